@@ -15,7 +15,18 @@
 package diff
 
 import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"iter"
+	"math"
+	"reflect"
 	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unsafe"
 
 	"znkr.io/diff/internal/config"
 	"znkr.io/diff/internal/impl"
@@ -28,9 +39,46 @@ import (
 type Op int
 
 const (
-	Match  Op = iota // Two slice elements match
-	Delete           // A deletion from an element on the left slice
-	Insert           // An insertion of an element from the right side
+	Match      Op = iota // Two slice elements match
+	Delete               // A deletion from an element on the left slice
+	Insert               // An insertion of an element from the right side
+	Modify               // Two slice elements with the same key that differ, see [Keyed]
+	Move                 // An element that was moved to a different position, see [DetectSwaps]
+	MoveModify           // An element of a block that was moved and modified, see [DetectModifiedMoves]
+)
+
+// IsChange reports whether op changes the input, i.e., whether it's anything other than a Match.
+func (op Op) IsChange() bool { return op != Match }
+
+// Prefix returns the prefix used for op in a unified diff: " " for Match, "-" for Delete, and "+"
+// for Insert. Modify has no representation in a unified diff and uses "!", like the context diff
+// format does for changed lines. Move and MoveModify use "~".
+func (op Op) Prefix() string {
+	switch op {
+	case Match:
+		return " "
+	case Delete:
+		return "-"
+	case Insert:
+		return "+"
+	case Modify:
+		return "!"
+	case Move, MoveModify:
+		return "~"
+	default:
+		panic("unknown op: " + op.String())
+	}
+}
+
+// Mode describes the algorithm that was used to compute a diff.
+//
+//go:generate go tool golang.org/x/tools/cmd/stringer -type=Mode
+type Mode int
+
+const (
+	ModeDefault Mode = iota // Default algorithm, see [Hunks]
+	ModeMinimal             // Minimal diff, see [Minimal]
+	ModeFast                // Fast diff, see [Fast]
 )
 
 // Edit describes a single edit of a diff.
@@ -41,19 +89,70 @@ const (
 //     position in the input and PosY is -1.
 //   - For Insert, Y contains the inserted element and X is unset (zero value). PosY contains its
 //     position in the input and PosX is -1.
+//   - For Modify, X and Y contain the elements that share a key but differ otherwise. PosX and
+//     PosY contain their respective positions in the input. Only [Keyed] and [KeyedFunc] produce
+//     this op.
+//   - For Move, the edit at the old position looks like a Delete and the edit at the new position
+//     looks like an Insert. Only [Edits] with [DetectSwaps] produces this op.
+//   - For MoveModify, the edits at the old position look like Deletes and the edits at the new
+//     position look like Inserts. Only [Edits] and [EditsWithMovedBlocks] with
+//     [DetectModifiedMoves] produce this op, use [EditsWithMovedBlocks] to get the edits within
+//     the moved blocks.
 type Edit[T any] struct {
 	Op         Op
 	PosX, PosY int
 	X, Y       T
 }
 
+// MovedBlock describes a block of elements that was moved and modified, see [DetectModifiedMoves].
+type MovedBlock[T any] struct {
+	PosX, EndX int       // Start and end position of the block in x.
+	PosY, EndY int       // Start and end position of the block in y.
+	Edits      []Edit[T] // Edits to transform x[PosX:EndX] to y[PosY:EndY]
+}
+
+// String formats e for debugging: The prefix of the op (see [Op.Prefix]) followed by the element
+// formatted with %v. For Modify, both elements are formatted as "x -> y".
+func (e Edit[T]) String() string {
+	var b strings.Builder
+	e.writeTo(&b)
+	return b.String()
+}
+
+func (e Edit[T]) writeTo(b *strings.Builder) {
+	b.WriteString(e.Op.Prefix())
+	switch {
+	case e.Op == Modify:
+		fmt.Fprintf(b, "%v -> %v", e.X, e.Y)
+	case e.PosX < 0:
+		fmt.Fprint(b, e.Y)
+	default:
+		fmt.Fprint(b, e.X)
+	}
+}
+
 // Hunk describes a sequence of consecutive edits.
+//
+// The edits are ordered by position: Every element in x[PosX:EndX] and y[PosY:EndY] is covered by
+// exactly one edit and the positions of these edits increase without gaps.
 type Hunk[T any] struct {
 	PosX, EndX int       // Start and end position in x.
 	PosY, EndY int       // Start and end position in y.
 	Edits      []Edit[T] // Edits to transform x[PosX:EndX] to y[PosY:EndY]
 }
 
+// String formats h in a compact, unified diff like format for debugging: A header line followed
+// by one line per edit, see [Edit.String]. Positions in the header are one-based.
+func (h Hunk[T]) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", h.PosX+1, h.EndX-h.PosX, h.PosY+1, h.EndY-h.PosY)
+	for _, e := range h.Edits {
+		e.writeTo(&b)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
 // Hunks compares the contents of x and y and returns the changes necessary to convert from one to
 // the other.
 //
@@ -63,16 +162,41 @@ type Hunk[T any] struct {
 //
 // If x and y are identical, the output has length zero.
 //
-// The following options are supported: [Context], [Minimal], [Fast]
+// The following options are supported: [Context], [Minimal], [PreferLongMatches], [CostLimit],
+// [GoodDiagonalTuning], [Fast], [AutoFast], [Parallel], [PairedOrdering], [MaxHunks]
 //
 // Important: The output is not guaranteed to be stable and may change with minor version upgrades.
 // DO NOT rely on the output being stable.
 func Hunks[T comparable](x, y []T, opts ...Option) []Hunk[T] {
-	cfg := config.FromOptions(opts, config.Context|config.Minimal|config.Fast)
+	cfg := config.FromOptions(opts, diffFlags|hunkFlags)
 	rx, ry := impl.Diff(x, y, cfg)
 	return hunks(x, y, rx, ry, cfg)
 }
 
+// HunksTruncated is like [Hunks], but additionally returns the number of hunks that were omitted
+// because of [MaxHunks].
+//
+// The following options are supported: [Context], [Minimal], [PreferLongMatches], [CostLimit],
+// [GoodDiagonalTuning], [Fast], [AutoFast], [Parallel], [PairedOrdering], [MaxHunks]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func HunksTruncated[T comparable](x, y []T, opts ...Option) ([]Hunk[T], int) {
+	cfg := config.FromOptions(opts, diffFlags|hunkFlags)
+	rx, ry := impl.Diff(x, y, cfg)
+	hout := hunks(x, y, rx, ry, cfg)
+	if cfg.MaxHunks == 0 || len(hout) < cfg.MaxHunks {
+		return hout, 0
+	}
+	// Count all hunks, without computing their edits.
+	cfg.MaxHunks = 0
+	total := 0
+	for range rvecs.Hunks(rx, ry, cfg) {
+		total++
+	}
+	return hout, total - len(hout)
+}
+
 // HunksFunc compares the contents of x and y using the provided equality comparison and returns the
 // changes necessary to convert from one to the other.
 //
@@ -82,18 +206,73 @@ func Hunks[T comparable](x, y []T, opts ...Option) []Hunk[T] {
 //
 // If x and y are identical, the output has length zero.
 //
-// The following options are supported: [Context], [Minimal]
+// The following options are supported: [Context], [Minimal], [PreferLongMatches], [CostLimit],
+// [GoodDiagonalTuning], [PairedOrdering], [MaxHunks]
 //
 // Note that this function has generally worse performance than [Hunks] for diffs with many changes.
 //
 // Important: The output is not guaranteed to be stable and may change with minor version upgrades.
 // DO NOT rely on the output being stable.
 func HunksFunc[T any](x, y []T, eq func(a, b T) bool, opts ...Option) []Hunk[T] {
-	cfg := config.FromOptions(opts, config.Context|config.Minimal)
+	cfg := config.FromOptions(opts, funcFlags|hunkFlags)
 	rx, ry := impl.DiffFunc(x, y, eq, cfg)
 	return hunks(x, y, rx, ry, cfg)
 }
 
+// Trace describes how a diff was computed. It's intended for debugging and tuning.
+type Trace struct {
+	// EditDistance is the number of deletions and insertions in the diff.
+	EditDistance int
+
+	// HeuristicFired is set if a heuristic was applied to speed up the computation. If it's not
+	// set, the diff is minimal.
+	HeuristicFired bool
+
+	// Mode is the algorithm that was used to compute the diff. This can be different from the
+	// configured mode, e.g. when using [AutoFast].
+	Mode Mode
+}
+
+// HunksWithTrace is like [Hunks], but additionally returns a [Trace] that describes how the diff
+// was computed.
+//
+// The following options are supported: [Context], [Minimal], [PreferLongMatches], [CostLimit],
+// [GoodDiagonalTuning], [Fast], [AutoFast], [Parallel], [PairedOrdering], [MaxHunks]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func HunksWithTrace[T comparable](x, y []T, opts ...Option) ([]Hunk[T], Trace) {
+	cfg := config.FromOptions(opts, diffFlags|hunkFlags)
+	rx, ry, stats := impl.DiffWithStats(x, y, cfg)
+	trace := Trace{
+		HeuristicFired: stats.Mode == config.ModeFast || stats.Anchoring || stats.GoodDiagonal || stats.TooExpensive,
+		Mode:           Mode(stats.Mode),
+	}
+	for _, r := range [][]bool{rx, ry} {
+		for _, v := range r {
+			if v {
+				trace.EditDistance++
+			}
+		}
+	}
+	return hunks(x, y, rx, ry, cfg), trace
+}
+
+// HunksOptimalReport is like [Hunks], but additionally reports whether the diff is provably
+// minimal. This is the case if no heuristic was applied to limit the runtime, e.g., because the
+// inputs are small or have few differences, or if [Minimal] was used. It's the opposite of
+// [Trace.HeuristicFired], use [HunksWithTrace] to find out more about how the diff was computed.
+//
+// The following options are supported: [Context], [Minimal], [PreferLongMatches], [CostLimit],
+// [GoodDiagonalTuning], [Fast], [AutoFast], [Parallel], [PairedOrdering], [MaxHunks]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func HunksOptimalReport[T comparable](x, y []T, opts ...Option) ([]Hunk[T], bool) {
+	hout, trace := HunksWithTrace(x, y, opts...)
+	return hout, !trace.HeuristicFired
+}
+
 func hunks[T any](x, y []T, rx, ry []bool, cfg config.Config) []Hunk[T] {
 	// Compute the number of hunks and edits, this is relatively cheap and allows us to preallocate
 	// the return values.
@@ -109,26 +288,56 @@ func hunks[T any](x, y []T, rx, ry []bool, cfg config.Config) []Hunk[T] {
 	eout := make([]Edit[T], 0, nedits)
 	hout := make([]Hunk[T], 0, nhunks)
 	for hunk := range rvecs.Hunks(rx, ry, cfg) {
-		for s, t := hunk.S0, hunk.T0; s < hunk.S1 || t < hunk.T1; {
-			for s < hunk.S1 && rx[s] {
+		eout = appendHunkEdits(eout, x, y, rx, ry, hunk, cfg)
+		hout = append(hout, makeHunk(hunk, slices.Clip(eout)))
+		eout = eout[len(eout):]
+	}
+	return hout
+}
+
+// HunksSeq is like [Hunks], but returns an iterator that computes the diff when it's used and
+// yields the hunks one by one. This avoids allocating all hunks at once if the hunks are processed
+// incrementally. The yielded hunks are the same as the ones returned by [Hunks].
+//
+// The following options are supported: [Context], [Minimal], [PreferLongMatches], [CostLimit],
+// [GoodDiagonalTuning], [Fast], [AutoFast], [Parallel], [PairedOrdering], [MaxHunks]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func HunksSeq[T comparable](x, y []T, opts ...Option) iter.Seq[Hunk[T]] {
+	cfg := config.FromOptions(opts, diffFlags|hunkFlags)
+	return func(yield func(Hunk[T]) bool) {
+		rx, ry := impl.Diff(x, y, cfg)
+		for hunk := range rvecs.Hunks(rx, ry, cfg) {
+			edits := appendHunkEdits(make([]Edit[T], 0, hunk.Edits), x, y, rx, ry, hunk, cfg)
+			if !yield(makeHunk(hunk, edits)) {
+				return
+			}
+		}
+	}
+}
+
+// appendHunkEdits appends the edits of hunk to eout.
+func appendHunkEdits[T any](eout []Edit[T], x, y []T, rx, ry []bool, hunk rvecs.Hunk, cfg config.Config) []Edit[T] {
+	for r := range rvecs.Runs(rx, ry, hunk, cfg.PairedOrdering) {
+		for i := range r.N {
+			s, t := r.S+i, r.T+i
+			switch r.Op {
+			case rvecs.Delete:
 				eout = append(eout, Edit[T]{
 					Op:   Delete,
 					X:    x[s],
 					PosX: s,
 					PosY: -1,
 				})
-				s++
-			}
-			for t < hunk.T1 && ry[t] {
+			case rvecs.Insert:
 				eout = append(eout, Edit[T]{
 					Op:   Insert,
 					Y:    y[t],
 					PosX: -1,
 					PosY: t,
 				})
-				t++
-			}
-			for s < hunk.S1 && t < hunk.T1 && !rx[s] && !ry[t] {
+			case rvecs.Match:
 				eout = append(eout, Edit[T]{
 					Op:   Match,
 					X:    x[s],
@@ -136,20 +345,72 @@ func hunks[T any](x, y []T, rx, ry []bool, cfg config.Config) []Hunk[T] {
 					PosX: s,
 					PosY: t,
 				})
-				s++
-				t++
 			}
 		}
-		hout = append(hout, Hunk[T]{
-			PosX:  hunk.S0,
-			EndX:  hunk.S1,
-			PosY:  hunk.T0,
-			EndY:  hunk.T1,
-			Edits: slices.Clip(eout),
-		})
-		eout = eout[len(eout):]
 	}
-	return hout
+	return eout
+}
+
+func makeHunk[T any](hunk rvecs.Hunk, edits []Edit[T]) Hunk[T] {
+	return Hunk[T]{
+		PosX:  hunk.S0,
+		EndX:  hunk.S1,
+		PosY:  hunk.T0,
+		EndY:  hunk.T1,
+		Edits: edits,
+	}
+}
+
+// NextChange returns the first change in hunks at or after position posX in x. The change is
+// hunks[hunkIdx].Edits[editIdx]. If there is no such change, ok is false.
+//
+// A change is located at the position of the element in x it changes. An insertion is located at
+// the position of the element in x it's inserted in front of. NextChange and [PrevChange] partition
+// the changes: Every change is either found by NextChange or by PrevChange for the same posX.
+//
+// The hunks must be ordered by position, as returned by [Hunks] or [HunksFunc].
+func NextChange[T any](hunks []Hunk[T], posX int) (hunkIdx, editIdx int, ok bool) {
+	// Positions of changes in a hunk are within [PosX, EndX], skip all hunks that end before posX.
+	start := sort.Search(len(hunks), func(i int) bool { return hunks[i].EndX >= posX })
+	for i := start; i < len(hunks); i++ {
+		h := hunks[i]
+		cur := h.PosX
+		for j, e := range h.Edits {
+			pos := cur
+			if e.PosX >= 0 {
+				pos = e.PosX
+				cur = e.PosX + 1
+			}
+			if e.Op.IsChange() && pos >= posX {
+				return i, j, true
+			}
+		}
+	}
+	return -1, -1, false
+}
+
+// PrevChange returns the last change in hunks before position posX in x. The change is
+// hunks[hunkIdx].Edits[editIdx]. If there is no such change, ok is false.
+//
+// See [NextChange] for how the position of a change is defined.
+func PrevChange[T any](hunks []Hunk[T], posX int) (hunkIdx, editIdx int, ok bool) {
+	// Positions of changes in a hunk are within [PosX, EndX], skip all hunks that start at or after
+	// posX.
+	end := sort.Search(len(hunks), func(i int) bool { return hunks[i].PosX >= posX })
+	for i := end - 1; i >= 0; i-- {
+		h := hunks[i]
+		cur := h.EndX
+		for j := len(h.Edits) - 1; j >= 0; j-- {
+			e := h.Edits[j]
+			if e.PosX >= 0 {
+				cur = e.PosX
+			}
+			if e.Op.IsChange() && cur < posX {
+				return i, j, true
+			}
+		}
+	}
+	return -1, -1, false
 }
 
 // Edits compares the contents of x and y and returns the changes necessary to convert from one to
@@ -158,14 +419,248 @@ func hunks[T any](x, y []T, rx, ry []bool, cfg config.Config) []Hunk[T] {
 // Edits returns one edit for every element in the input slices. If x and y are identical, the
 // output will consist of a match edit for every input element.
 //
-// The following option is supported: [Minimal], [Fast]
+// Elements are compared using ==. For floating point numbers, this means that NaN is not equal to
+// anything, including itself, and every NaN is reported as a change. Use [EditsFloat] to compare
+// floating point numbers instead.
+//
+// The following options are supported: [Minimal], [PreferLongMatches], [CostLimit],
+// [GoodDiagonalTuning], [Fast], [AutoFast], [Parallel], [DetectSwaps], [DetectModifiedMoves]
 //
 // Important: The output is not guaranteed to be stable and may change with minor version upgrades.
 // DO NOT rely on the output being stable.
 func Edits[T comparable](x, y []T, opts ...Option) []Edit[T] {
-	cfg := config.FromOptions(opts, config.Minimal|config.Fast)
+	eout, _ := editsWithMoves(x, y, opts)
+	return eout
+}
+
+// Hash returns a 64-bit hash of the edits that [Edits] returns for the same inputs and options,
+// e.g., to use as a cache key for a rendered diff without serializing the diff itself. The hash
+// covers the op of every edit and the element it refers to.
+//
+// The hash is the 64-bit FNV-1a hash of the following encoding of the edits: For every edit, the op
+// as a single byte, followed by the element from x for deletions and matches and from y otherwise.
+// Strings are encoded as their length as a uvarint followed by their bytes, integers are encoded as
+// 8 bytes in little-endian byte order. The hash is therefore the same across processes and
+// platforms and can be persisted.
+//
+// Hash only supports strings and integers, not all comparable types: Pointers, channels, and
+// interfaces have no encoding that is the same across processes, floating point numbers that are
+// equal can have different encodings (0 and -0), and structs and arrays would have to be encoded
+// field by field using reflection.
+//
+// The hash is deterministic for a given pair of inputs and options, but like the diff itself, it
+// depends on the options: Changing the mode, e.g., with [Minimal] or [Fast], can change the diff
+// and therefore the hash.
+//
+// The following options are supported: [Minimal], [PreferLongMatches], [CostLimit],
+// [GoodDiagonalTuning], [Fast], [AutoFast], [Parallel]
+//
+// Important: The hash is not guaranteed to be stable across minor version upgrades, because the
+// diff itself isn't.
+func Hash[T ~string | ~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr](x, y []T, opts ...Option) uint64 {
+	cfg := config.FromOptions(opts, diffFlags)
 	rx, ry := impl.Diff(x, y, cfg)
-	return edits(x, y, rx, ry)
+	// Select the encoding once, by the underlying type of T. The elements are then encoded without
+	// reflection.
+	switch reflect.TypeFor[T]().Kind() {
+	case reflect.String:
+		return hashEdits(underlying[string](x), underlying[string](y), rx, ry, func(buf []byte, v string) []byte {
+			buf = binary.AppendUvarint(buf, uint64(len(v)))
+			return append(buf, v...)
+		})
+	case reflect.Int:
+		return hashInts[int](x, y, rx, ry)
+	case reflect.Int8:
+		return hashInts[int8](x, y, rx, ry)
+	case reflect.Int16:
+		return hashInts[int16](x, y, rx, ry)
+	case reflect.Int32:
+		return hashInts[int32](x, y, rx, ry)
+	case reflect.Int64:
+		return hashInts[int64](x, y, rx, ry)
+	case reflect.Uint:
+		return hashInts[uint](x, y, rx, ry)
+	case reflect.Uint8:
+		return hashInts[uint8](x, y, rx, ry)
+	case reflect.Uint16:
+		return hashInts[uint16](x, y, rx, ry)
+	case reflect.Uint32:
+		return hashInts[uint32](x, y, rx, ry)
+	case reflect.Uint64:
+		return hashInts[uint64](x, y, rx, ry)
+	case reflect.Uintptr:
+		return hashInts[uintptr](x, y, rx, ry)
+	default:
+		panic("never reached")
+	}
+}
+
+// hashInts is [hashEdits] for inputs whose underlying element type is the integer type E.
+func hashInts[E int | int8 | int16 | int32 | int64 | uint | uint8 | uint16 | uint32 | uint64 | uintptr, T any](x, y []T, rx, ry []bool) uint64 {
+	return hashEdits(underlying[E](x), underlying[E](y), rx, ry, func(buf []byte, v E) []byte {
+		return binary.LittleEndian.AppendUint64(buf, uint64(v))
+	})
+}
+
+// hashEdits returns the hash of the edits described by rx and ry, see [Hash]. appendElem appends
+// the encoding of an element to buf.
+func hashEdits[E any](x, y []E, rx, ry []bool, appendElem func(buf []byte, v E) []byte) uint64 {
+	h := fnv.New64a()
+	var buf []byte
+	walkEdits(x, y, rx, ry, func(e Edit[E]) bool {
+		v := e.X
+		if e.Op == Insert {
+			v = e.Y
+		}
+		buf = appendElem(append(buf[:0], byte(e.Op)), v)
+		h.Write(buf)
+		return true
+	})
+	return h.Sum64()
+}
+
+// underlying reinterprets s as a slice of U. The underlying type of T must be U.
+func underlying[U, T any](s []T) []U {
+	return unsafe.Slice((*U)(unsafe.Pointer(unsafe.SliceData(s))), len(s))
+}
+
+// EditsWithMovedBlocks is like [Edits], but additionally returns the blocks that are reported as
+// moved and modified, in the order of their position in y. Every block contains the edits to
+// transform the block in x to the block in y. Without [DetectModifiedMoves], the blocks are always
+// nil.
+//
+// The following options are supported: [Minimal], [PreferLongMatches], [CostLimit],
+// [GoodDiagonalTuning], [Fast], [AutoFast], [Parallel], [DetectSwaps], [DetectModifiedMoves]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func EditsWithMovedBlocks[T comparable](x, y []T, opts ...Option) ([]Edit[T], []MovedBlock[T]) {
+	return editsWithMoves(x, y, opts)
+}
+
+func editsWithMoves[T comparable](x, y []T, opts []Option) ([]Edit[T], []MovedBlock[T]) {
+	cfg := config.FromOptions(opts, diffFlags|config.DetectSwaps|config.DetectModifiedMoves)
+	rx, ry := impl.Diff(x, y, cfg)
+	eout := edits(x, y, rx, ry)
+	if cfg.DetectSwaps {
+		detectSwaps(eout)
+	}
+	var blocks []MovedBlock[T]
+	if cfg.ModifiedMovesThreshold > 0 {
+		blocks = detectModifiedMoves(x, y, eout, cfg)
+	}
+	return eout, blocks
+}
+
+// detectSwaps changes deletions and insertions of identical elements to moves.
+func detectSwaps[T comparable](edits []Edit[T]) {
+	deleted := make(map[T][]int)
+	for i, edit := range edits {
+		if edit.Op == Delete {
+			deleted[edit.X] = append(deleted[edit.X], i)
+		}
+	}
+	if len(deleted) == 0 {
+		return
+	}
+	for i, edit := range edits {
+		if edit.Op != Insert {
+			continue
+		}
+		if idx := deleted[edit.Y]; len(idx) > 0 {
+			edits[idx[0]].Op = Move
+			edits[i].Op = Move
+			deleted[edit.Y] = idx[1:]
+		}
+	}
+}
+
+// maxModifiedMovePairs is the maximum number of pairs of runs that detectModifiedMoves compares
+// with a secondary diff.
+const maxModifiedMovePairs = 10_000
+
+// detectModifiedMoves changes runs of deletions and insertions that are similar enough to moved and
+// modified blocks and returns these blocks, see [DetectModifiedMoves].
+func detectModifiedMoves[T comparable](x, y []T, eout []Edit[T], cfg config.Config) []MovedBlock[T] {
+	// Collect the runs of deletions and insertions as ranges of edits.
+	type run struct{ i0, i1 int }
+	var dels, ins []run
+	for i := 0; i < len(eout); {
+		op := eout[i].Op
+		j := i + 1
+		for j < len(eout) && eout[j].Op == op {
+			j++
+		}
+		switch op {
+		case Delete:
+			dels = append(dels, run{i, j})
+		case Insert:
+			ins = append(ins, run{i, j})
+		}
+		i = j
+	}
+	if len(dels) == 0 || len(ins) == 0 {
+		return nil
+	}
+
+	// The secondary diff uses the same mode, but none of the options that only affect the output.
+	scfg := config.Default
+	scfg.Mode = cfg.Mode
+	paired := make([]bool, len(dels))
+	compared := 0
+	var blocks []MovedBlock[T]
+	for _, in := range ins {
+		y0, y1 := eout[in.i0].PosY, eout[in.i1-1].PosY+1
+		best, bestSim := -1, cfg.ModifiedMovesThreshold
+		var bestRx, bestRy []bool
+		for k, del := range dels {
+			if paired[k] || del.i1 == in.i0 {
+				continue // Already used or a modification in place.
+			}
+			x0, x1 := eout[del.i0].PosX, eout[del.i1-1].PosX+1
+			if maxSim := 2 * float64(min(x1-x0, y1-y0)) / float64(x1-x0+y1-y0); maxSim < bestSim || best >= 0 && maxSim == bestSim {
+				continue // The runs are too different in length to be a better pair.
+			}
+			if compared == maxModifiedMovePairs {
+				break
+			}
+			compared++
+			rx, ry := impl.Diff(x[x0:x1], y[y0:y1], scfg)
+			var matches int
+			for _, r := range rx[:x1-x0] {
+				if !r {
+					matches++
+				}
+			}
+			if sim := 2 * float64(matches) / float64(x1-x0+y1-y0); sim > bestSim || best < 0 && sim == bestSim {
+				best, bestSim, bestRx, bestRy = k, sim, rx, ry
+			}
+		}
+		if best < 0 {
+			continue
+		}
+		paired[best] = true
+		del := dels[best]
+		x0, x1 := eout[del.i0].PosX, eout[del.i1-1].PosX+1
+		block := MovedBlock[T]{PosX: x0, EndX: x1, PosY: y0, EndY: y1}
+		block.Edits = edits(x[x0:x1], y[y0:y1], bestRx, bestRy)
+		for i := range block.Edits {
+			e := &block.Edits[i]
+			if e.PosX >= 0 {
+				e.PosX += x0
+			}
+			if e.PosY >= 0 {
+				e.PosY += y0
+			}
+		}
+		blocks = append(blocks, block)
+		for _, r := range []run{del, in} {
+			for i := r.i0; i < r.i1; i++ {
+				eout[i].Op = MoveModify
+			}
+		}
+	}
+	return blocks
 }
 
 // EditsFunc compares the contents of x and y using the provided equality comparison and returns the
@@ -174,18 +669,82 @@ func Edits[T comparable](x, y []T, opts ...Option) []Edit[T] {
 // EditsFunc returns edits for every element in the input. If both x and y are identical, the output
 // will consist of a match edit for every input element.
 //
-// The following option is supported: [Minimal]
+// The following options are supported: [Minimal], [PreferLongMatches], [CostLimit],
+// [GoodDiagonalTuning]
 //
 // Note that this function has generally worse performance than [Edits] for diffs with many changes.
 //
 // Important: The output is not guaranteed to be stable and may change with minor version upgrades.
 // DO NOT rely on the output being stable.
 func EditsFunc[T any](x, y []T, eq func(a, b T) bool, opts ...Option) []Edit[T] {
-	cfg := config.FromOptions(opts, config.Minimal)
+	cfg := config.FromOptions(opts, funcFlags)
 	rx, ry := impl.DiffFunc(x, y, eq, cfg)
 	return edits(x, y, rx, ry)
 }
 
+// EditsFloat is like [Edits] for floating point numbers, but treats NaN values as equal to each
+// other. Like with ==, -0 and +0 are equal, too. Use [FloatTolerance] to treat values that are
+// almost equal as equal.
+//
+// The following options are supported: [Minimal], [PreferLongMatches], [CostLimit],
+// [GoodDiagonalTuning], [Fast], [AutoFast], [Parallel], [FloatTolerance]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func EditsFloat[T ~float32 | ~float64](x, y []T, opts ...Option) []Edit[T] {
+	cfg := config.FromOptions(opts, diffFlags|config.FloatTolerance)
+	if eps := cfg.FloatTolerance; eps > 0 {
+		eq := func(a, b T) bool {
+			return a == b || a != a && b != b || math.Abs(float64(a)-float64(b)) <= eps
+		}
+		rx, ry := impl.DiffFunc(x, y, eq, cfg)
+		return edits(x, y, rx, ry)
+	}
+	rx, ry := impl.Diff(floatKeys(x), floatKeys(y), cfg)
+	return edits(x, y, rx, ry)
+}
+
+// floatKeys returns keys for v that are equal if and only if the values are equal or both NaN.
+func floatKeys[T ~float32 | ~float64](v []T) []uint64 {
+	keys := make([]uint64, len(v))
+	for i, f := range v {
+		switch {
+		case f != f:
+			keys[i] = math.Float64bits(math.NaN())
+		case f == 0:
+			keys[i] = 0 // -0 == +0
+		default:
+			keys[i] = math.Float64bits(float64(f))
+		}
+	}
+	return keys
+}
+
+// ErrMaxWork is returned by [EditsFuncChecked] if the work limit set with [MaxWork] was exceeded.
+var ErrMaxWork = errors.New("diff: work limit exceeded")
+
+// EditsFuncChecked is like [EditsFunc], but supports limiting the work spent on the comparison
+// using [MaxWork]. This is useful to bound the runtime for untrusted inputs.
+//
+// If the work limit is exceeded, EditsFuncChecked returns a partial result together with
+// [ErrMaxWork]. The partial result is a valid diff, i.e., it converts x to y, but all elements that
+// weren't compared before the limit was exceeded are reported as deleted or inserted.
+//
+// The following options are supported: [Minimal], [PreferLongMatches], [CostLimit],
+// [GoodDiagonalTuning], [MaxWork]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func EditsFuncChecked[T any](x, y []T, eq func(a, b T) bool, opts ...Option) ([]Edit[T], error) {
+	cfg := config.FromOptions(opts, funcFlags|config.MaxWork)
+	rx, ry, exceeded := impl.DiffFuncChecked(x, y, eq, cfg)
+	eout := edits(x, y, rx, ry)
+	if exceeded {
+		return eout, ErrMaxWork
+	}
+	return eout, nil
+}
+
 func edits[T any](x, y []T, rx, ry []bool) []Edit[T] {
 	// Compute the number of edits, this is relatively cheap and allows us to preallocate the return
 	// value.
@@ -244,3 +803,467 @@ func edits[T any](x, y []T, rx, ry []bool) []Edit[T] {
 	}
 	return eout
 }
+
+// ApplyEdits applies edits to x and returns the result. It's the inverse of [Edits]:
+// ApplyEdits(x, Edits(x, y)) returns a copy of y.
+//
+// The edits must describe a conversion of all of x, like the edits returned by [Edits] or [Keyed]:
+// Every element of x must be referenced by one Match, Delete, Modify, or Move edit, in order.
+// Matches are copied from x, deletions are skipped, and insertions and modifications are copied
+// from the edits. An error is returned if an edit doesn't match the corresponding element in x or
+// if the edits don't cover x completely.
+func ApplyEdits[T comparable](x []T, edits []Edit[T]) ([]T, error) {
+	return ApplyEditsFunc(x, edits, func(a, b T) bool { return a == b })
+}
+
+// ApplyEditsFunc is like [ApplyEdits] but uses the provided equality comparison to verify that the
+// edits match x.
+func ApplyEditsFunc[T any](x []T, edits []Edit[T], eq func(a, b T) bool) ([]T, error) {
+	out := make([]T, 0, len(edits))
+	s := 0 // Index of the next element in x.
+	for i, e := range edits {
+		fromX := e.Op == Match || e.Op == Delete || e.Op == Modify || (e.Op == Move || e.Op == MoveModify) && e.PosX >= 0
+		if !fromX {
+			out = append(out, e.Y)
+			continue
+		}
+		if s >= len(x) {
+			return nil, fmt.Errorf("diff: edit %d refers to element %d of x, but x has only %d elements", i, s, len(x))
+		}
+		if !eq(x[s], e.X) {
+			return nil, fmt.Errorf("diff: edit %d doesn't match element %d of x", i, s)
+		}
+		switch e.Op {
+		case Match:
+			out = append(out, x[s])
+		case Modify:
+			out = append(out, e.Y)
+		}
+		s++
+	}
+	if s != len(x) {
+		return nil, fmt.Errorf("diff: edits only cover %d of %d elements of x", s, len(x))
+	}
+	return out, nil
+}
+
+// Keyed compares x and y by the keys returned by key and returns one edit for every element in the
+// input slices.
+//
+// Unlike [Edits], the alignment is driven by the keys alone: Two elements with the same key are
+// aligned even if they differ otherwise. Aligned elements that are equal are reported as Match,
+// aligned elements that differ are reported as Modify.
+//
+// The following options are supported: [Minimal], [PreferLongMatches], [CostLimit],
+// [GoodDiagonalTuning], [Fast], [AutoFast], [Parallel]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func Keyed[T, K comparable](x, y []T, key func(T) K, opts ...Option) []Edit[T] {
+	return KeyedFunc(x, y, key, func(a, b T) bool { return a == b }, opts...)
+}
+
+// KeyedFunc is like [Keyed] but uses the provided equality comparison to decide whether two aligned
+// elements are reported as Match or Modify.
+//
+// The following options are supported: [Minimal], [PreferLongMatches], [CostLimit],
+// [GoodDiagonalTuning], [Fast], [AutoFast], [Parallel]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func KeyedFunc[T any, K comparable](x, y []T, key func(T) K, eq func(a, b T) bool, opts ...Option) []Edit[T] {
+	cfg := config.FromOptions(opts, diffFlags)
+	kx, ky := keys(x, key), keys(y, key)
+	rx, ry := impl.Diff(kx, ky, cfg)
+	eout := edits(x, y, rx, ry)
+	for i := range eout {
+		if e := &eout[i]; e.Op == Match && !eq(e.X, e.Y) {
+			e.Op = Modify
+		}
+	}
+	return eout
+}
+
+// EditsProject compares x and y by the projections of their elements returned by project and
+// returns one edit for every element in the input slices. The edits carry the original elements.
+//
+// Elements with equal projections are reported as Match, even if they differ otherwise. This
+// allows to compare elements by a single field, e.g. the message of a log entry, while keeping
+// the full elements for rendering. Unlike [EditsFunc], the projections are compared like the
+// elements in [Edits], which is a lot faster for inputs with many differences. Use [Keyed] instead
+// to report aligned elements that differ as Modify.
+//
+// The following options are supported: [Minimal], [PreferLongMatches], [CostLimit],
+// [GoodDiagonalTuning], [Fast], [AutoFast], [Parallel]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func EditsProject[T any, K comparable](x, y []T, project func(T) K, opts ...Option) []Edit[T] {
+	cfg := config.FromOptions(opts, diffFlags)
+	rx, ry := impl.Diff(keys(x, project), keys(y, project), cfg)
+	return edits(x, y, rx, ry)
+}
+
+func keys[T any, K comparable](in []T, key func(T) K) []K {
+	out := make([]K, len(in))
+	for i, v := range in {
+		out[i] = key(v)
+	}
+	return out
+}
+
+// WalkEdits compares the contents of x and y and calls fn for every edit necessary to convert from
+// one to the other. Walking stops early if fn returns false.
+//
+// WalkEdits produces the same edits as [Edits], but avoids allocating the edits slice. This is
+// useful for hot paths that process each edit only once.
+//
+// The following options are supported: [Minimal], [PreferLongMatches], [CostLimit],
+// [GoodDiagonalTuning], [Fast], [AutoFast], [Parallel]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func WalkEdits[T comparable](x, y []T, fn func(Edit[T]) bool, opts ...Option) {
+	cfg := config.FromOptions(opts, diffFlags)
+	rx, ry := impl.Diff(x, y, cfg)
+	walkEdits(x, y, rx, ry, fn)
+}
+
+func walkEdits[T any](x, y []T, rx, ry []bool, fn func(Edit[T]) bool) {
+	n, m := len(rx)-1, len(ry)-1
+	for s, t := 0, 0; s < n || t < m; {
+		for s < n && rx[s] {
+			if !fn(Edit[T]{Op: Delete, X: x[s], PosX: s, PosY: -1}) {
+				return
+			}
+			s++
+		}
+		for t < m && ry[t] {
+			if !fn(Edit[T]{Op: Insert, Y: y[t], PosX: -1, PosY: t}) {
+				return
+			}
+			t++
+		}
+		for s < n && t < m && !rx[s] && !ry[t] {
+			if !fn(Edit[T]{Op: Match, X: x[s], Y: y[t], PosX: s, PosY: t}) {
+				return
+			}
+			s++
+			t++
+		}
+	}
+}
+
+// Run describes a maximal run of consecutive edits with the same op.
+//
+//   - For Match, X and Y contain the matching elements. PosX and PosY contain the position of the
+//     first element in the respective input.
+//   - For Delete, X contains the deleted elements and Y is nil. PosX contains the position of the
+//     first element in the input and PosY is -1.
+//   - For Insert, Y contains the inserted elements and X is nil. PosY contains the position of the
+//     first element in the input and PosX is -1.
+//
+// X and Y are subslices of the inputs and must not be modified.
+type Run[T any] struct {
+	Op         Op
+	PosX, PosY int
+	X, Y       []T
+}
+
+// Runs compares the contents of x and y and returns the changes necessary to convert from one to
+// the other as runs of consecutive edits with the same op.
+//
+// Runs describes the same changes as [Edits], but groups consecutive edits with the same op into a
+// single [Run]. This is a lot more compact than the output of [Edits] for inputs with many small
+// changes, e.g., binary data. If x and y are identical, the output is a single match run.
+//
+// The following options are supported: [Minimal], [PreferLongMatches], [CostLimit],
+// [GoodDiagonalTuning], [Fast], [AutoFast], [Parallel]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func Runs[T comparable](x, y []T, opts ...Option) []Run[T] {
+	cfg := config.FromOptions(opts, diffFlags)
+	rx, ry := impl.Diff(x, y, cfg)
+	return runs(x, y, rx, ry)
+}
+
+func runs[T any](x, y []T, rx, ry []bool) []Run[T] {
+	// Count the runs first to preallocate the return value, see edits.
+	n, m := len(rx)-1, len(ry)-1
+	var nruns int
+	for s, t := 0, 0; s < n || t < m; {
+		if s < n && rx[s] {
+			nruns++
+			for s < n && rx[s] {
+				s++
+			}
+		}
+		if t < m && ry[t] {
+			nruns++
+			for t < m && ry[t] {
+				t++
+			}
+		}
+		if s < n && t < m && !rx[s] && !ry[t] {
+			nruns++
+			for s < n && t < m && !rx[s] && !ry[t] {
+				s++
+				t++
+			}
+		}
+	}
+	if nruns == 0 {
+		return nil
+	}
+
+	rout := make([]Run[T], 0, nruns)
+	for s, t := 0, 0; s < n || t < m; {
+		if s0 := s; s < n && rx[s] {
+			for s < n && rx[s] {
+				s++
+			}
+			rout = append(rout, Run[T]{Op: Delete, X: x[s0:s], PosX: s0, PosY: -1})
+		}
+		if t0 := t; t < m && ry[t] {
+			for t < m && ry[t] {
+				t++
+			}
+			rout = append(rout, Run[T]{Op: Insert, Y: y[t0:t], PosX: -1, PosY: t0})
+		}
+		if s0, t0 := s, t; s < n && t < m && !rx[s] && !ry[t] {
+			for s < n && t < m && !rx[s] && !ry[t] {
+				s++
+				t++
+			}
+			rout = append(rout, Run[T]{Op: Match, X: x[s0:s], Y: y[t0:t], PosX: s0, PosY: t0})
+		}
+	}
+	return rout
+}
+
+// OpString compares the contents of x and y and returns the edits as a string with one letter per
+// edit: "M" for Match, "D" for Delete, and "I" for Insert. For example, the edits to convert
+// "ABCABBA" to "CBABAC" are "DIMDMMDMI".
+//
+// This compact representation is useful for debugging and for snapshot tests.
+//
+// The following options are supported: [Minimal], [PreferLongMatches], [CostLimit],
+// [GoodDiagonalTuning], [Fast], [AutoFast], [Parallel]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func OpString[T comparable](x, y []T, opts ...Option) string {
+	cfg := config.FromOptions(opts, diffFlags)
+	rx, ry := impl.Diff(x, y, cfg)
+	var b strings.Builder
+	b.Grow(len(x) + len(y))
+	walkEdits(x, y, rx, ry, func(e Edit[T]) bool {
+		b.WriteByte(opLetter(e.Op))
+		return true
+	})
+	return b.String()
+}
+
+// OpStringRLE is like [OpString], but run-length encodes the edits. Runs are separated by a space
+// and runs longer than one edit are followed by their length. For example, the edits to convert
+// "ABCABBA" to "CBABAC" are "D I M D M2 D M I".
+//
+// The following options are supported: [Minimal], [PreferLongMatches], [CostLimit],
+// [GoodDiagonalTuning], [Fast], [AutoFast], [Parallel]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func OpStringRLE[T comparable](x, y []T, opts ...Option) string {
+	cfg := config.FromOptions(opts, diffFlags)
+	rx, ry := impl.Diff(x, y, cfg)
+	var b strings.Builder
+	for i, r := range runs(x, y, rx, ry) {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteByte(opLetter(r.Op))
+		if n := max(len(r.X), len(r.Y)); n > 1 {
+			b.WriteString(strconv.Itoa(n))
+		}
+	}
+	return b.String()
+}
+
+// opLetter returns the letter used for op by [OpString].
+func opLetter(op Op) byte {
+	switch op {
+	case Match:
+		return 'M'
+	case Delete:
+		return 'D'
+	case Insert:
+		return 'I'
+	default:
+		panic("unsupported op: " + op.String())
+	}
+}
+
+// AlignRow is a row in a side-by-side alignment of two inputs, see [Align].
+//
+//   - For Match, X and Y point to the matching elements.
+//   - For Delete, X points to the deleted element and Y is nil.
+//   - For Insert, Y points to the inserted element and X is nil.
+//
+// X and Y point into the inputs and must not be used to modify them.
+type AlignRow[T any] struct {
+	X, Y *T
+	Op   Op
+}
+
+// Align compares the contents of x and y and returns them aligned row by row, e.g., to render a
+// side-by-side view of a diff in two columns. A side without an element in a row is nil.
+//
+// Align describes the same changes as [Edits], with one row for every edit. If x and y are
+// identical, every row is a match.
+//
+// The following options are supported: [Minimal], [PreferLongMatches], [CostLimit],
+// [GoodDiagonalTuning], [Fast], [AutoFast], [Parallel]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func Align[T comparable](x, y []T, opts ...Option) []AlignRow[T] {
+	cfg := config.FromOptions(opts, diffFlags)
+	rx, ry := impl.Diff(x, y, cfg)
+	return align(x, y, rx, ry)
+}
+
+// AlignFunc is like [Align] but uses the provided equality comparison.
+//
+// The following options are supported: [Minimal], [PreferLongMatches], [CostLimit],
+// [GoodDiagonalTuning]
+//
+// Note that this function has generally worse performance than [Align] for diffs with many changes.
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func AlignFunc[T any](x, y []T, eq func(a, b T) bool, opts ...Option) []AlignRow[T] {
+	cfg := config.FromOptions(opts, funcFlags)
+	rx, ry := impl.DiffFunc(x, y, eq, cfg)
+	return align(x, y, rx, ry)
+}
+
+func align[T any](x, y []T, rx, ry []bool) []AlignRow[T] {
+	// Every element in y has a row and every deleted element in x has one in addition.
+	n, m := len(rx)-1, len(ry)-1
+	nrows := m
+	for _, r := range rx[:n] {
+		if r {
+			nrows++
+		}
+	}
+	if nrows == 0 {
+		return nil
+	}
+
+	rows := make([]AlignRow[T], 0, nrows)
+	for s, t := 0, 0; s < n || t < m; {
+		for s < n && rx[s] {
+			rows = append(rows, AlignRow[T]{X: &x[s], Op: Delete})
+			s++
+		}
+		for t < m && ry[t] {
+			rows = append(rows, AlignRow[T]{Y: &y[t], Op: Insert})
+			t++
+		}
+		for s < n && t < m && !rx[s] && !ry[t] {
+			rows = append(rows, AlignRow[T]{X: &x[s], Y: &y[t], Op: Match})
+			s++
+			t++
+		}
+	}
+	return rows
+}
+
+// Equal reports whether x and y are element-wise equal. If Equal returns true, [Hunks] returns
+// no hunks and [Edits] returns only matches.
+//
+// This doesn't compute a diff and stops at the first difference.
+func Equal[T comparable](x, y []T) bool {
+	return slices.Equal(x, y)
+}
+
+// FirstDifference returns the index of the first element where x and y differ. If one input is a
+// prefix of the other, the index is the length of the shorter input. If x and y are equal, it
+// returns -1 and true.
+//
+// This is a lot cheaper than computing a diff and useful for equality checks that want to report
+// where the inputs start to differ.
+func FirstDifference[T comparable](x, y []T) (index int, equal bool) {
+	n := impl.CommonPrefixLen(x, y)
+	if n == len(x) && n == len(y) {
+		return -1, true
+	}
+	return n, false
+}
+
+// LCSLen returns the length of the longest common subsequence of x and y.
+//
+// This is the number of matches in a minimal diff of x and y and it's cheaper to compute than
+// counting the matches in the output of [Edits] with [Minimal]. A common prefix and suffix are
+// skipped without running the diff algorithm.
+func LCSLen[T comparable](x, y []T) int {
+	cfg := config.Default
+	cfg.Mode = config.ModeMinimal
+	rx, _ := impl.Diff(x, y, cfg)
+	return lcsLen(rx[:len(x)])
+}
+
+// LCSLenFunc is like [LCSLen] but uses the provided equality comparison.
+//
+// Note that this function has generally worse performance than [LCSLen] for inputs with many
+// differences.
+func LCSLenFunc[T any](x, y []T, eq func(a, b T) bool) int {
+	cfg := config.Default
+	cfg.Mode = config.ModeMinimal
+	rx, _ := impl.DiffFunc(x, y, eq, cfg)
+	return lcsLen(rx[:len(x)])
+}
+
+// SimilarAtLeast reports whether the similarity of x and y is at least ratio. The similarity is
+// 2*M/(N+K), where M is the length of the longest common subsequence (see [LCSLen]) and N and K
+// are the lengths of x and y. It's 1 for identical inputs, including two empty inputs, and 0 for
+// inputs that have nothing in common.
+//
+// SimilarAtLeast doesn't compute a full diff: It finds matches incrementally and returns true as
+// soon as the matches found so far guarantee a similarity of at least ratio, no matter how the
+// rest of the inputs compare. Likewise, it returns false as soon as the number of differences
+// found so far rules out the ratio. The more similar the inputs are, and the higher the ratio is,
+// the earlier the result is known. This is useful to find near-duplicates, e.g., for plagiarism
+// detection.
+//
+// Performance impact: O((N+K)*D) time where D is the number of differences that are needed to
+// decide the result, but at most the number of differences in a minimal diff.
+func SimilarAtLeast[T comparable](x, y []T, ratio float64) bool {
+	total := len(x) + len(y)
+	if total == 0 {
+		return ratio <= 1
+	}
+	similar := func(matches int) bool { return 2*float64(matches)/float64(total) >= ratio }
+	// Find the smallest number of matches that is similar enough. Computing it directly is prone
+	// to rounding errors at the boundary.
+	k := min(max(0, int(ratio*float64(total)/2)), min(len(x), len(y))+1)
+	for k > 0 && similar(k-1) {
+		k--
+	}
+	for k <= min(len(x), len(y)) && !similar(k) {
+		k++
+	}
+	return impl.MatchesAtLeast(x, y, k)
+}
+
+// lcsLen returns the number of elements that aren't deleted according to the result vector rx.
+func lcsLen(rx []bool) int {
+	n := 0
+	for _, r := range rx {
+		if !r {
+			n++
+		}
+	}
+	return n
+}
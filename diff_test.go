@@ -17,11 +17,17 @@ package diff
 import (
 	"crypto/sha256"
 	"fmt"
+	"math"
 	"math/rand/v2"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"znkr.io/diff/internal/config"
+	"znkr.io/diff/internal/impl"
 )
 
 func TestHunks(t *testing.T) {
@@ -392,6 +398,16 @@ func TestEdits(t *testing.T) {
 					t.Errorf("Edits(...) result is different (-want, +got):\n%s", diff)
 				}
 			}
+			{
+				var got []Edit[string]
+				WalkEdits(tt.x, tt.y, func(e Edit[string]) bool {
+					got = append(got, e)
+					return true
+				})
+				if diff := cmp.Diff(tt.want, got); diff != "" {
+					t.Errorf("WalkEdits(...) result is different (-want, +got):\n%s", diff)
+				}
+			}
 			{
 				got := EditsFunc(tt.x, tt.y, func(a, b string) bool { return a == b })
 				if diff := cmp.Diff(tt.want, got); diff != "" {
@@ -402,6 +418,1582 @@ func TestEdits(t *testing.T) {
 	}
 }
 
+func TestAutoFast(t *testing.T) {
+	// After removing the common prefix and suffix, the inputs below have a product of sizes of
+	// 7*6 = 42.
+	x := strings.Split("xABCABBAx", "")
+	y := strings.Split("xCBABACx", "")
+	tests := []struct {
+		name       string
+		maxProduct int
+		want       []Edit[string]
+	}{
+		{
+			name:       "below-limit",
+			maxProduct: 43,
+			want:       Edits(x, y),
+		},
+		{
+			name:       "at-limit",
+			maxProduct: 42,
+			want:       Edits(x, y),
+		},
+		{
+			name:       "above-limit",
+			maxProduct: 41,
+			want:       Edits(x, y, Fast()),
+		},
+		{
+			name:       "zero",
+			maxProduct: 0,
+			want:       Edits(x, y, Fast()),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Edits(x, y, AutoFast(tt.maxProduct))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Edits(...) result is different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+
+	// Sanity check that the test above is meaningful.
+	if cmp.Equal(Edits(x, y), Edits(x, y, Fast())) {
+		t.Errorf("default and fast mode produce the same result, the test above is not meaningful")
+	}
+}
+
+func TestHunksAdjacent(t *testing.T) {
+	// Two changes separated by a single matching element must end up in the same hunk, no matter
+	// which algorithm computed the edits.
+	x := strings.Split("abcdefgh", "")
+	y := strings.Split("abXdYfgh", "")
+	for _, tt := range []struct {
+		name string
+		opts []Option
+	}{
+		{"default", nil},
+		{"minimal", []Option{Minimal()}},
+		{"fast", []Option{Fast()}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, context := range []int{1, 3} {
+				hunks := Hunks(x, y, append(tt.opts, Context(context))...)
+				if len(hunks) != 1 {
+					t.Fatalf("Hunks(..., Context(%d)) returned %d hunks, want 1:\n%v", context, len(hunks), hunks)
+				}
+				// Grouping must not change which elements are edits.
+				var want []Edit[string]
+				for _, e := range Edits(x, y, tt.opts...) {
+					if e.PosX >= hunks[0].PosX && e.PosX < hunks[0].EndX || e.PosY >= hunks[0].PosY && e.PosY < hunks[0].EndY {
+						want = append(want, e)
+					}
+				}
+				if diff := cmp.Diff(want, hunks[0].Edits); diff != "" {
+					t.Errorf("Hunks(..., Context(%d)) edits are different from Edits(...) (-want, +got):\n%s", context, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestHunksOrdered(t *testing.T) {
+	// Within a hunk, the edits cover x[PosX:EndX] and y[PosY:EndY] in order, no matter how the
+	// hunks were computed and merged. Hunks are ordered too.
+	for _, tt := range []struct {
+		name string
+		opts []Option
+	}{
+		{"default", nil},
+		{"context-0", []Option{Context(0)}},
+		{"context-5", []Option{Context(5)}},
+		{"minimal", []Option{Minimal(), PreferLongMatches()}},
+		{"fast", []Option{Fast()}},
+		{"paired-ordering", []Option{PairedOrdering()}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			rng := rand.New(rand.NewPCG(1, 2))
+			for i := range 300 {
+				x := make([]int, rng.IntN(60))
+				for j := range x {
+					x[j] = rng.IntN(5)
+				}
+				y := make([]int, rng.IntN(60))
+				for j := range y {
+					y[j] = rng.IntN(5)
+				}
+				endX, endY := 0, 0
+				for _, h := range Hunks(x, y, tt.opts...) {
+					if h.PosX < endX || h.PosY < endY {
+						t.Fatalf("%d: Hunks(%v, %v) has overlapping or unordered hunks:\n%v", i, x, y, h)
+					}
+					nextX, nextY := h.PosX, h.PosY
+					for _, e := range h.Edits {
+						if e.PosX >= 0 {
+							if e.PosX != nextX {
+								t.Fatalf("%d: Hunks(%v, %v) has edit %v at x position %d, want %d:\n%v", i, x, y, e, e.PosX, nextX, h)
+							}
+							nextX++
+						}
+						if e.PosY >= 0 {
+							if e.PosY != nextY {
+								t.Fatalf("%d: Hunks(%v, %v) has edit %v at y position %d, want %d:\n%v", i, x, y, e, e.PosY, nextY, h)
+							}
+							nextY++
+						}
+					}
+					if nextX != h.EndX || nextY != h.EndY {
+						t.Fatalf("%d: Hunks(%v, %v) edits end at %d, %d, want %d, %d:\n%v", i, x, y, nextX, nextY, h.EndX, h.EndY, h)
+					}
+					endX, endY = h.EndX, h.EndY
+				}
+			}
+		})
+	}
+}
+
+func TestHunksSeq(t *testing.T) {
+	for _, s := range benchmarkSpecs {
+		x, y := s.generate([]byte{})
+		for _, opts := range [][]Option{
+			nil,
+			{Context(0)},
+			{PairedOrdering()},
+			{MaxHunks(2)},
+		} {
+			want := Hunks(x, y, opts...)
+			got := slices.Collect(HunksSeq(x, y, opts...))
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("HunksSeq(...) for %s with %d options is different from Hunks(...) (-want, +got):\n%s", s.name(), len(opts), diff)
+			}
+		}
+	}
+}
+
+func TestHunksSeqBreak(t *testing.T) {
+	x, y := spec{500, 500, 100}.generate([]byte{})
+	want := Hunks(x, y)
+	if len(want) < 3 {
+		t.Fatalf("Hunks(x, y) returned %d hunks, want at least 3", len(want))
+	}
+	var got []Hunk[int]
+	for h := range HunksSeq(x, y) {
+		got = append(got, h)
+		if len(got) == 2 {
+			break
+		}
+	}
+	if diff := cmp.Diff(want[:2], got); diff != "" {
+		t.Errorf("HunksSeq(...) result is different (-want, +got):\n%s", diff)
+	}
+}
+
+func TestNextPrevChange(t *testing.T) {
+	// Two hunks: x[0:3] with three insertions at position 0, x[4:11] with deletions at 7..10.
+	x := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"}
+	y := []string{"1", "2", "3", "a", "b", "c", "d", "e", "f", "g"}
+	hunks := Hunks(x, y)
+	if len(hunks) != 2 {
+		t.Fatalf("Hunks(x, y) returned %d hunks, want 2", len(hunks))
+	}
+
+	type result struct {
+		HunkIdx, EditIdx int
+		OK               bool
+	}
+	tests := []struct {
+		posX       int
+		next, prev result
+	}{
+		{posX: -1, next: result{0, 0, true}, prev: result{-1, -1, false}},
+		{posX: 0, next: result{0, 0, true}, prev: result{-1, -1, false}},
+		{posX: 1, next: result{1, 3, true}, prev: result{0, 2, true}},
+		{posX: 5, next: result{1, 3, true}, prev: result{0, 2, true}},
+		{posX: 7, next: result{1, 3, true}, prev: result{0, 2, true}},
+		{posX: 8, next: result{1, 4, true}, prev: result{1, 3, true}},
+		{posX: 10, next: result{1, 6, true}, prev: result{1, 5, true}},
+		{posX: 11, next: result{-1, -1, false}, prev: result{1, 6, true}},
+		{posX: 100, next: result{-1, -1, false}, prev: result{1, 6, true}},
+	}
+	for _, tt := range tests {
+		var got result
+		got.HunkIdx, got.EditIdx, got.OK = NextChange(hunks, tt.posX)
+		if diff := cmp.Diff(tt.next, got); diff != "" {
+			t.Errorf("NextChange(hunks, %d) result is different (-want, +got):\n%s", tt.posX, diff)
+		}
+		got.HunkIdx, got.EditIdx, got.OK = PrevChange(hunks, tt.posX)
+		if diff := cmp.Diff(tt.prev, got); diff != "" {
+			t.Errorf("PrevChange(hunks, %d) result is different (-want, +got):\n%s", tt.posX, diff)
+		}
+	}
+}
+
+func TestNextPrevChangeRandom(t *testing.T) {
+	// Compare against a linear scan over all changes.
+	for _, s := range []spec{{100, 100, 20}, {200, 150, 50}, {500, 500, 100}} {
+		x, y := s.generate([]byte{})
+		for _, opts := range [][]Option{nil, {Context(0)}, {PairedOrdering()}} {
+			hunks := Hunks(x, y, opts...)
+			type change struct{ hunkIdx, editIdx, pos int }
+			var changes []change
+			for i, h := range hunks {
+				cur := h.PosX
+				for j, e := range h.Edits {
+					pos := cur
+					if e.PosX >= 0 {
+						pos, cur = e.PosX, e.PosX+1
+					}
+					if e.Op.IsChange() {
+						changes = append(changes, change{i, j, pos})
+					}
+				}
+			}
+			for posX := -1; posX <= len(x)+1; posX++ {
+				k := 0
+				for k < len(changes) && changes[k].pos < posX {
+					k++
+				}
+				hi, ei, ok := NextChange(hunks, posX)
+				if want := k < len(changes); ok != want || ok && (hi != changes[k].hunkIdx || ei != changes[k].editIdx) {
+					t.Errorf("%s: NextChange(hunks, %d) = %d, %d, %t, want change %d of %d", s.name(), posX, hi, ei, ok, k, len(changes))
+				}
+				hi, ei, ok = PrevChange(hunks, posX)
+				if want := k > 0; ok != want || ok && (hi != changes[k-1].hunkIdx || ei != changes[k-1].editIdx) {
+					t.Errorf("%s: PrevChange(hunks, %d) = %d, %d, %t, want change %d of %d", s.name(), posX, hi, ei, ok, k-1, len(changes))
+				}
+			}
+		}
+	}
+}
+
+func TestMaxHunks(t *testing.T) {
+	// Every 10th element changes, resulting in 10 hunks with the default context.
+	x := make([]int, 100)
+	y := make([]int, 100)
+	for i := range x {
+		x[i] = i
+		y[i] = i
+		if i%10 == 5 {
+			y[i] = -i
+		}
+	}
+	all := Hunks(x, y)
+	if len(all) != 10 {
+		t.Fatalf("Hunks(x, y) returned %d hunks, want 10", len(all))
+	}
+
+	tests := []struct {
+		name        string
+		k           int
+		wantHunks   int
+		wantOmitted int
+	}{
+		{"zero", 0, 1, 9},
+		{"one", 1, 1, 9},
+		{"some", 3, 3, 7},
+		{"all", 10, 10, 0},
+		{"more", 11, 10, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, omitted := HunksTruncated(x, y, MaxHunks(tt.k))
+			if diff := cmp.Diff(all[:tt.wantHunks], got); diff != "" {
+				t.Errorf("HunksTruncated(...) result is different (-want, +got):\n%s", diff)
+			}
+			if omitted != tt.wantOmitted {
+				t.Errorf("HunksTruncated(...) omitted %d hunks, want %d", omitted, tt.wantOmitted)
+			}
+			if got := Hunks(x, y, MaxHunks(tt.k)); !cmp.Equal(all[:tt.wantHunks], got) {
+				t.Errorf("Hunks(..., MaxHunks(%d)) returned %d hunks, want %d", tt.k, len(got), tt.wantHunks)
+			}
+		})
+	}
+
+	// Without MaxHunks, nothing is omitted.
+	if _, omitted := HunksTruncated(x, y); omitted != 0 {
+		t.Errorf("HunksTruncated(x, y) omitted %d hunks, want 0", omitted)
+	}
+}
+
+func TestHunksWithTrace(t *testing.T) {
+	largeX, largeY := spec{20_000, 20_000, 10_000}.generate([]byte{})
+	tests := []struct {
+		name string
+		x, y []int
+		opts []Option
+		want Trace
+	}{
+		{
+			name: "identical",
+			x:    []int{1, 2, 3},
+			y:    []int{1, 2, 3},
+			want: Trace{},
+		},
+		{
+			name: "small",
+			x:    []int{1, 2, 3, 1, 2, 2, 1},
+			y:    []int{3, 2, 1, 2, 1, 3},
+			want: Trace{EditDistance: 5, Mode: ModeDefault},
+		},
+		{
+			name: "small-fast",
+			x:    []int{1, 2, 3, 1, 2, 2, 1},
+			y:    []int{3, 2, 1, 2, 1, 3},
+			opts: []Option{Fast()},
+			want: Trace{EditDistance: 13, HeuristicFired: true, Mode: ModeFast},
+		},
+		{
+			name: "small-auto-fast",
+			x:    []int{1, 2, 3, 1, 2, 2, 1},
+			y:    []int{3, 2, 1, 2, 1, 3},
+			opts: []Option{AutoFast(10)},
+			want: Trace{EditDistance: 13, HeuristicFired: true, Mode: ModeFast},
+		},
+		{
+			name: "large-noisy",
+			x:    largeX,
+			y:    largeY,
+			want: Trace{EditDistance: -1, HeuristicFired: true, Mode: ModeDefault},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hunks, got := HunksWithTrace(tt.x, tt.y, tt.opts...)
+			if tt.want.EditDistance < 0 {
+				tt.want.EditDistance = got.EditDistance // don't care
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("HunksWithTrace(...) trace is different (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(Hunks(tt.x, tt.y, tt.opts...), hunks); diff != "" {
+				t.Errorf("HunksWithTrace(...) hunks are different from Hunks(...) (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCostLimit(t *testing.T) {
+	x, y := spec{500, 500, 200}.generate([]byte{})
+	_, minimal := HunksWithTrace(x, y, Minimal())
+
+	_, low := HunksWithTrace(x, y, CostLimit(4))
+	if !low.HeuristicFired {
+		t.Errorf("HunksWithTrace(..., CostLimit(4)) didn't fire a heuristic")
+	}
+	if low.EditDistance <= minimal.EditDistance {
+		t.Errorf("HunksWithTrace(..., CostLimit(4)) edit distance = %d, want > %d", low.EditDistance, minimal.EditDistance)
+	}
+
+	_, high := HunksWithTrace(x, y, CostLimit(1<<20))
+	if high.EditDistance >= low.EditDistance {
+		t.Errorf("HunksWithTrace(..., CostLimit(1<<20)) edit distance = %d, want < %d", high.EditDistance, low.EditDistance)
+	}
+	if high.EditDistance != minimal.EditDistance {
+		t.Errorf("HunksWithTrace(..., CostLimit(1<<20)) edit distance = %d, want %d", high.EditDistance, minimal.EditDistance)
+	}
+
+	// The limit is also used for types that aren't comparable.
+	changes := func(edits []Edit[int]) int {
+		n := 0
+		for _, e := range edits {
+			if e.Op != Match {
+				n++
+			}
+		}
+		return n
+	}
+	eq := func(a, b int) bool { return a == b }
+	if got := changes(EditsFunc(x, y, eq, CostLimit(4))); got <= minimal.EditDistance {
+		t.Errorf("EditsFunc(..., CostLimit(4)) edit distance = %d, want > %d", got, minimal.EditDistance)
+	}
+}
+
+func TestMinimalWithBudget(t *testing.T) {
+	x, y := spec{5000, 5000, 2000}.generate([]byte{})
+	_, minimal := HunksWithTrace(x, y, Minimal())
+
+	// With a tiny budget, the search falls back to the heuristics. A low cost limit makes sure that
+	// they fire. The result is still a valid diff.
+	edits := Edits(x, y, MinimalWithBudget(time.Nanosecond), CostLimit(4))
+	got, err := ApplyEdits(x, edits)
+	if err != nil {
+		t.Fatalf("ApplyEdits(x, Edits(..., MinimalWithBudget(1ns), CostLimit(4))) failed: %v", err)
+	}
+	if diff := cmp.Diff(y, got); diff != "" {
+		t.Errorf("ApplyEdits(x, Edits(..., MinimalWithBudget(1ns), CostLimit(4))) result is different (-want, +got):\n%s", diff)
+	}
+	_, low := HunksWithTrace(x, y, MinimalWithBudget(time.Nanosecond), CostLimit(4))
+	if !low.HeuristicFired {
+		t.Errorf("HunksWithTrace(..., MinimalWithBudget(1ns), CostLimit(4)) didn't fire a heuristic")
+	}
+	if low.EditDistance <= minimal.EditDistance {
+		t.Errorf("HunksWithTrace(..., MinimalWithBudget(1ns), CostLimit(4)) edit distance = %d, want > %d", low.EditDistance, minimal.EditDistance)
+	}
+
+	// With a large budget, the diff is minimal.
+	_, high := HunksWithTrace(x, y, MinimalWithBudget(time.Hour), CostLimit(4))
+	if high.EditDistance != minimal.EditDistance {
+		t.Errorf("HunksWithTrace(..., MinimalWithBudget(1h), CostLimit(4)) edit distance = %d, want %d", high.EditDistance, minimal.EditDistance)
+	}
+
+	// Stable takes precedence.
+	_, stable := HunksWithTrace(x, y, MinimalWithBudget(time.Nanosecond), CostLimit(4), Stable())
+	if stable.EditDistance != minimal.EditDistance {
+		t.Errorf("HunksWithTrace(..., MinimalWithBudget(1ns), CostLimit(4), Stable()) edit distance = %d, want %d", stable.EditDistance, minimal.EditDistance)
+	}
+}
+
+func TestHunksOptimalReport(t *testing.T) {
+	small := func() ([]int, []int) { return spec{50, 50, 10}.generate([]byte{}) }
+	// Large random inputs with a small alphabet have many differences and no anchors.
+	noisy := func() ([]int, []int) {
+		rng := rand.New(rand.NewPCG(1, 2))
+		x, y := make([]int, 12000), make([]int, 12000)
+		for i := range x {
+			x[i], y[i] = rng.IntN(4), rng.IntN(4)
+		}
+		return x, y
+	}
+	tests := []struct {
+		name   string
+		inputs func() ([]int, []int)
+		opts   []Option
+		want   bool
+	}{
+		{name: "small", inputs: small, want: true},
+		{name: "small-fast", inputs: small, opts: []Option{Fast()}, want: false},
+		{name: "small-minimal", inputs: small, opts: []Option{Minimal()}, want: true},
+		{name: "noisy", inputs: noisy, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y := tt.inputs()
+			hunks, got := HunksOptimalReport(x, y, tt.opts...)
+			if got != tt.want {
+				t.Errorf("HunksOptimalReport(...) = %v, want %v", got, tt.want)
+			}
+			if diff := cmp.Diff(Hunks(x, y, tt.opts...), hunks); diff != "" {
+				t.Errorf("HunksOptimalReport(...) hunks are different from Hunks(...) (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHash(t *testing.T) {
+	x := strings.Split("ABCABBA", "")
+	y := strings.Split("CBABAC", "")
+	h := Hash(x, y)
+
+	// Equal inputs produce equal hashes, even if they're different slices.
+	if got := Hash(slices.Clone(x), slices.Clone(y)); got != h {
+		t.Errorf("Hash(...) of equal inputs = %x, want %x", got, h)
+	}
+
+	// A single change flips the hash.
+	for i := range y {
+		y2 := slices.Clone(y)
+		y2[i] = "X"
+		if got := Hash(x, y2); got == h {
+			t.Errorf("Hash(...) with y[%d] changed = %x, want a different hash", i, got)
+		}
+	}
+	x2 := append(slices.Clone(x), "A")
+	if got := Hash(x2, y); got == h {
+		t.Errorf("Hash(...) with an element appended to x = %x, want a different hash", got)
+	}
+
+	// The same elements with different ops have a different hash.
+	if Hash([]string{"a"}, nil) == Hash(nil, []string{"a"}) {
+		t.Errorf("Hash(...) of a deletion and an insertion of the same element are equal")
+	}
+
+	// Identical inputs only consist of matches, but the hash still depends on the elements.
+	if Hash(x, x) == Hash(y, y) {
+		t.Errorf("Hash(x, x) == Hash(y, y), want different hashes")
+	}
+}
+
+func TestHashEncoding(t *testing.T) {
+	// The hash is persisted by callers, it must not change between processes or releases unless
+	// the diff itself changes.
+	type token int8
+	tests := []struct {
+		name string
+		got  uint64
+		want uint64
+	}{
+		{"strings", Hash([]string{"a"}, []string{"b"}), 0x58b31ac904d22803},
+		{"ints", Hash([]int{1, 2}, []int{1, 3}), 0x3debca89edf1f594},
+		{"negative", Hash([]token{-1}, nil), 0x685cd83ad34b3424},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("Hash(...) for %s = %#x, want %#x", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestSettings(t *testing.T) {
+	x, y := spec{500, 500, 100}.generate([]byte{})
+	ptr := func(n int) *int { return &n }
+	tests := []struct {
+		name string
+		s    Settings
+	}{
+		{name: "zero"},
+		{name: "context", s: Settings{Context: ptr(1), MaxHunks: 2}},
+		{name: "no-context", s: Settings{Context: ptr(0)}},
+		{name: "minimal", s: Settings{Mode: ModeMinimal, PreferLongMatches: true}},
+		{name: "minimal-budget", s: Settings{Mode: ModeMinimal, MinimalBudget: time.Hour}},
+		{name: "fast", s: Settings{Mode: ModeFast, PairedOrdering: true}},
+		{name: "stable", s: Settings{Mode: ModeFast, Stable: true}},
+		{name: "tuning", s: Settings{CostLimit: 4, Parallel: 2, AutoFast: 1000}},
+		{name: "good-diagonal", s: Settings{GoodDiagonalMinLen: 4, GoodDiagonalCostLimit: 8}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := Hunks(x, y, tt.s.Options()...)
+			if diff := cmp.Diff(want, HunksWith(x, y, tt.s)); diff != "" {
+				t.Errorf("HunksWith(...) is different from Hunks(..., s.Options()...) (-want, +got):\n%s", diff)
+			}
+		})
+	}
+
+	// The zero value is the same as no options at all.
+	if diff := cmp.Diff(Hunks(x, y), HunksWith(x, y, Settings{})); diff != "" {
+		t.Errorf("HunksWith(..., Settings{}) is different from Hunks(...) (-want, +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(Hunks(x, y, Context(0)), HunksWith(x, y, Settings{Context: ptr(0)})); diff != "" {
+		t.Errorf("HunksWith(...) with Context 0 is different from Hunks(..., Context(0)) (-want, +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(Edits(x, y, DetectSwaps()), EditsWith(x, y, Settings{DetectSwaps: true})); diff != "" {
+		t.Errorf("EditsWith(...) result is different (-want, +got):\n%s", diff)
+	}
+}
+
+func TestSettingsOptions(t *testing.T) {
+	// Every setting results in the same configuration as the corresponding option.
+	s := Settings{
+		Context:               new(int),
+		MaxHunks:              2,
+		Mode:                  ModeMinimal,
+		MinimalBudget:         time.Second,
+		PreferLongMatches:     true,
+		PairedOrdering:        true,
+		CostLimit:             4,
+		Parallel:              2,
+		AutoFast:              1000,
+		MaxWork:               100,
+		FloatTolerance:        0.5,
+		GoodDiagonalMinLen:    1,
+		GoodDiagonalCostLimit: 2,
+		GoodDiagonalMagic:     3,
+		DetectSwaps:           true,
+		DetectModifiedMoves:   0.5,
+	}
+	opts := []Option{
+		Context(0),
+		MaxHunks(2),
+		MinimalWithBudget(time.Second),
+		PreferLongMatches(),
+		PairedOrdering(),
+		CostLimit(4),
+		Parallel(2),
+		AutoFast(1000),
+		MaxWork(100),
+		FloatTolerance(0.5),
+		GoodDiagonalTuning(1, 2, 3),
+		DetectSwaps(),
+		DetectModifiedMoves(0.5),
+	}
+	all := config.Flag(^0)
+	if diff := cmp.Diff(config.FromOptions(opts, all), config.FromOptions(s.Options(), all)); diff != "" {
+		t.Errorf("Settings.Options() results in a different configuration (-want, +got):\n%s", diff)
+	}
+}
+
+func TestSettingsUnsupported(t *testing.T) {
+	x, y := []int{1, 2, 3}, []int{1, 3}
+	for _, tt := range []struct {
+		name string
+		f    func()
+	}{
+		{"hunks-detect-swaps", func() { HunksWith(x, y, Settings{DetectSwaps: true}) }},
+		{"hunks-max-work", func() { HunksWith(x, y, Settings{MaxWork: 10}) }},
+		{"edits-context", func() { EditsWith(x, y, Settings{Context: new(int)}) }},
+		{"edits-max-hunks", func() { EditsWith(x, y, Settings{MaxHunks: 1}) }},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("unsupported setting didn't panic")
+				}
+			}()
+			tt.f()
+		})
+	}
+}
+
+// parallelInput returns large inputs with unique elements that serve as anchors and with changes
+// spread over the whole input.
+func parallelInput(n int) (x, y []int) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	x = make([]int, n)
+	for i := range x {
+		x[i] = i
+	}
+	y = slices.Clone(x)
+	for i := range y {
+		if rng.IntN(4) == 0 {
+			y[i] = -rng.IntN(100)
+		}
+	}
+	return x, y
+}
+
+func TestParallel(t *testing.T) {
+	x, y := parallelInput(50_000)
+	want, wantTrace := HunksWithTrace(x, y)
+	if !wantTrace.HeuristicFired {
+		t.Fatalf("HunksWithTrace(...) didn't use the anchoring heuristic")
+	}
+	for _, n := range []int{-1, 1, 2, 8, 1000} {
+		got, trace := HunksWithTrace(x, y, Parallel(n))
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("HunksWithTrace(..., Parallel(%d)) result is different (-want, +got):\n%s", n, diff)
+		}
+		if trace != wantTrace {
+			t.Errorf("HunksWithTrace(..., Parallel(%d)) trace = %+v, want %+v", n, trace, wantTrace)
+		}
+	}
+}
+
+func TestGoodDiagonalTuning(t *testing.T) {
+	// The inputs share a long diagonal interrupted by scattered changes. The default parameters
+	// never apply the heuristic for inputs this small.
+	x, y := spec{500, 500, 200}.generate([]byte{})
+	_, minimal := HunksWithTrace(x, y, Minimal())
+
+	_, def := HunksWithTrace(x, y)
+	if def.HeuristicFired || def.EditDistance != minimal.EditDistance {
+		t.Errorf("HunksWithTrace(...) = %+v, want minimal edit distance %d without heuristics", def, minimal.EditDistance)
+	}
+
+	_, tuned := HunksWithTrace(x, y, GoodDiagonalTuning(4, 8, 1))
+	if !tuned.HeuristicFired {
+		t.Errorf("HunksWithTrace(..., GoodDiagonalTuning(4, 8, 1)) didn't fire a heuristic")
+	}
+	if tuned.EditDistance <= minimal.EditDistance {
+		t.Errorf("HunksWithTrace(..., GoodDiagonalTuning(4, 8, 1)) edit distance = %d, want > %d", tuned.EditDistance, minimal.EditDistance)
+	}
+
+	// A diagonal can't be longer than the inputs.
+	_, long := HunksWithTrace(x, y, GoodDiagonalTuning(1000, 8, 1))
+	if long.HeuristicFired || long.EditDistance != minimal.EditDistance {
+		t.Errorf("HunksWithTrace(..., GoodDiagonalTuning(1000, 8, 1)) = %+v, want minimal edit distance %d without heuristics", long, minimal.EditDistance)
+	}
+
+	// The parameters are also used for types that aren't comparable.
+	changes := 0
+	for _, e := range EditsFunc(x, y, func(a, b int) bool { return a == b }, GoodDiagonalTuning(4, 8, 1)) {
+		if e.Op != Match {
+			changes++
+		}
+	}
+	if changes <= minimal.EditDistance {
+		t.Errorf("EditsFunc(..., GoodDiagonalTuning(4, 8, 1)) edit distance = %d, want > %d", changes, minimal.EditDistance)
+	}
+}
+
+func TestEditsFuncChecked(t *testing.T) {
+	eq := func(a, b int) bool { return a == b }
+
+	// apply applies edits to x and returns the result.
+	apply := func(edits []Edit[int]) []int {
+		var out []int
+		for _, e := range edits {
+			if e.Op != Delete {
+				out = append(out, e.Y)
+			}
+		}
+		return out
+	}
+
+	t.Run("below-limit", func(t *testing.T) {
+		x, y := spec{50, 50, 10}.generate([]byte{})
+		got, err := EditsFuncChecked(x, y, eq, MaxWork(1000))
+		if err != nil {
+			t.Fatalf("EditsFuncChecked(...) failed: %v", err)
+		}
+		if diff := cmp.Diff(EditsFunc(x, y, eq), got); diff != "" {
+			t.Errorf("EditsFuncChecked(...) result is different from EditsFunc(...) (-want, +got):\n%s", diff)
+		}
+	})
+
+	t.Run("adversarial", func(t *testing.T) {
+		// Two large inputs without any structure in common. Without a work limit, a minimal diff
+		// takes O(ND) time with a large D.
+		rng := rand.New(rand.NewPCG(1, 2))
+		x, y := make([]int, 50_000), make([]int, 50_000)
+		for i := range x {
+			x[i] = rng.IntN(50)
+			y[i] = rng.IntN(50)
+		}
+		got, err := EditsFuncChecked(x, y, eq, Minimal(), MaxWork(1000))
+		if err != ErrMaxWork {
+			t.Fatalf("EditsFuncChecked(...) error = %v, want %v", err, ErrMaxWork)
+		}
+		if !slices.Equal(apply(got), y) {
+			t.Errorf("EditsFuncChecked(...) partial result doesn't convert x to y")
+		}
+	})
+}
+
+func TestEditsFloat(t *testing.T) {
+	nan, negZero := math.NaN(), math.Copysign(0, -1)
+	tests := []struct {
+		name string
+		x, y []float64
+		opts []Option
+		want []Op // The values can contain NaN, only compare the ops.
+	}{
+		{
+			name: "nan",
+			x:    []float64{1, nan, 3},
+			y:    []float64{1, nan, 3},
+			want: []Op{Match, Match, Match},
+		},
+		{
+			name: "nan-payload",
+			x:    []float64{math.Float64frombits(0x7ff8000000000001)},
+			y:    []float64{nan},
+			want: []Op{Match},
+		},
+		{
+			name: "zero",
+			x:    []float64{0, 1},
+			y:    []float64{negZero, 1},
+			want: []Op{Match, Match},
+		},
+		{
+			name: "near-equal",
+			x:    []float64{1, 2, 3},
+			y:    []float64{1, 2.0001, 3},
+			want: []Op{Match, Delete, Insert, Match},
+		},
+		{
+			name: "near-equal-tolerance",
+			x:    []float64{1, 2, 3},
+			y:    []float64{1, 2.0001, 3},
+			opts: []Option{FloatTolerance(0.001)},
+			want: []Op{Match, Match, Match},
+		},
+		{
+			name: "outside-tolerance",
+			x:    []float64{1, 2, 3},
+			y:    []float64{1, 2.01, 3},
+			opts: []Option{FloatTolerance(0.001)},
+			want: []Op{Match, Delete, Insert, Match},
+		},
+		{
+			name: "nan-tolerance",
+			x:    []float64{nan, math.Inf(1), 0},
+			y:    []float64{nan, math.Inf(1), negZero},
+			opts: []Option{FloatTolerance(0.001)},
+			want: []Op{Match, Match, Match},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []Op
+			for _, e := range EditsFloat(tt.x, tt.y, tt.opts...) {
+				got = append(got, e.Op)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("EditsFloat(...) result is different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+
+	// Without EditsFloat, every NaN is a change.
+	for _, e := range Edits([]float64{nan}, []float64{nan}) {
+		if e.Op == Match {
+			t.Errorf("Edits(...) reports NaN as match")
+		}
+	}
+
+	// float32 works too.
+	got := EditsFloat([]float32{1, float32(nan)}, []float32{1, float32(nan)})
+	if len(got) != 2 || got[0].Op != Match || got[1].Op != Match {
+		t.Errorf("EditsFloat[float32](...) = %v, want two matches", got)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y []string
+		want bool
+	}{
+		{name: "nil", want: true},
+		{name: "nil-empty", x: nil, y: []string{}, want: true},
+		{name: "identical", x: []string{"a", "b"}, y: []string{"a", "b"}, want: true},
+		{name: "prefix", x: []string{"a"}, y: []string{"a", "b"}, want: false},
+		{name: "different", x: []string{"a", "b"}, y: []string{"a", "c"}, want: false},
+		{name: "empty-element", x: []string{""}, y: []string{}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Equal(tt.x, tt.y); got != tt.want {
+				t.Errorf("Equal(%q, %q) = %v, want %v", tt.x, tt.y, got, tt.want)
+			}
+			if got, want := Equal(tt.x, tt.y), len(Hunks(tt.x, tt.y)) == 0; got != want {
+				t.Errorf("Equal(%q, %q) = %v, but Hunks(...) is empty = %v", tt.x, tt.y, got, want)
+			}
+		})
+	}
+}
+
+func TestFirstDifference(t *testing.T) {
+	tests := []struct {
+		name      string
+		x, y      []string
+		wantIndex int
+		wantEqual bool
+	}{
+		{
+			name:      "empty",
+			wantIndex: -1,
+			wantEqual: true,
+		},
+		{
+			name:      "identical",
+			x:         []string{"foo", "bar", "baz"},
+			y:         []string{"foo", "bar", "baz"},
+			wantIndex: -1,
+			wantEqual: true,
+		},
+		{
+			name:      "x-prefix-of-y",
+			x:         []string{"foo", "bar"},
+			y:         []string{"foo", "bar", "baz"},
+			wantIndex: 2,
+		},
+		{
+			name:      "y-prefix-of-x",
+			x:         []string{"foo", "bar", "baz"},
+			y:         []string{"foo"},
+			wantIndex: 1,
+		},
+		{
+			name:      "x-empty",
+			y:         []string{"foo"},
+			wantIndex: 0,
+		},
+		{
+			name:      "disjoint",
+			x:         []string{"foo", "bar"},
+			y:         []string{"baz", "qux"},
+			wantIndex: 0,
+		},
+		{
+			name:      "same-prefix",
+			x:         []string{"foo", "bar", "baz"},
+			y:         []string{"foo", "qux", "baz"},
+			wantIndex: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index, equal := FirstDifference(tt.x, tt.y)
+			if index != tt.wantIndex || equal != tt.wantEqual {
+				t.Errorf("FirstDifference(...) = %v, %v, want %v, %v", index, equal, tt.wantIndex, tt.wantEqual)
+			}
+		})
+	}
+}
+
+func TestLCSLen(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y string
+		want int
+	}{
+		{name: "empty", want: 0},
+		{name: "x-empty", y: "abc", want: 0},
+		{name: "y-empty", x: "abc", want: 0},
+		{name: "identical", x: "abc", y: "abc", want: 3},
+		{name: "disjoint", x: "abc", y: "def", want: 0},
+		{name: "myers-paper", x: "ABCABBA", y: "CBABAC", want: 4},
+		{name: "common-prefix-and-suffix", x: "xxABCABBAyy", y: "xxCBABACyy", want: 8},
+		{name: "reversed", x: "abcdefg", y: "gfedcba", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y := strings.Split(tt.x, ""), strings.Split(tt.y, "")
+			if got := LCSLen(x, y); got != tt.want {
+				t.Errorf("LCSLen(%q, %q) = %d, want %d", tt.x, tt.y, got, tt.want)
+			}
+			if got := LCSLenFunc(x, y, func(a, b string) bool { return a == b }); got != tt.want {
+				t.Errorf("LCSLenFunc(%q, %q) = %d, want %d", tt.x, tt.y, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLCSLenRandom(t *testing.T) {
+	// bruteForce computes the LCS length using the textbook dynamic programming algorithm.
+	bruteForce := func(x, y []int) int {
+		dp := make([][]int, len(x)+1)
+		for i := range dp {
+			dp[i] = make([]int, len(y)+1)
+		}
+		for i := range x {
+			for j := range y {
+				if x[i] == y[j] {
+					dp[i+1][j+1] = dp[i][j] + 1
+				} else {
+					dp[i+1][j+1] = max(dp[i][j+1], dp[i+1][j])
+				}
+			}
+		}
+		return dp[len(x)][len(y)]
+	}
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := range 500 {
+		x := make([]int, rng.IntN(40))
+		for j := range x {
+			x[j] = rng.IntN(5)
+		}
+		y := make([]int, rng.IntN(40))
+		for j := range y {
+			y[j] = rng.IntN(5)
+		}
+		want := bruteForce(x, y)
+		if got := LCSLen(x, y); got != want {
+			t.Errorf("%d: LCSLen(%v, %v) = %d, want %d", i, x, y, got, want)
+		}
+		if got := LCSLenFunc(x, y, func(a, b int) bool { return a == b }); got != want {
+			t.Errorf("%d: LCSLenFunc(%v, %v) = %d, want %d", i, x, y, got, want)
+		}
+	}
+}
+
+func TestSimilarAtLeast(t *testing.T) {
+	tests := []struct {
+		name  string
+		x, y  string
+		ratio float64
+		want  bool
+	}{
+		{name: "empty", ratio: 1, want: true},
+		{name: "empty-above-one", ratio: 1.1, want: false},
+		{name: "x-empty", y: "abc", ratio: 0, want: true},
+		{name: "x-empty-positive", y: "abc", ratio: 0.01, want: false},
+		{name: "identical", x: "abc", y: "abc", ratio: 1, want: true},
+		{name: "identical-above-one", x: "abc", y: "abc", ratio: 1.1, want: false},
+		{name: "disjoint", x: "abc", y: "def", ratio: 0, want: true},
+		{name: "disjoint-positive", x: "abc", y: "def", ratio: 0.01, want: false},
+		// The LCS of the inputs from the Myers paper has length 4, the similarity is 8/13.
+		{name: "myers-paper-below", x: "ABCABBA", y: "CBABAC", ratio: 0.6, want: true},
+		{name: "myers-paper-exact", x: "ABCABBA", y: "CBABAC", ratio: 8.0 / 13, want: true},
+		{name: "myers-paper-above", x: "ABCABBA", y: "CBABAC", ratio: 0.62, want: false},
+		// The similarity is 12/20 = 0.6, which can't be represented exactly.
+		{name: "rounding-exact", x: "abcdefghij", y: "abcdefXXXX", ratio: 0.6, want: true},
+		{name: "rounding-above", x: "abcdefghij", y: "abcdefXXXX", ratio: 0.6000001, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y := strings.Split(tt.x, ""), strings.Split(tt.y, "")
+			if tt.x == "" {
+				x = nil
+			}
+			if tt.y == "" {
+				y = nil
+			}
+			if got := SimilarAtLeast(x, y, tt.ratio); got != tt.want {
+				t.Errorf("SimilarAtLeast(%q, %q, %v) = %v, want %v", tt.x, tt.y, tt.ratio, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSimilarAtLeastRandom(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := range 500 {
+		x := make([]int, rng.IntN(40))
+		for j := range x {
+			x[j] = rng.IntN(5)
+		}
+		y := slices.Clone(x)
+		for range rng.IntN(10) {
+			switch j := rng.IntN(len(y) + 1); {
+			case j < len(y) && rng.IntN(2) == 0:
+				y = slices.Delete(y, j, j+1)
+			default:
+				y = slices.Insert(y, j, rng.IntN(5))
+			}
+		}
+		if len(x)+len(y) == 0 {
+			continue
+		}
+		lcs := LCSLen(x, y)
+		similarity := 2 * float64(lcs) / float64(len(x)+len(y))
+		for _, ratio := range []float64{0, 0.25, 0.5, 0.75, 0.9, 1, similarity, math.Nextafter(similarity, 2)} {
+			want := similarity >= ratio
+			if got := SimilarAtLeast(x, y, ratio); got != want {
+				t.Errorf("%d: SimilarAtLeast(%v, %v, %v) = %v, want %v (LCS length %d)", i, x, y, ratio, got, want, lcs)
+			}
+		}
+	}
+}
+
+func TestPreferLongMatches(t *testing.T) {
+	// PreferLongMatches must only change which of several minimal diffs is returned.
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := range 500 {
+		x := make([]int, rng.IntN(40))
+		for j := range x {
+			x[j] = rng.IntN(4)
+		}
+		y := make([]int, rng.IntN(40))
+		for j := range y {
+			y[j] = rng.IntN(4)
+		}
+		want := editDistance(Edits(x, y, Minimal()))
+		if got := editDistance(Edits(x, y, Minimal(), PreferLongMatches())); got != want {
+			t.Errorf("%d: Edits(%v, %v, Minimal(), PreferLongMatches()) has %d edits, want %d", i, x, y, got, want)
+		}
+	}
+}
+
+func editDistance[T any](edits []Edit[T]) int {
+	n := 0
+	for _, e := range edits {
+		if e.Op.IsChange() {
+			n++
+		}
+	}
+	return n
+}
+
+func TestOp(t *testing.T) {
+	tests := []struct {
+		op           Op
+		wantIsChange bool
+		wantPrefix   string
+	}{
+		{Match, false, " "},
+		{Delete, true, "-"},
+		{Insert, true, "+"},
+		{Modify, true, "!"},
+		{Move, true, "~"},
+		{MoveModify, true, "~"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.op.String(), func(t *testing.T) {
+			if got := tt.op.IsChange(); got != tt.wantIsChange {
+				t.Errorf("%v.IsChange() = %v, want %v", tt.op, got, tt.wantIsChange)
+			}
+			if got := tt.op.Prefix(); got != tt.wantPrefix {
+				t.Errorf("%v.Prefix() = %q, want %q", tt.op, got, tt.wantPrefix)
+			}
+		})
+	}
+}
+
+func TestEditString(t *testing.T) {
+	tests := []struct {
+		edit Edit[string]
+		want string
+	}{
+		{Edit[string]{Op: Match, X: "a", Y: "a", PosX: 0, PosY: 0}, " a"},
+		{Edit[string]{Op: Delete, X: "a", PosX: 0, PosY: -1}, "-a"},
+		{Edit[string]{Op: Insert, Y: "a", PosX: -1, PosY: 0}, "+a"},
+		{Edit[string]{Op: Modify, X: "a", Y: "b", PosX: 0, PosY: 0}, "!a -> b"},
+		{Edit[string]{Op: Move, X: "a", PosX: 0, PosY: -1}, "~a"},
+		{Edit[string]{Op: Move, Y: "a", PosX: -1, PosY: 0}, "~a"},
+	}
+	for _, tt := range tests {
+		if got := tt.edit.String(); got != tt.want {
+			t.Errorf("%#v.String() = %q, want %q", tt.edit, got, tt.want)
+		}
+	}
+}
+
+func TestKeyed(t *testing.T) {
+	type record struct {
+		id    int
+		value string
+	}
+	key := func(r record) int { return r.id }
+
+	tests := []struct {
+		name string
+		x, y []record
+		want []Edit[record]
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "identical",
+			x:    []record{{1, "a"}, {2, "b"}},
+			y:    []record{{1, "a"}, {2, "b"}},
+			want: []Edit[record]{
+				{Op: Match, X: record{1, "a"}, Y: record{1, "a"}, PosX: 0, PosY: 0},
+				{Op: Match, X: record{2, "b"}, Y: record{2, "b"}, PosX: 1, PosY: 1},
+			},
+		},
+		{
+			name: "field-change",
+			x:    []record{{1, "a"}, {2, "b"}, {3, "c"}},
+			y:    []record{{1, "a"}, {2, "B"}, {3, "c"}},
+			want: []Edit[record]{
+				{Op: Match, X: record{1, "a"}, Y: record{1, "a"}, PosX: 0, PosY: 0},
+				{Op: Modify, X: record{2, "b"}, Y: record{2, "B"}, PosX: 1, PosY: 1},
+				{Op: Match, X: record{3, "c"}, Y: record{3, "c"}, PosX: 2, PosY: 2},
+			},
+		},
+		{
+			name: "insert-delete",
+			x:    []record{{1, "a"}, {2, "b"}},
+			y:    []record{{2, "B"}, {3, "c"}},
+			want: []Edit[record]{
+				{Op: Delete, X: record{1, "a"}, PosX: 0, PosY: -1},
+				{Op: Modify, X: record{2, "b"}, Y: record{2, "B"}, PosX: 1, PosY: 0},
+				{Op: Insert, Y: record{3, "c"}, PosX: -1, PosY: 1},
+			},
+		},
+		{
+			name: "reordered",
+			x:    []record{{1, "a"}, {2, "b"}, {3, "c"}},
+			y:    []record{{3, "c"}, {1, "a"}, {2, "B"}},
+			want: []Edit[record]{
+				{Op: Insert, Y: record{3, "c"}, PosX: -1, PosY: 0},
+				{Op: Match, X: record{1, "a"}, Y: record{1, "a"}, PosX: 0, PosY: 1},
+				{Op: Modify, X: record{2, "b"}, Y: record{2, "B"}, PosX: 1, PosY: 2},
+				{Op: Delete, X: record{3, "c"}, PosX: 2, PosY: -1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Keyed(tt.x, tt.y, key)
+			if diff := cmp.Diff(tt.want, got, cmp.AllowUnexported(record{})); diff != "" {
+				t.Errorf("Keyed(...) result is different (-want, +got):\n%s", diff)
+			}
+
+			got = KeyedFunc(tt.x, tt.y, key, func(a, b record) bool { return a == b })
+			if diff := cmp.Diff(tt.want, got, cmp.AllowUnexported(record{})); diff != "" {
+				t.Errorf("KeyedFunc(...) result is different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEditsProject(t *testing.T) {
+	// logEntry isn't comparable.
+	type logEntry struct {
+		Msg    string
+		Fields []string
+	}
+	msg := func(e logEntry) string { return e.Msg }
+
+	tests := []struct {
+		name string
+		x, y []logEntry
+		want []Edit[logEntry]
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "fields-ignored",
+			x:    []logEntry{{"start", []string{"t=1"}}, {"stop", []string{"t=2"}}},
+			y:    []logEntry{{"start", []string{"t=5"}}, {"stop", nil}},
+			want: []Edit[logEntry]{
+				{Op: Match, X: logEntry{"start", []string{"t=1"}}, Y: logEntry{"start", []string{"t=5"}}, PosX: 0, PosY: 0},
+				{Op: Match, X: logEntry{"stop", []string{"t=2"}}, Y: logEntry{"stop", nil}, PosX: 1, PosY: 1},
+			},
+		},
+		{
+			name: "message-change",
+			x:    []logEntry{{"start", []string{"t=1"}}, {"retry", []string{"t=2"}}, {"stop", []string{"t=3"}}},
+			y:    []logEntry{{"start", []string{"t=4"}}, {"fail", []string{"t=5"}}, {"stop", []string{"t=6"}}},
+			want: []Edit[logEntry]{
+				{Op: Match, X: logEntry{"start", []string{"t=1"}}, Y: logEntry{"start", []string{"t=4"}}, PosX: 0, PosY: 0},
+				{Op: Delete, X: logEntry{"retry", []string{"t=2"}}, PosX: 1, PosY: -1},
+				{Op: Insert, Y: logEntry{"fail", []string{"t=5"}}, PosX: -1, PosY: 1},
+				{Op: Match, X: logEntry{"stop", []string{"t=3"}}, Y: logEntry{"stop", []string{"t=6"}}, PosX: 2, PosY: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EditsProject(tt.x, tt.y, msg)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("EditsProject(...) result is different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+
+	// Projecting to the identity is the same as Edits.
+	x, y := spec{200, 200, 50}.generate([]byte{})
+	want := Edits(x, y)
+	got := EditsProject(x, y, func(v int) int { return v })
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("EditsProject(..., identity) is different from Edits(...) (-want, +got):\n%s", diff)
+	}
+}
+
+func TestApplyEdits(t *testing.T) {
+	for _, s := range []spec{{0, 0, 0}, {0, 50, 0}, {50, 0, 0}, {100, 100, 20}, {200, 150, 50}, {500, 500, 100}} {
+		x, y := s.generate([]byte{})
+		for _, opts := range [][]Option{nil, {Minimal()}, {Fast()}, {DetectSwaps()}} {
+			got, err := ApplyEdits(x, Edits(x, y, opts...))
+			if err != nil {
+				t.Errorf("ApplyEdits(x, Edits(x, y)) for %s with %d options returned error: %v", s.name(), len(opts), err)
+				continue
+			}
+			if !slices.Equal(got, y) {
+				t.Errorf("ApplyEdits(x, Edits(x, y)) for %s with %d options is different from y", s.name(), len(opts))
+			}
+		}
+	}
+
+	// Modify edits are applied from y.
+	type record struct {
+		id    int
+		value string
+	}
+	x := []record{{1, "a"}, {2, "b"}, {3, "c"}}
+	y := []record{{3, "c"}, {1, "a"}, {2, "B"}}
+	got, err := ApplyEdits(x, Keyed(x, y, func(r record) int { return r.id }))
+	if err != nil || !slices.Equal(got, y) {
+		t.Errorf("ApplyEdits(x, Keyed(x, y, ...)) = %v, %v, want %v, nil", got, err, y)
+	}
+}
+
+func TestApplyEditsError(t *testing.T) {
+	x := []string{"a", "b", "c"}
+	tests := []struct {
+		name  string
+		edits []Edit[string]
+		want  string
+	}{
+		{
+			name: "mismatch",
+			edits: []Edit[string]{
+				{Op: Match, X: "a", Y: "a"},
+				{Op: Delete, X: "c"},
+			},
+			want: "diff: edit 1 doesn't match element 1 of x",
+		},
+		{
+			name: "too-long",
+			edits: []Edit[string]{
+				{Op: Match, X: "a", Y: "a"},
+				{Op: Match, X: "b", Y: "b"},
+				{Op: Match, X: "c", Y: "c"},
+				{Op: Delete, X: "d"},
+			},
+			want: "diff: edit 3 refers to element 3 of x, but x has only 3 elements",
+		},
+		{
+			name: "incomplete",
+			edits: []Edit[string]{
+				{Op: Match, X: "a", Y: "a"},
+				{Op: Insert, Y: "z"},
+			},
+			want: "diff: edits only cover 1 of 3 elements of x",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyEdits(x, tt.edits)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("ApplyEdits(...) = %v, %v, want error %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPairedOrdering(t *testing.T) {
+	x := strings.Fields("a b c d e")
+	y := strings.Fields("a B C d E F")
+	want := []Hunk[string]{
+		{
+			PosX: 0, EndX: 5, PosY: 0, EndY: 6,
+			Edits: []Edit[string]{
+				{Op: Match, X: "a", Y: "a", PosX: 0, PosY: 0},
+				{Op: Delete, X: "b", PosX: 1, PosY: -1},
+				{Op: Insert, Y: "B", PosX: -1, PosY: 1},
+				{Op: Delete, X: "c", PosX: 2, PosY: -1},
+				{Op: Insert, Y: "C", PosX: -1, PosY: 2},
+				{Op: Match, X: "d", Y: "d", PosX: 3, PosY: 3},
+				{Op: Delete, X: "e", PosX: 4, PosY: -1},
+				{Op: Insert, Y: "E", PosX: -1, PosY: 4},
+				{Op: Insert, Y: "F", PosX: -1, PosY: 5},
+			},
+		},
+	}
+	got := Hunks(x, y, PairedOrdering())
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Hunks(...) result is different (-want, +got):\n%s", diff)
+	}
+	got = HunksFunc(x, y, func(a, b string) bool { return a == b }, PairedOrdering())
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("HunksFunc(...) result is different (-want, +got):\n%s", diff)
+	}
+}
+
+func TestDetectSwaps(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y []string
+		want []Edit[string]
+	}{
+		{
+			name: "swap",
+			x:    []string{"a", "b"},
+			y:    []string{"b", "a"},
+			want: []Edit[string]{
+				{Op: Move, Y: "b", PosX: -1, PosY: 0},
+				{Op: Match, X: "a", Y: "a", PosX: 0, PosY: 1},
+				{Op: Move, X: "b", PosX: 1, PosY: -1},
+			},
+		},
+		{
+			name: "no-false-positive",
+			x:    []string{"a", "b"},
+			y:    []string{"a", "c"},
+			want: []Edit[string]{
+				{Op: Match, X: "a", Y: "a", PosX: 0, PosY: 0},
+				{Op: Delete, X: "b", PosX: 1, PosY: -1},
+				{Op: Insert, Y: "c", PosX: -1, PosY: 1},
+			},
+		},
+		{
+			name: "partial",
+			x:    []string{"a", "b", "c"},
+			y:    []string{"b", "a", "d"},
+			want: []Edit[string]{
+				{Op: Move, Y: "b", PosX: -1, PosY: 0},
+				{Op: Match, X: "a", Y: "a", PosX: 0, PosY: 1},
+				{Op: Move, X: "b", PosX: 1, PosY: -1},
+				{Op: Delete, X: "c", PosX: 2, PosY: -1},
+				{Op: Insert, Y: "d", PosX: -1, PosY: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Edits(tt.x, tt.y, DetectSwaps())
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Edits(...) result is different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDetectModifiedMoves(t *testing.T) {
+	// The function f moves behind g and one of its lines changes.
+	x := []string{
+		"def f():",
+		"    a()",
+		"    b()",
+		"    c()",
+		"def g():",
+		"    d()",
+		"    e()",
+		"    f()",
+		"    g()",
+		"    h()",
+	}
+	y := []string{
+		"def g():",
+		"    d()",
+		"    e()",
+		"    f()",
+		"    g()",
+		"    h()",
+		"def f():",
+		"    a()",
+		"    B()",
+		"    c()",
+	}
+
+	got := Edits(x, y, DetectModifiedMoves(0.7))
+	var ops []Op
+	for _, e := range got {
+		ops = append(ops, e.Op)
+	}
+	wantOps := []Op{
+		MoveModify, MoveModify, MoveModify, MoveModify,
+		Match, Match, Match, Match, Match, Match,
+		MoveModify, MoveModify, MoveModify, MoveModify,
+	}
+	if diff := cmp.Diff(wantOps, ops); diff != "" {
+		t.Errorf("Edits(...) ops are different (-want, +got):\n%s", diff)
+	}
+	if y2, err := ApplyEdits(x, got); err != nil || !slices.Equal(y2, y) {
+		t.Errorf("ApplyEdits(x, Edits(...)) = %q, %v, want %q", y2, err, y)
+	}
+
+	wantBlocks := []MovedBlock[string]{
+		{
+			PosX: 0, EndX: 4, PosY: 6, EndY: 10,
+			Edits: []Edit[string]{
+				{Match, 0, 6, "def f():", "def f():"},
+				{Match, 1, 7, "    a()", "    a()"},
+				{Delete, 2, -1, "    b()", ""},
+				{Insert, -1, 8, "", "    B()"},
+				{Match, 3, 9, "    c()", "    c()"},
+			},
+		},
+	}
+	gotEdits, gotBlocks := EditsWithMovedBlocks(x, y, DetectModifiedMoves(0.7))
+	if diff := cmp.Diff(got, gotEdits); diff != "" {
+		t.Errorf("EditsWithMovedBlocks(...) edits are different (-want, +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(wantBlocks, gotBlocks); diff != "" {
+		t.Errorf("EditsWithMovedBlocks(...) blocks are different (-want, +got):\n%s", diff)
+	}
+
+	// With a higher threshold, the blocks are too different.
+	if _, got := EditsWithMovedBlocks(x, y, DetectModifiedMoves(0.9)); got != nil {
+		t.Errorf("EditsWithMovedBlocks(...) blocks = %v, want nil", got)
+	}
+	for _, e := range Edits(x, y, DetectModifiedMoves(0.9)) {
+		if e.Op == MoveModify {
+			t.Errorf("Edits(...) contains %v, want no MoveModify", e)
+		}
+	}
+}
+
+func TestDetectModifiedMovesInPlace(t *testing.T) {
+	// A replacement in place is never reported as a move, no matter how similar it is.
+	x := []string{"a", "b", "c", "d"}
+	y := []string{"a", "b", "C", "d"}
+	want := []Edit[string]{
+		{Match, 0, 0, "a", "a"},
+		{Match, 1, 1, "b", "b"},
+		{Delete, 2, -1, "c", ""},
+		{Insert, -1, 2, "", "C"},
+		{Match, 3, 3, "d", "d"},
+	}
+	if diff := cmp.Diff(want, Edits(x, y, DetectModifiedMoves(0.1))); diff != "" {
+		t.Errorf("Edits(...) result is different (-want, +got):\n%s", diff)
+	}
+}
+
+func TestDetectModifiedMovesLimit(t *testing.T) {
+	// Every block is moved, but only the first blocks are paired before the limit is reached.
+	x, y := movedBlocksInput(200)
+	got, blocks := EditsWithMovedBlocks(x, y, DetectModifiedMoves(0.7))
+	if len(blocks) == 0 || len(blocks) >= 200 {
+		t.Errorf("EditsWithMovedBlocks(...) found %d blocks, want between 1 and 199", len(blocks))
+	}
+	if y2, err := ApplyEdits(x, got); err != nil || !slices.Equal(y2, y) {
+		t.Errorf("ApplyEdits(x, EditsWithMovedBlocks(...)) = %v, %v, want %v", y2, err, y)
+	}
+}
+
+func TestStable(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y []string
+		opts []Option
+		want string
+	}{
+		{
+			name: "myers-paper",
+			x:    strings.Split("ABCABBA", ""),
+			y:    strings.Split("CBABAC", ""),
+			opts: []Option{Stable()},
+			want: "-A\n+C\n B\n-C\n A\n B\n-B\n A\n+C\n",
+		},
+		{
+			name: "reversed",
+			x:    strings.Split("abcdefg", ""),
+			y:    strings.Split("gfedcba", ""),
+			opts: []Option{Stable()},
+			want: "+g\n+f\n+e\n+d\n+c\n+b\n a\n-b\n-c\n-d\n-e\n-f\n-g\n",
+		},
+		{
+			name: "stable-before-fast",
+			x:    strings.Split("abcdefg", ""),
+			y:    strings.Split("gfedcba", ""),
+			opts: []Option{Stable(), Fast()},
+			want: "+g\n+f\n+e\n+d\n+c\n+b\n a\n-b\n-c\n-d\n-e\n-f\n-g\n",
+		},
+		{
+			name: "stable-after-fast",
+			x:    strings.Split("abcdefg", ""),
+			y:    strings.Split("gfedcba", ""),
+			opts: []Option{Fast(), Stable()},
+			want: "+g\n+f\n+e\n+d\n+c\n+b\n a\n-b\n-c\n-d\n-e\n-f\n-g\n",
+		},
+		{
+			name: "stable-with-auto-fast",
+			x:    strings.Split("abcdefg", ""),
+			y:    strings.Split("gfedcba", ""),
+			opts: []Option{Stable(), AutoFast(0)},
+			want: "+g\n+f\n+e\n+d\n+c\n+b\n a\n-b\n-c\n-d\n-e\n-f\n-g\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Report(tt.x, tt.y, tt.opts...)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Report(...) result is different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReport(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y []int
+		want string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "identical",
+			x:    []int{1, 2, 3},
+			y:    []int{1, 2, 3},
+		},
+		{
+			name: "changed",
+			x:    []int{1, 2, 3},
+			y:    []int{1, 4, 3},
+			want: " 1\n-2\n+4\n 3\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Report(tt.x, tt.y)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Report(...) result is different (-want, +got):\n%s", diff)
+			}
+
+			got = ReportFunc(tt.x, tt.y, func(a, b int) bool { return a == b }, strconv.Itoa)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ReportFunc(...) result is different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func BenchmarkHunks(b *testing.B) {
 	for _, s := range benchmarkSpecs {
 		b.Run(s.name(), func(b *testing.B) {
@@ -438,6 +2030,317 @@ func BenchmarkEdits(b *testing.B) {
 	}
 }
 
+func TestRuns(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y string
+		want []Run[byte]
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "identical",
+			x:    "abc",
+			y:    "abc",
+			want: []Run[byte]{
+				{Op: Match, X: []byte("abc"), Y: []byte("abc"), PosX: 0, PosY: 0},
+			},
+		},
+		{
+			name: "x-empty",
+			y:    "abc",
+			want: []Run[byte]{
+				{Op: Insert, Y: []byte("abc"), PosX: -1, PosY: 0},
+			},
+		},
+		{
+			name: "y-empty",
+			x:    "abc",
+			want: []Run[byte]{
+				{Op: Delete, X: []byte("abc"), PosX: 0, PosY: -1},
+			},
+		},
+		{
+			name: "replace",
+			x:    "abXYcd",
+			y:    "abZcd",
+			want: []Run[byte]{
+				{Op: Match, X: []byte("ab"), Y: []byte("ab"), PosX: 0, PosY: 0},
+				{Op: Delete, X: []byte("XY"), PosX: 2, PosY: -1},
+				{Op: Insert, Y: []byte("Z"), PosX: -1, PosY: 2},
+				{Op: Match, X: []byte("cd"), Y: []byte("cd"), PosX: 4, PosY: 3},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Runs([]byte(tt.x), []byte(tt.y))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Runs(...) result is different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOpString(t *testing.T) {
+	// The test cases match the ones for the internal diff implementation.
+	tests := []struct {
+		name    string
+		x, y    []string
+		want    string
+		wantRLE string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:    "identical",
+			x:       []string{"foo", "bar", "baz"},
+			y:       []string{"foo", "bar", "baz"},
+			want:    "MMM",
+			wantRLE: "M3",
+		},
+		{
+			name:    "x-empty",
+			y:       []string{"foo", "bar", "baz"},
+			want:    "III",
+			wantRLE: "I3",
+		},
+		{
+			name:    "y-empty",
+			x:       []string{"foo", "bar", "baz"},
+			want:    "DDD",
+			wantRLE: "D3",
+		},
+		{
+			name:    "ABCABBA_to_CBABAC",
+			x:       strings.Split("ABCABBA", ""),
+			y:       strings.Split("CBABAC", ""),
+			want:    "DIMDMMDMI",
+			wantRLE: "D I M D M2 D M I",
+		},
+		{
+			name:    "same-prefix",
+			x:       []string{"foo", "bar"},
+			y:       []string{"foo", "baz"},
+			want:    "MDI",
+			wantRLE: "M D I",
+		},
+		{
+			name:    "same-suffix",
+			x:       []string{"foo", "bar"},
+			y:       []string{"loo", "bar"},
+			want:    "DIM",
+			wantRLE: "D I M",
+		},
+		{
+			name:    "largish",
+			x:       strings.Split("xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaay", ""),
+			y:       strings.Split("waaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaait", ""),
+			want:    "DIMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMMDII",
+			wantRLE: "D I M71 D I2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := OpString(tt.x, tt.y); got != tt.want {
+				t.Errorf("OpString(...) = %q, want %q", got, tt.want)
+			}
+			if got := OpStringRLE(tt.x, tt.y); got != tt.wantRLE {
+				t.Errorf("OpStringRLE(...) = %q, want %q", got, tt.wantRLE)
+			}
+		})
+	}
+}
+
+func TestRunsEquivalentToEdits(t *testing.T) {
+	for _, s := range benchmarkSpecs {
+		t.Run(s.name(), func(t *testing.T) {
+			x, y := s.generate([]byte{})
+			var got []Edit[int]
+			for _, r := range Runs(x, y) {
+				for i := range max(len(r.X), len(r.Y)) {
+					e := Edit[int]{Op: r.Op, PosX: -1, PosY: -1}
+					if r.X != nil {
+						e.X, e.PosX = r.X[i], r.PosX+i
+					}
+					if r.Y != nil {
+						e.Y, e.PosY = r.Y[i], r.PosY+i
+					}
+					got = append(got, e)
+				}
+			}
+			if want := Edits(x, y); !slices.Equal(want, got) {
+				t.Errorf("Runs(...) expanded to edits is different from Edits(...) (-want, +got):\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestAlign(t *testing.T) {
+	x := strings.Split("ABCABBA", "")
+	y := strings.Split("CBABAC", "")
+
+	// row describes an AlignRow by the positions of the elements X and Y point to, or -1 if nil.
+	type row struct {
+		Op         Op
+		PosX, PosY int
+	}
+	pos := func(p *string, s []string) int {
+		for i := range s {
+			if p == &s[i] {
+				return i
+			}
+		}
+		return -1
+	}
+	toRows := func(in []AlignRow[string]) []row {
+		var out []row
+		for _, r := range in {
+			out = append(out, row{r.Op, pos(r.X, x), pos(r.Y, y)})
+		}
+		return out
+	}
+
+	want := []row{
+		{Delete, 0, -1},
+		{Insert, -1, 0},
+		{Match, 1, 1},
+		{Delete, 2, -1},
+		{Match, 3, 2},
+		{Match, 4, 3},
+		{Delete, 5, -1},
+		{Match, 6, 4},
+		{Insert, -1, 5},
+	}
+	if diff := cmp.Diff(want, toRows(Align(x, y))); diff != "" {
+		t.Errorf("Align(...) result is different (-want, +got):\n%s", diff)
+	}
+	got := AlignFunc(x, y, func(a, b string) bool { return a == b })
+	if diff := cmp.Diff(want, toRows(got)); diff != "" {
+		t.Errorf("AlignFunc(...) result is different (-want, +got):\n%s", diff)
+	}
+
+	if got := Align([]string{}, nil); got != nil {
+		t.Errorf("Align(empty, nil) = %v, want nil", got)
+	}
+}
+
+func BenchmarkRuns(b *testing.B) {
+	// A noisy byte pair: y is x with every 8th byte changed.
+	x := make([]byte, 1<<14)
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := range x {
+		x[i] = byte(rng.IntN(256))
+	}
+	y := slices.Clone(x)
+	for i := 0; i < len(y); i += 8 {
+		y[i]++
+	}
+	b.Run("Runs", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = Runs(x, y)
+		}
+	})
+	b.Run("Edits", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			_ = Edits(x, y)
+		}
+	})
+}
+
+func TestWalkEditsStop(t *testing.T) {
+	x := strings.Split("ABCABBA", "")
+	y := strings.Split("CBABAC", "")
+	want := Edits(x, y)[:3]
+	var got []Edit[string]
+	WalkEdits(x, y, func(e Edit[string]) bool {
+		got = append(got, e)
+		return len(got) < 3
+	})
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("WalkEdits(...) result is different (-want, +got):\n%s", diff)
+	}
+}
+
+func TestWalkEditsAllocs(t *testing.T) {
+	// WalkEdits doesn't allocate anything beyond what the diff computation allocates.
+	for _, s := range []spec{{100, 100, 10}, {500, 500, 100}, {1000, 2000, 1500}} {
+		x, y := s.generate([]byte{})
+		want := testing.AllocsPerRun(10, func() {
+			impl.Diff(x, y, config.FromOptions(nil, diffFlags))
+		})
+		got := testing.AllocsPerRun(10, func() {
+			WalkEdits(x, y, func(Edit[int]) bool { return true })
+		})
+		if got > want {
+			t.Errorf("%s: WalkEdits(...) allocates %v times, want at most %v like the diff computation", s.name(), got, want)
+		}
+	}
+}
+
+func BenchmarkWalkEdits(b *testing.B) {
+	for _, s := range benchmarkSpecs {
+		b.Run(s.name(), func(b *testing.B) {
+			b.ReportAllocs()
+			x, y := s.generate([]byte{})
+			for b.Loop() {
+				WalkEdits(x, y, func(Edit[int]) bool { return true })
+			}
+		})
+	}
+}
+
+func BenchmarkDetectModifiedMoves(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		x, y := movedBlocksInput(n)
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				_, _ = EditsWithMovedBlocks(x, y, DetectModifiedMoves(0.7))
+			}
+		})
+	}
+}
+
+// movedBlocksInput returns n blocks of 10 elements, separated by unchanged elements. In y, every
+// block moves to the previous gap and one of its elements is modified.
+func movedBlocksInput(n int) (x, y []int) {
+	block := func(i int, modified bool) []int {
+		var v []int
+		for j := range 10 {
+			if modified && j == 5 {
+				v = append(v, -10*i-j-1)
+				continue
+			}
+			v = append(v, 10*i+j)
+		}
+		return v
+	}
+	for i := range n {
+		x = append(x, -1_000_000-i)
+		x = append(x, block(i, false)...)
+		y = append(y, -1_000_000-i)
+		y = append(y, block((i+1)%n, true)...)
+	}
+	return x, y
+}
+
+func BenchmarkParallel(b *testing.B) {
+	x, y := parallelInput(1_000_000)
+	for _, n := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				_ = Edits(x, y, Parallel(n))
+			}
+		})
+	}
+}
+
 func BenchmarkEditsFunc(b *testing.B) {
 	for _, s := range benchmarkSpecs {
 		b.Run(s.name(), func(b *testing.B) {
@@ -508,3 +2411,190 @@ func (s spec) generate(seed []byte) (x, y []int) {
 	}
 	return
 }
+
+func TestSetDiff(t *testing.T) {
+	tests := []struct {
+		name                     string
+		x, y                     string
+		wantX, wantY, wantCommon string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:       "identical",
+			x:          "abc",
+			y:          "abc",
+			wantCommon: "abc",
+		},
+		{
+			name:       "reordered",
+			x:          "abc",
+			y:          "cab",
+			wantCommon: "abc",
+		},
+		{
+			name:  "disjoint",
+			x:     "abc",
+			y:     "def",
+			wantX: "abc",
+			wantY: "def",
+		},
+		{
+			name:       "duplicates",
+			x:          "aaabc",
+			y:          "cbaxa",
+			wantX:      "a",
+			wantY:      "x",
+			wantCommon: "aabc",
+		},
+		{
+			name:       "duplicates-in-y",
+			x:          "ab",
+			y:          "bbbab",
+			wantY:      "bbb",
+			wantCommon: "ab",
+		},
+	}
+	split := func(s string) []string {
+		if s == "" {
+			return nil
+		}
+		return strings.Split(s, "")
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			onlyX, onlyY, common := SetDiff(split(tt.x), split(tt.y))
+			if diff := cmp.Diff(split(tt.wantX), onlyX); diff != "" {
+				t.Errorf("SetDiff(...) onlyX is different (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(split(tt.wantY), onlyY); diff != "" {
+				t.Errorf("SetDiff(...) onlyY is different (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(split(tt.wantCommon), common); diff != "" {
+				t.Errorf("SetDiff(...) common is different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSortedEdits(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y []int
+		want []Edit[int]
+	}{
+		{
+			name: "empty",
+			want: []Edit[int]{},
+		},
+		{
+			name: "identical",
+			x:    []int{1, 2},
+			y:    []int{1, 2},
+			want: []Edit[int]{
+				{Op: Match, PosX: 0, PosY: 0, X: 1, Y: 1},
+				{Op: Match, PosX: 1, PosY: 1, X: 2, Y: 2},
+			},
+		},
+		{
+			name: "interleaved",
+			x:    []int{1, 3, 5, 6},
+			y:    []int{2, 3, 4, 6, 7},
+			want: []Edit[int]{
+				{Op: Delete, PosX: 0, PosY: -1, X: 1},
+				{Op: Insert, PosX: -1, PosY: 0, Y: 2},
+				{Op: Match, PosX: 1, PosY: 1, X: 3, Y: 3},
+				{Op: Insert, PosX: -1, PosY: 2, Y: 4},
+				{Op: Delete, PosX: 2, PosY: -1, X: 5},
+				{Op: Match, PosX: 3, PosY: 3, X: 6, Y: 6},
+				{Op: Insert, PosX: -1, PosY: 4, Y: 7},
+			},
+		},
+		{
+			name: "duplicates",
+			x:    []int{1, 1, 1, 2},
+			y:    []int{1, 2, 2},
+			want: []Edit[int]{
+				{Op: Match, PosX: 0, PosY: 0, X: 1, Y: 1},
+				{Op: Delete, PosX: 1, PosY: -1, X: 1},
+				{Op: Delete, PosX: 2, PosY: -1, X: 1},
+				{Op: Match, PosX: 3, PosY: 1, X: 2, Y: 2},
+				{Op: Insert, PosX: -1, PosY: 2, Y: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SortedEdits(tt.x, tt.y)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("SortedEdits(...) result is different (-want, +got):\n%s", diff)
+			}
+			got = SortedEditsFunc(tt.x, tt.y, func(a, b int) int { return a - b })
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("SortedEditsFunc(...) result is different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSortedEditsRandom(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := range 200 {
+		sorted := i%2 == 0
+		x := make([]int, rng.IntN(50))
+		y := make([]int, rng.IntN(50))
+		for j := range x {
+			x[j] = rng.IntN(20)
+		}
+		for j := range y {
+			y[j] = rng.IntN(20)
+		}
+		if sorted {
+			slices.Sort(x)
+			slices.Sort(y)
+		}
+
+		edits := SortedEdits(x, y)
+		var gotX, gotY []int
+		for _, e := range edits {
+			if e.PosX >= 0 {
+				gotX = append(gotX, e.X)
+			}
+			if e.PosY >= 0 {
+				gotY = append(gotY, e.Y)
+			}
+		}
+		if !slices.Equal(x, gotX) || !slices.Equal(y, gotY) {
+			t.Fatalf("%d: SortedEdits(%v, %v) doesn't reproduce the inputs: got %v, %v", i, x, y, gotX, gotY)
+		}
+		if !sorted {
+			continue // the edits are valid, but not necessarily minimal
+		}
+		if got, want := editDistance(edits), editDistance(Edits(x, y, Minimal())); got != want {
+			t.Errorf("%d: SortedEdits(%v, %v) has %d edits, want %d", i, x, y, got, want)
+		}
+	}
+}
+
+func BenchmarkSortedEdits(b *testing.B) {
+	for _, s := range benchmarkSpecs {
+		x, y := s.generate([]byte{})
+		slices.Sort(x)
+		slices.Sort(y)
+		b.Run(s.name(), func(b *testing.B) {
+			b.Run("SortedEdits", func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					_ = SortedEdits(x, y)
+				}
+			})
+			b.Run("Edits", func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					_ = Edits(x, y)
+				}
+			})
+		})
+	}
+}
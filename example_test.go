@@ -16,6 +16,7 @@ package diff_test
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"znkr.io/diff"
@@ -158,3 +159,51 @@ func ExampleContext() {
 	// Output:
 	// [-calm-] {+restless+} seas … sky {+defiantly+}
 }
+
+func ExampleReport() {
+	want := []int{1, 2, 3, 4, 5}
+	got := []int{1, 2, 4, 5, 6}
+	fmt.Print(diff.Report(want, got))
+	// Output:
+	//  1
+	//  2
+	// -3
+	//  4
+	//  5
+	// +6
+}
+
+func ExampleReportFunc() {
+	want := []string{"Alpha", "Beta", "Gamma"}
+	got := []string{"alpha", "beta", "delta"}
+	fmt.Print(diff.ReportFunc(want, got, strings.EqualFold, strconv.Quote))
+	// Output:
+	//  "Alpha"
+	//  "Beta"
+	// -"Gamma"
+	// +"delta"
+}
+
+func ExampleHunk_String() {
+	x := []string{"a", "b", "c", "d"}
+	y := []string{"a", "B", "c", "d", "e"}
+	for _, h := range diff.Hunks(x, y, diff.Context(1)) {
+		fmt.Print(h)
+	}
+	// Output:
+	// @@ -1,4 +1,5 @@
+	//  a
+	// -b
+	// +B
+	//  c
+	//  d
+	// +e
+}
+
+func ExampleEdit_String() {
+	x := []int{1, 2, 3}
+	y := []int{1, 3, 4}
+	fmt.Println(diff.Edits(x, y))
+	// Output:
+	// [ 1 -2  3 +4]
+}
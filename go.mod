@@ -9,6 +9,7 @@ tool (
 
 require (
 	github.com/google/go-cmp v0.7.0
+	golang.org/x/text v0.34.0
 	golang.org/x/tools v0.42.0
 )
 
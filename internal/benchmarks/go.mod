@@ -12,4 +12,6 @@ require (
 	znkr.io/diff v0.0.0-20250814195549-58fd23adf4e1
 )
 
+require golang.org/x/text v0.34.0 // indirect
+
 replace znkr.io/diff => ../..
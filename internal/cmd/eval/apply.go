@@ -0,0 +1,72 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"znkr.io/diff"
+	"znkr.io/diff/textdiff"
+)
+
+// applyPatch applies a unified diff to orig without using the unix patch tool. Unlike patch, it
+// doesn't search for the hunk positions, every hunk must apply exactly at the given position.
+func applyPatch(orig, patch string) (string, error) {
+	if len(patch) == 0 {
+		return orig, nil
+	}
+	files, err := textdiff.SplitFiles("--- orig\n+++ out\n" + patch)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse patch: %v", err)
+	}
+	if len(files) != 1 {
+		return "", fmt.Errorf("expected a patch for one file, got %d", len(files))
+	}
+
+	lines := strings.SplitAfter(orig, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	var b strings.Builder
+	pos := 0
+	for _, h := range files[0].Hunks {
+		// Diffs of an empty file use the hunk header "-1,0" for an empty range at the start.
+		start := min(h.LineNoX, len(lines))
+		if start < pos {
+			return "", fmt.Errorf("hunk at line %d overlaps the previous hunk", h.LineNoX+1)
+		}
+		for _, line := range lines[pos:start] {
+			b.WriteString(line)
+		}
+		pos = start
+		for _, edit := range h.Edits {
+			if edit.Op != diff.Insert {
+				if pos >= len(lines) || lines[pos] != edit.Line {
+					return "", fmt.Errorf("hunk doesn't apply at line %d", pos+1)
+				}
+				pos++
+			}
+			if edit.Op != diff.Delete {
+				b.WriteString(edit.Line)
+			}
+		}
+	}
+	for _, line := range lines[pos:] {
+		b.WriteString(line)
+	}
+	return b.String(), nil
+}
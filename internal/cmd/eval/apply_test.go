@@ -0,0 +1,106 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"testing"
+
+	"znkr.io/diff"
+	"znkr.io/diff/internal/unixpatch"
+	"znkr.io/diff/textdiff"
+)
+
+var validate = flag.Bool("validate", false, "perform validation using the unix patch cli tool")
+
+func TestApplyPatch(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y string
+		opts []diff.Option
+	}{
+		{
+			name: "identical",
+			x:    "a\nb\nc\n",
+			y:    "a\nb\nc\n",
+		},
+		{
+			name: "x-empty",
+			y:    "a\nb\nc\n",
+		},
+		{
+			name: "y-empty",
+			x:    "a\nb\nc\n",
+		},
+		{
+			name: "multiple-hunks",
+			x:    "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\n",
+			y:    "a\nB\nc\nd\ne\nf\ng\nh\nI\nj\nk\n",
+			opts: []diff.Option{diff.Context(1)},
+		},
+		{
+			name: "missing-newline-x",
+			x:    "a\nb\nc",
+			y:    "a\nb\nc\n",
+		},
+		{
+			name: "missing-newline-y",
+			x:    "a\nb\nc\n",
+			y:    "a\nB\nc",
+		},
+		{
+			name: "missing-newline-both",
+			x:    "a\nb\nc",
+			y:    "A\nb\nc",
+		},
+		{
+			name: "missing-newline-changed-last-line",
+			x:    "a\nb\nc",
+			y:    "a\nb\nC",
+		},
+	}
+	appliers := []struct {
+		name     string
+		patch    func(orig, patch string) (string, error)
+		external bool // Set if the applier needs an external tool, only used with -validate.
+	}{
+		{"library", applyPatch, false},
+		{"unix", unixpatch.Patch, true},
+	}
+	for _, tt := range tests {
+		patch := textdiff.Unified(tt.x, tt.y, tt.opts...)
+		for _, a := range appliers {
+			t.Run(tt.name+"/"+a.name, func(t *testing.T) {
+				if a.external && !*validate {
+					t.Skip("requires -validate")
+				}
+				got, err := a.patch(tt.x, patch)
+				if err != nil {
+					t.Fatalf("failed to apply patch:\n%s\nerror: %v", patch, err)
+				}
+				if got != tt.y {
+					t.Errorf("applying patch:\n%s\nresult is %q, want %q", patch, got, tt.y)
+				}
+			})
+		}
+	}
+}
+
+func TestApplyPatchMismatch(t *testing.T) {
+	patch := textdiff.Unified("a\nb\nc\n", "a\nB\nc\n")
+	if _, err := applyPatch("a\nx\nc\n", patch); err == nil {
+		t.Errorf("applyPatch(...) succeeded for a patch that doesn't apply")
+	}
+}
@@ -13,7 +13,8 @@
 // limitations under the License.
 
 // eval provides a way to validate the diffing algorithm by applying the resulting diffs using
-// the unix patch tool and checking that they produce the input again.
+// the unix patch tool (or a patch implementation based on this module with -applier=library) and
+// checking that they produce the input again.
 package main
 
 import (
@@ -42,6 +43,7 @@ type config struct {
 	parallel int
 	stats    string
 	validate bool
+	applier  string
 }
 
 func main() {
@@ -51,6 +53,7 @@ func main() {
 	flag.IntVar(&cfg.parallel, "parallel", runtime.GOMAXPROCS(0), "number of evaluations to run in parallel")
 	flag.StringVar(&cfg.stats, "stats", "", "file to store stats in")
 	flag.BoolVar(&cfg.validate, "validate", true, "if validation should be performed")
+	flag.StringVar(&cfg.applier, "applier", "unix", "patch implementation used for validation: unix or library")
 	flag.Parse()
 
 	if len(flag.CommandLine.Args()) > 0 {
@@ -58,6 +61,11 @@ func main() {
 		os.Exit(1)
 	}
 
+	if cfg.applier != "unix" && cfg.applier != "library" {
+		fmt.Fprintf(os.Stderr, "error: unknown applier %q, want unix or library\n", cfg.applier)
+		os.Exit(1)
+	}
+
 	if err := run(&cfg); err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
@@ -106,6 +114,11 @@ func run(cfg *config) error {
 		}
 	}
 
+	patch := unixpatch.Patch
+	if cfg.applier == "library" {
+		patch = applyPatch
+	}
+
 	git, err := git.Open(cfg.repo)
 	if err != nil {
 		return fmt.Errorf("opening git repository: %v", err)
@@ -233,7 +246,7 @@ func run(cfg *config) error {
 
 					if cfg.validate {
 						unified := textdiff.Unified(change.old, change.new, opts...)
-						patched, err := unixpatch.Patch(change.old, unified)
+						patched, err := patch(change.old, unified)
 						if err != nil {
 							notes <- note{
 								prefix: change.commitID + ":" + change.filename,
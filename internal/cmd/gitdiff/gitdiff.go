@@ -69,12 +69,14 @@ func run(args []string) error {
 		}
 	}
 
-	diff := textdiff.Unified(old, new, textdiff.IndentHeuristic(), diff.Context(20))
+	diff := textdiff.Unified(old, new,
+		textdiff.IndentHeuristic(),
+		diff.Context(20),
+		textdiff.IndexHeader(oldHex[:10], newHex[:10], newMode),
+		textdiff.Labels("a/"+path, "b/"+path),
+	)
 
 	fmt.Printf("diff --git a/%s b/%s\n", path, path)
-	fmt.Printf("index %s..%s %s\n", oldHex[:10], newHex[:10], newMode)
-	fmt.Printf("--- a/%s\n", path)
-	fmt.Printf("+++ b/%s\n", path)
 	os.Stdout.Write(diff)
 
 	return nil
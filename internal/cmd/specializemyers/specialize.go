@@ -120,6 +120,9 @@ var methodsOfInterest = map[string]bool{
 	"split":   true,
 	"compare": true,
 	"init":    true,
+	"tune":    true,
+
+	"exceedsDeadline": true,
 }
 
 func isMyersT(ts *ast.TypeSpec) bool {
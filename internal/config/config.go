@@ -18,6 +18,12 @@
 // diff.Option.
 package config
 
+import (
+	"time"
+
+	"znkr.io/diff/internal/indentheuristic"
+)
+
 // Mode describes the mode of the diff algorithm.
 type Mode int
 
@@ -38,18 +44,147 @@ type Config struct {
 	// Context is the number of matches to include as a prefix and postfix for hunks returned.
 	Context int
 
+	// If > 0, rvecs.Hunks stops after MaxHunks hunks.
+	MaxHunks int
+
 	// Diff algorithm mode.
 	Mode Mode
 
+	// If set, internal/impl always uses ModeMinimal, regardless of Mode and AutoFast.
+	Stable bool
+
+	// If > 0, internal/impl stops searching for a minimal diff after MinimalBudget and falls back
+	// to the heuristics of ModeDefault. Ignored if Stable is set.
+	MinimalBudget time.Duration
+
+	// If set, internal/impl switches to ModeFast when the product of the input lengths (after
+	// removing common prefixes and suffixes) exceeds AutoFastMaxProduct.
+	AutoFast           bool
+	AutoFastMaxProduct int
+
+	// If > 0, overrides the cost limit of the TOO_EXPENSIVE heuristic.
+	CostLimit int
+
+	// If GoodDiagMinLen > 0, overrides the parameters of the GOOD_DIAGONAL heuristic.
+	GoodDiagMinLen, GoodDiagCostLimit, GoodDiagMagic int
+
+	// If set, internal/impl prefers the longest run of matches among equally good split points.
+	PreferLongMatches bool
+
+	// If > 0, diff.EditsFloat treats values as equal if they differ by at most FloatTolerance.
+	FloatTolerance float64
+
+	// If > 0, diff.EditsFuncChecked gives up after MaxWork iterations of the diff algorithm.
+	MaxWork int
+
+	// If > 1, the ranges between anchors are compared on up to Parallel goroutines.
+	Parallel int
+
+	// If set, hunks interleave runs of deletions and insertions of the same length.
+	PairedOrdering bool
+
+	// If set, diff.Edits reports deletions and insertions of identical elements as moves.
+	DetectSwaps bool
+
+	// If > 0, diff.Edits reports runs of deletions and insertions with at least this similarity as
+	// moved and modified blocks.
+	ModifiedMovesThreshold float64
+
 	// If set, textdiff will apply ident heuristics.
 	IndentHeuristic bool
 
+	// If not nil, the indent heuristic uses these weights instead of the default weights.
+	IndentHeuristicWeights *indentheuristic.Weights
+
+	// If not nil, the indent heuristic doesn't place groups of changes whose lines all satisfy
+	// IndentHeuristicExcept.
+	IndentHeuristicExcept func(line string) bool
+
+	// If not nil, textdiff compares the keys returned by LineKey instead of the lines themselves.
+	// The output is still rendered from the original lines.
+	LineKey func(line string) string
+
+	// If not nil, textdiff uses lines for which Anchor returns true and that are unique in both
+	// inputs as fixed matches and compares the lines between them independently.
+	Anchor func(line string) bool
+
+	// If not nil, textdiff treats maximal runs of lines for which ReorderedBlock returns true as
+	// unordered if a run in x and a run in y contain the same lines.
+	ReorderedBlock func(line string) bool
+
+	// If set, textdiff doesn't compare inputs line by line if one of them looks binary.
+	BinaryDetection bool
+
+	// If not nil, textdiff expands hunks to the innermost block that encloses their changes. The
+	// nesting depth of a block is the sum of BlockContext over all lines in front of it.
+	BlockContext func(line string) int
+
+	// If set, textdiff expands hunks to the enclosing functions. FunctionContext reports whether a
+	// line starts a function.
+	FunctionContext func(line string) bool
+
+	// If > 0, textdiff splits hunks with more edits into multiple hunks.
+	MaxHunkLines int
+
+	// If > 0, textdiff omits hunks with fewer inserted and deleted lines.
+	MinHunkChanges int
+
+	// If > 0, textdiff treats deleted and inserted lines that are paired in a change as matches if
+	// their similarity is at least FuzzyLinesThreshold.
+	FuzzyLinesThreshold float64
+
+	// If set, textdiff reports deleted and inserted lines that are paired in a change and only
+	// differ in their leading whitespace as reindented.
+	MarkReindent bool
+
 	// If not nil, textdiff.Unify will use this to color the output.
 	Colors *ColorConfig
 
+	// Labels for conflict markers in textdiff.Merge3.
+	ConflictLabelX, ConflictLabelY string
+
+	// If not empty, textdiff.UnifiedCompact separates hunks using this line instead of hunk headers.
+	HunkSeparator string
+
+	// If > 0, textdiff.Unified truncates lines longer than MaxLineWidth runes.
+	MaxLineWidth int
+
+	// If set, textdiff.Unified replaces invalid UTF-8 in the output with U+FFFD.
+	SanitizeInvalidUTF8 bool
+
+	// If set, textdiff.Unified renders leading and trailing tabs and trailing spaces as visible
+	// glyphs.
+	ShowWhitespace bool
+
+	// If set, textdiff.Unified appends the number of inserted and deleted lines to hunk headers.
+	HunkStatsInHeader bool
+
+	// Line separator for structural lines in the output of textdiff.Unified. If empty, "\n" is
+	// used.
+	OutputNewline string
+
+	// If set, textdiff.Unified prints a "---" and "+++" file header with these labels.
+	LabelX, LabelY string
+
+	// If set, textdiff.Unified prints a git "index" line with these hashes and mode.
+	IndexOld, IndexNew, IndexMode string
+
 	// If set, internal/myers will always use the anchoring heuristic. This configuration is not
 	// exposed via an option API, it's main use is for testing.
 	ForceAnchoringHeuristic bool
+
+	// If set, textdiff identifies equal lines by their fingerprints, even for small inputs. This
+	// configuration is not exposed via an option API, it's main use is for testing.
+	ForceFingerprintLines bool
+
+	// If set, FromOptions disables all heuristics and forces ModeMinimal, so that every diff is
+	// computed by the optimal split of internal/impl. This configuration is only exposed via an
+	// experimental option, see diff.DeterministicSplit.
+	DeterministicSplit bool
+
+	// If > 1, elements that appear up to AnchorMaxCount times in x and y are used as anchors. This
+	// configuration is only exposed via an experimental option, see diff.AnchorMaxCount.
+	AnchorMaxCount int
 }
 
 type ColorConfig struct {
@@ -76,11 +211,62 @@ const (
 	Fast
 	IndentHeuristic
 	TerminalColors
+	AutoFast
+	NormalizeUnicode
+	MaxLineWidth
+	ConflictLabels
+	PairedOrdering
+	DetectSwaps
+	FuzzyLines
+	HunkSeparator
+	CostLimit
+	AnchorOn
+	MaxWork
+	IgnoreMatching
+	GoodDiagonalTuning
+	FloatTolerance
+	MarkReindent
+	MaxHunks
+	PreferLongMatches
+	SanitizeInvalidUTF8
+	OutputNewline
+	Labels
+	IndexHeader
+	Parallel
+	IgnoreReorderedBlocks
+	BinaryDetection
+	BlockContext
+	DetectModifiedMoves
+	IgnoreComments
+	MaxHunkLines
+	ShowWhitespace
+	FunctionContext
+	HunkStatsInHeader
+	MinHunkChanges
+	DeterministicSplit
+	AnchorMaxCount
 )
 
+// Algorithm is the set of flags for options that select and tune the diff algorithm. Every function
+// that compares comparable elements supports them.
+const Algorithm = Minimal | PreferLongMatches | Fast | AutoFast | Parallel | DeterministicSplit | AnchorMaxCount
+
 // Option is the mechanism used to expose the configuration to users.
 type Option func(*Config) Flag
 
+// AddLineKey adds a transformation to the line key. If a line key is already set, key is applied
+// to its result.
+func (cfg *Config) AddLineKey(key func(line string) string) {
+	prev := cfg.LineKey
+	if prev == nil {
+		cfg.LineKey = key
+		return
+	}
+	cfg.LineKey = func(line string) string {
+		return key(prev(line))
+	}
+}
+
 // FromOptions creates a configuration from a set of options.
 func FromOptions(opts []Option, allowed Flag) Config {
 	cfg := Default
@@ -90,6 +276,16 @@ func FromOptions(opts []Option, allowed Flag) Config {
 			panic("Option " + printFlag(flag) + " not allowed here")
 		}
 	}
+	if cfg.DeterministicSplit {
+		cfg.Mode = ModeMinimal
+		cfg.Stable = true
+		cfg.AutoFast = false
+		cfg.Parallel = 0
+		cfg.PreferLongMatches = false
+		cfg.CostLimit = 0
+		cfg.GoodDiagMinLen, cfg.GoodDiagCostLimit, cfg.GoodDiagMagic = 0, 0, 0
+		cfg.ForceAnchoringHeuristic = false
+	}
 	if cfg.Mode != ModeDefault && cfg.ForceAnchoringHeuristic {
 		panic("ForceAnchoringHeuristic may only be set for ModeDefault")
 	}
@@ -108,6 +304,74 @@ func printFlag(flag Flag) string {
 		return "textdiff.IndentHeuristic"
 	case TerminalColors:
 		return "textdiff.TerminalColors"
+	case AutoFast:
+		return "diff.AutoFast"
+	case NormalizeUnicode:
+		return "textdiff.NormalizeUnicode"
+	case MaxLineWidth:
+		return "textdiff.MaxLineWidth"
+	case ConflictLabels:
+		return "textdiff.ConflictLabels"
+	case PairedOrdering:
+		return "diff.PairedOrdering"
+	case DetectSwaps:
+		return "diff.DetectSwaps"
+	case FuzzyLines:
+		return "textdiff.FuzzyLines"
+	case HunkSeparator:
+		return "textdiff.HunkSeparator"
+	case CostLimit:
+		return "diff.CostLimit"
+	case AnchorOn:
+		return "textdiff.AnchorOn"
+	case MaxWork:
+		return "diff.MaxWork"
+	case IgnoreMatching:
+		return "textdiff.IgnoreMatching"
+	case IgnoreReorderedBlocks:
+		return "textdiff.IgnoreReorderedBlocks"
+	case BinaryDetection:
+		return "textdiff.BinaryDetection"
+	case BlockContext:
+		return "textdiff.BlockContext"
+	case DetectModifiedMoves:
+		return "diff.DetectModifiedMoves"
+	case IgnoreComments:
+		return "textdiff.IgnoreComments"
+	case MaxHunkLines:
+		return "textdiff.MaxHunkLines"
+	case ShowWhitespace:
+		return "textdiff.ShowWhitespace"
+	case FunctionContext:
+		return "textdiff.FunctionContext"
+	case HunkStatsInHeader:
+		return "textdiff.HunkStatsInHeader"
+	case MinHunkChanges:
+		return "textdiff.MinHunkChanges"
+	case GoodDiagonalTuning:
+		return "diff.GoodDiagonalTuning"
+	case FloatTolerance:
+		return "diff.FloatTolerance"
+	case MarkReindent:
+		return "textdiff.MarkReindent"
+	case MaxHunks:
+		return "diff.MaxHunks"
+	case PreferLongMatches:
+		return "diff.PreferLongMatches"
+	case SanitizeInvalidUTF8:
+		return "textdiff.SanitizeInvalidUTF8"
+	case OutputNewline:
+		return "textdiff.OutputNewline"
+	case Labels:
+		return "textdiff.Labels"
+	case IndexHeader:
+		return "textdiff.IndexHeader"
+	case Parallel:
+		return "diff.Parallel"
+	case DeterministicSplit:
+		return "diff.DeterministicSplit"
+	case AnchorMaxCount:
+		return "diff.AnchorMaxCount"
 	default:
 		panic("never reached")
 	}
@@ -48,41 +48,97 @@ package impl
 import (
 	"fmt"
 	"sort"
+	"sync"
+	"sync/atomic"
 
 	"znkr.io/diff/internal/config"
 	"znkr.io/diff/internal/rvecs"
 )
 
+// Stats describes how a diff was computed.
+type Stats struct {
+	Mode         config.Mode // Mode used to compute the diff.
+	Anchoring    bool        // Set if the ANCHORING heuristic was applied.
+	GoodDiagonal bool        // Set if the GOOD_DIAGONAL heuristic was applied.
+	TooExpensive bool        // Set if the TOO_EXPENSIVE heuristic was applied.
+}
+
 // Diff compares the contents of x and y and returns the changes necessary to convert from one to
 // the other.
 func Diff[T comparable](x, y []T, cfg config.Config) (rx, ry []bool) {
+	rx, ry, _ = DiffWithStats(x, y, cfg)
+	return rx, ry
+}
+
+// DiffWithStats is like [Diff], but additionally returns information about how the diff was
+// computed.
+func DiffWithStats[T comparable](x, y []T, cfg config.Config) (rx, ry []bool, stats Stats) {
+	return diffWithStats(x, y, nil, cfg)
+}
+
+// DiffFingerprint is like [Diff], but identifies equal elements by their fingerprint instead of
+// using the elements as map keys. This reduces the memory used for preprocessing if there are many
+// distinct elements. Elements with the same fingerprint are compared for equality, a collision
+// therefore doesn't change the result.
+func DiffFingerprint[T comparable](x, y []T, fingerprint func(T) uint64, cfg config.Config) (rx, ry []bool) {
+	rx, ry, _ = diffWithStats(x, y, fingerprint, cfg)
+	return rx, ry
+}
+
+// diffWithStats implements [DiffWithStats] and [DiffFingerprint]. If fingerprint is nil, the
+// elements themselves are used as map keys.
+func diffWithStats[T comparable](x, y []T, fingerprint func(T) uint64, cfg config.Config) (rx, ry []bool, stats Stats) {
 	rx, ry = rvecs.Make(x, y)
+	stats.Mode = cfg.Mode
+	if cfg.Stable {
+		stats.Mode = config.ModeMinimal
+	}
 
 	smin, smax, tmin, tmax := findChangeBounds(x, y)
 	if handleTrivialBounds(rx, ry, smin, smax, tmin, tmax) {
 		return
 	}
 
+	// Switch to fast mode if the remaining problem is too large. We use the reduced problem size
+	// here, because a large input with only a few changes is cheap to diff anyway.
+	if cfg.AutoFast && !cfg.Stable && exceedsProduct(smax-smin, tmax-tmin, cfg.AutoFastMaxProduct) {
+		stats.Mode = config.ModeFast
+	}
+
 	// Preprocess x and y to reduce the problem size and to work with integer IDs instead of Ts.
 	// This is (for now) only possible for comparable types, because mapping from T to a unique
 	// ID requires a map.
-	x0, y0, xidx, yidx, counts, nanchors := preprocess(rx, ry, smin, smax, tmin, tmax, x, y)
+	var x0, y0, xidx, yidx, counts []int
+	var nanchors int
+	if fingerprint != nil {
+		x0, y0, xidx, yidx, counts, nanchors = preprocessFingerprint(rx, ry, smin, smax, tmin, tmax, x, y, fingerprint)
+	} else {
+		x0, y0, xidx, yidx, counts, nanchors = preprocess(rx, ry, smin, smax, tmin, tmax, x, y)
+	}
+	if cfg.AnchorMaxCount > 1 {
+		nanchors = relaxAnchors(x0, y0, counts, cfg.AnchorMaxCount)
+	}
 
-	switch cfg.Mode {
+	switch stats.Mode {
 	case config.ModeMinimal:
-		diffMinimal(rx, ry, x0, y0, xidx, yidx)
+		diffMinimal(rx, ry, x0, y0, xidx, yidx, cfg, &stats)
 
 	case config.ModeDefault:
-		diffDefault(rx, ry, x0, y0, xidx, yidx, counts, nanchors, cfg.ForceAnchoringHeuristic)
+		diffDefault(rx, ry, x0, y0, xidx, yidx, counts, nanchors, cfg, &stats)
 
 	case config.ModeFast:
 		diffFast(rx, ry, x0, y0, xidx, yidx, counts, nanchors)
 
 	default:
-		panic(fmt.Sprintf("unknown mode: %v", cfg.Mode))
+		panic(fmt.Sprintf("unknown mode: %v", stats.Mode))
 	}
 
-	return rx, ry
+	return rx, ry, stats
+}
+
+// exceedsProduct returns true if n*m > limit without risking an integer overflow.
+func exceedsProduct(n, m, limit int) bool {
+	return n > 0 && m > limit/n
 }
 
 // DiffFunc compares the contents of x and y and returns the changes necessary to convert from one
@@ -90,6 +146,14 @@ func Diff[T comparable](x, y []T, cfg config.Config) (rx, ry []bool) {
 //
 // Note that this function has generally worse performance than [Diff] for diffs with many changes.
 func DiffFunc[T any](x, y []T, eq func(a, b T) bool, cfg config.Config) (rx, ry []bool) {
+	rx, ry, _ = DiffFuncChecked(x, y, eq, cfg)
+	return rx, ry
+}
+
+// DiffFuncChecked is like [DiffFunc], but additionally reports whether the work limit in
+// cfg.MaxWork was exceeded. If it was, the result vectors describe a valid diff, but all ranges
+// that weren't compared yet are reported as changed.
+func DiffFuncChecked[T any](x, y []T, eq func(a, b T) bool, cfg config.Config) (rx, ry []bool, exceeded bool) {
 	rx, ry = rvecs.Make(x, y)
 
 	smin, smax, tmin, tmax := findChangeBoundsFunc(x, y, eq)
@@ -100,20 +164,30 @@ func DiffFunc[T any](x, y []T, eq func(a, b T) bool, cfg config.Config) (rx, ry
 	var m myers[T]
 	m.rx, m.ry = rx, ry
 	smin, smax, tmin, tmax = m.init(x, y, eq)
-	m.compare(smin, smax, tmin, tmax, cfg.Mode == config.ModeMinimal, eq)
-	return m.rx, m.ry
+	m.tune(cfg)
+	m.maxWork = cfg.MaxWork
+	m.compare(smin, smax, tmin, tmax, cfg.Mode == config.ModeMinimal || cfg.Stable, eq)
+	return m.rx, m.ry, m.maxWork > 0 && m.work > m.maxWork
+}
+
+// CommonPrefixLen returns the length of the common prefix of x and y.
+func CommonPrefixLen[T comparable](x, y []T) int {
+	n := min(len(x), len(y))
+	for i := range n {
+		if x[i] != y[i] {
+			return i
+		}
+	}
+	return n
 }
 
 // findChangeBounds returns the upper and lower bounds for the changed portion of the inputs.
 func findChangeBounds[T comparable](x, y []T) (smin, smax, tmin, tmax int) {
-	smin, tmin = 0, 0
 	smax, tmax = len(x), len(y)
 
 	// Strip common prefix.
-	for smin < smax && tmin < tmax && x[smin] == y[tmin] {
-		smin++
-		tmin++
-	}
+	smin = CommonPrefixLen(x, y)
+	tmin = smin
 
 	// Strip common suffix.
 	for smax > smin && tmax > tmin && x[smax-1] == y[tmax-1] {
@@ -252,26 +326,135 @@ func preprocess[T comparable](rx, ry []bool, smin, smax, tmin, tmax int, x, y []
 	return
 }
 
-func diffMinimal(rx, ry []bool, x0, y0 []int, xidx, yidx []int) {
+// preprocessFingerprint is like preprocess, but uses a map from fingerprints to IDs instead of a
+// map from elements to IDs. The ID of an element is the position of its first occurrence in
+// x[smin:smax]. This makes the ID space sparse, but allows to check for fingerprint collisions
+// without storing the elements: An element is only assigned an existing ID if it's equal to the
+// element at that position. Colliding elements are stored in a separate map.
+func preprocessFingerprint[T comparable](rx, ry []bool, smin, smax, tmin, tmax int, x, y []T, fingerprint func(T) uint64) (x0, y0 []int, xidx, yidx []int, counts []int, nanchors int) {
+	idx := make(map[uint64]int, smax-smin) // temporary map from fingerprint to ID
+	var collisions map[T]int               // temporary map from element to ID for collisions
+	lookup := func(e T, h uint64) (int, bool) {
+		if id, ok := idx[h]; ok && x[smin+id] == e {
+			return id, true
+		}
+		id, ok := collisions[e]
+		return id, ok
+	}
+
+	buf := make([]int, 2*(smax-smin)+2*(tmax-tmin))
+	x0, buf = buf[:0:smax-smin], buf[smax-smin:]
+	xidx, buf = buf[:0:smax-smin], buf[smax-smin:]
+	y0, buf = buf[:0:tmax-tmin], buf[tmax-tmin:]
+	yidx, buf = buf[:0:tmax-tmin], buf[tmax-tmin:]
+	if len(buf) != 0 && cap(buf) != 0 {
+		panic("something went wrong during buffer assignments")
+	}
+	counts = make([]int, smax-smin)
+	// Step 1: Create an ID for every element in x[smin:smax] and count the number of occurrences.
+	for j, e := range x[smin:smax] {
+		h := fingerprint(e)
+		id, ok := lookup(e, h)
+		if !ok {
+			id = j
+			if _, collision := idx[h]; collision {
+				if collisions == nil {
+					collisions = make(map[T]int)
+				}
+				collisions[e] = id
+			} else {
+				idx[h] = id
+			}
+		}
+		if c := counts[id]; c < 2 {
+			counts[id] = c + 1
+		}
+		x0 = append(x0, id)
+	}
+	// Step 2: Do the same for y, but already ignore everything that's not in x, except for marking
+	// these elements as insertions.
+	for i, e := range y[tmin:tmax] {
+		id, ok := lookup(e, fingerprint(e))
+		if !ok {
+			// Not in x, this is always an insertion.
+			ry[i+tmin] = true
+			continue
+		}
+		if c := counts[id]; c < 8 {
+			counts[id] = c + 4
+		}
+		yidx = append(yidx, i+tmin)
+		y0 = append(y0, id)
+	}
+	// Step 3: Filter out elements from x0 that are not in y.
+	i := 0
+	for j, e := range x0 {
+		if c := counts[e]; c > 4 {
+			xidx = append(xidx, j+smin)
+			x0[i] = e
+			if c == 1+4 {
+				// Element appears exactly once in x (1) and y (4).
+				nanchors++
+			}
+			i++
+		} else {
+			rx[j+smin] = true // always an deletion
+		}
+	}
+	x0 = x0[:i]
+	return
+}
+
+// relaxAnchors changes counts so that every element that appears equally often in x0 and y0, but
+// at most k times, is an anchor, i.e., has a count of 1+4. It returns the number of occurrences of
+// anchors in x0, which is the same as the number of occurrences in y0.
+//
+// The anchors are used by segments, which pairs the i-th occurrence of an anchor in x0 with the
+// i-th occurrence in y0.
+func relaxAnchors(x0, y0 []int, counts []int, k int) (nanchors int) {
+	cx, cy := make([]int, len(counts)), make([]int, len(counts))
+	for _, e := range x0 {
+		cx[e]++
+	}
+	for _, e := range y0 {
+		cy[e]++
+	}
+	for id, n := range cx {
+		if n > 0 && n <= k && n == cy[id] {
+			counts[id] = 1 + 4
+			nanchors += n
+		}
+	}
+	return nanchors
+}
+
+func diffMinimal(rx, ry []bool, x0, y0 []int, xidx, yidx []int, cfg config.Config, stats *Stats) {
 	var m myersInt
 	m.xidx, m.yidx = xidx, yidx
 	m.rx, m.ry = rx, ry
 	smin0, smax0, tmin0, tmax0 := m.init(x0, y0)
+	m.tune(cfg)
 	m.compare(smin0, smax0, tmin0, tmax0, true)
+	// The heuristics are only used if the time budget of cfg.MinimalBudget is exhausted.
+	stats.GoodDiagonal = m.goodDiagUsed
+	stats.TooExpensive = m.tooExpensiveUsed
 }
 
-func diffDefault(rx, ry []bool, x0, y0 []int, xidx, yidx []int, counts []int, nanchors int, forceAnchoring bool) {
+func diffDefault(rx, ry []bool, x0, y0 []int, xidx, yidx []int, counts []int, nanchors int, cfg config.Config, stats *Stats) {
 	var m myersInt
 	m.xidx, m.yidx = xidx, yidx
 	m.rx, m.ry = rx, ry
 	smin0, smax0, tmin0, tmax0 := m.init(x0, y0)
+	m.tune(cfg)
 
 	// Heuristic (ANCHORING): If the input is too large and we have found anchors, use the
 	// anchoring heuristic. This provides a significant performance boost and provides more
 	// optimal results than the other heuristics.
 	anchoring := nanchors > 0 && (smax0-smin0)+(tmax0-tmin0) > anchoringHeuristicMinInputLen
-	if anchoring || forceAnchoring {
+	if anchoring || cfg.ForceAnchoringHeuristic {
+		stats.Anchoring = true
 		segments := segments(smin0, smax0, tmin0, tmax0, nanchors, counts, x0, y0)
+		var ranges [][2]pair // Start and end of the ranges between anchors.
 		done := segments[0]
 		for _, anchor := range segments[1:] {
 			if anchor.s < done.s {
@@ -290,7 +473,7 @@ func diffDefault(rx, ry []bool, x0, y0 []int, xidx, yidx []int, counts []int, na
 				end.t++
 			}
 
-			m.compare(done.s, start.s, done.t, start.t, false)
+			ranges = append(ranges, [2]pair{done, start})
 
 			if end.s >= smax0 && end.t >= tmax0 {
 				break
@@ -298,9 +481,64 @@ func diffDefault(rx, ry []bool, x0, y0 []int, xidx, yidx []int, counts []int, na
 
 			done = end
 		}
+		if cfg.Parallel > 1 && len(ranges) > 1 {
+			compareParallel(&m, ranges, x0, y0, cfg.Parallel)
+		} else {
+			for _, r := range ranges {
+				m.compare(r[0].s, r[1].s, r[0].t, r[1].t, false)
+			}
+		}
 	} else {
 		m.compare(smin0, smax0, tmin0, tmax0, false)
 	}
+	stats.GoodDiagonal = m.goodDiagUsed
+	stats.TooExpensive = m.tooExpensiveUsed
+}
+
+// compareParallel compares the ranges between anchors on up to n goroutines. The ranges are
+// disjoint, every goroutine therefore writes to a disjoint part of the result vectors.
+//
+// Every range is compared with its own myersInt that is tuned like m, the result is the same as
+// comparing all ranges with m sequentially.
+func compareParallel(m *myersInt, ranges [][2]pair, x0, y0 []int, n int) {
+	var (
+		next                           atomic.Int64 // Index of the next range to compare.
+		goodDiagUsed, tooExpensiveUsed atomic.Bool
+		wg                             sync.WaitGroup
+	)
+	for range min(n, len(ranges)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= len(ranges) {
+					return
+				}
+				from, to := ranges[i][0], ranges[i][1]
+				w := myersInt{
+					xidx: m.xidx[from.s:to.s],
+					yidx: m.yidx[from.t:to.t],
+					rx:   m.rx,
+					ry:   m.ry,
+				}
+				smin, smax, tmin, tmax := w.init(x0[from.s:to.s], y0[from.t:to.t])
+				w.costLimit = m.costLimit
+				w.goodDiagMinLen, w.goodDiagCostLimit, w.goodDiagMagic = m.goodDiagMinLen, m.goodDiagCostLimit, m.goodDiagMagic
+				w.preferLongMatches = m.preferLongMatches
+				w.compare(smin, smax, tmin, tmax, false)
+				if w.goodDiagUsed {
+					goodDiagUsed.Store(true)
+				}
+				if w.tooExpensiveUsed {
+					tooExpensiveUsed.Store(true)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	m.goodDiagUsed = m.goodDiagUsed || goodDiagUsed.Load()
+	m.tooExpensiveUsed = m.tooExpensiveUsed || tooExpensiveUsed.Load()
 }
 
 func diffFast(rx, ry []bool, x0, y0 []int, xidx, yidx []int, counts []int, nanchors int) {
@@ -362,18 +600,44 @@ func segments(smin, smax, tmin, tmax int, nanchors int, counts []int, x, y []int
 	//	xi[i] = increasing indexes of unique strings in x.
 	//	yi[i] = increasing indexes of unique strings in y.
 	//	inv[i] = index j such that x[xi[i]] = y[yi[j]].
+	//
+	// With relaxed anchors (see relaxAnchors), an anchor can appear multiple times. The occurrences
+	// in y are then chained with next and the i-th occurrence in x is paired with the i-th
+	// occurrence in y. This is never needed for unique anchors, next isn't allocated then.
+	var next []int
 	for i, e := range y[tmin:tmax] {
 		t := tmin + i
 		if counts[e] == 1+4 {
-			idx[e] = len(yi)
+			j, ok := idx[e]
+			if !ok {
+				idx[e] = len(yi)
+			} else {
+				if next == nil {
+					next = make([]int, nanchors)
+				}
+				for next[j] > 0 {
+					j = next[j]
+				}
+				next[j] = len(yi)
+			}
 			yi = append(yi, t)
 		}
 	}
 	for i, e := range x[smin:smax] {
 		s := smin + i
 		if counts[e] == 1+4 {
+			j := idx[e]
+			if j < 0 {
+				continue // more occurrences in x than in y, can't be paired
+			}
 			xi = append(xi, s)
-			inv = append(inv, idx[e])
+			inv = append(inv, j)
+			if next != nil {
+				idx[e] = next[j]
+				if idx[e] == 0 {
+					idx[e] = -1 // last occurrence in y
+				}
+			}
 		}
 	}
 
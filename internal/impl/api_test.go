@@ -15,6 +15,10 @@
 package impl
 
 import (
+	"fmt"
+	"hash/maphash"
+	"math/rand/v2"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -131,6 +135,32 @@ func TestDiff(t *testing.T) {
 				}
 			})
 
+			t.Run("diff_fingerprint", func(t *testing.T) {
+				cfg := config.Default
+				if tt.skip != nil && tt.skip(cfg) {
+					return
+				}
+				seed := maphash.MakeSeed()
+				rx, ry := DiffFingerprint(tt.x, tt.y, func(s string) uint64 { return maphash.String(seed, s) }, cfg)
+				got := render(rx, ry, len(tt.x), len(tt.y))
+				if diff := cmp.Diff(tt.want, got); diff != "" {
+					t.Errorf("DiffFingerprint(...) differs [-want,+got]:\n%s", diff)
+				}
+			})
+
+			t.Run("diff_fingerprint_collisions", func(t *testing.T) {
+				cfg := config.Default
+				if tt.skip != nil && tt.skip(cfg) {
+					return
+				}
+				// Every element has the same fingerprint.
+				rx, ry := DiffFingerprint(tt.x, tt.y, func(string) uint64 { return 0 }, cfg)
+				got := render(rx, ry, len(tt.x), len(tt.y))
+				if diff := cmp.Diff(tt.want, got); diff != "" {
+					t.Errorf("DiffFingerprint(...) differs [-want,+got]:\n%s", diff)
+				}
+			})
+
 			t.Run("diff_func", func(t *testing.T) {
 				cfg := config.Default
 				if tt.skip != nil && tt.skip(cfg) {
@@ -146,6 +176,61 @@ func TestDiff(t *testing.T) {
 	}
 }
 
+func TestDiffFingerprint(t *testing.T) {
+	// Random inputs with many repeated elements and a fingerprint with many collisions.
+	rng := rand.New(rand.NewPCG(1, 2))
+	gen := func(n int) []string {
+		out := make([]string, n)
+		for i := range out {
+			out[i] = strconv.Itoa(rng.IntN(n / 2))
+		}
+		return out
+	}
+	fingerprint := func(s string) uint64 { return uint64(len(s)) }
+	for _, mode := range []config.Mode{config.ModeDefault, config.ModeMinimal, config.ModeFast} {
+		for range 10 {
+			x, y := gen(500), gen(400)
+			cfg := config.Default
+			cfg.Mode = mode
+			wantX, wantY := Diff(x, y, cfg)
+			gotX, gotY := DiffFingerprint(x, y, fingerprint, cfg)
+			want := render(wantX, wantY, len(x), len(y))
+			got := render(gotX, gotY, len(x), len(y))
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("DiffFingerprint(...) in mode %v is different from Diff(...) [-want,+got]:\n%s", mode, diff)
+			}
+		}
+	}
+}
+
+func BenchmarkDiffFingerprint(b *testing.B) {
+	// Many distinct long lines, every tenth line changed.
+	const n = 500_000
+	x := make([]string, n)
+	y := make([]string, n)
+	for i := range x {
+		x[i] = fmt.Sprintf("%0100d\n", i)
+		y[i] = x[i]
+		if i%10 == 0 {
+			y[i] = fmt.Sprintf("%0100d\n", -i)
+		}
+	}
+	b.Run("direct", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			Diff(x, y, config.Default)
+		}
+	})
+	b.Run("fingerprint", func(b *testing.B) {
+		seed := maphash.MakeSeed()
+		fingerprint := func(s string) uint64 { return maphash.String(seed, s) }
+		b.ReportAllocs()
+		for b.Loop() {
+			DiffFingerprint(x, y, fingerprint, config.Default)
+		}
+	})
+}
+
 func render(rx, ry []bool, n, m int) string {
 	var sb strings.Builder
 	for s, t := 0, 0; s < n || t < m; {
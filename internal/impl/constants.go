@@ -25,3 +25,7 @@ const goodDiagMagic = 4       // Magic number for diagonal selection.
 
 // Constants for ANCHORING heuristic.
 const anchoringHeuristicMinInputLen = 5_000 // Minimum length for enabling the anchoring heuristic.
+
+// deadlineCheckInterval is the number of d-iterations between two checks of the deadline for
+// diff.MinimalWithBudget. Reading the clock is cheap, but not free.
+const deadlineCheckInterval = 64
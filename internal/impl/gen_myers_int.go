@@ -5,6 +5,9 @@ package impl
 
 import (
 	"math"
+	"time"
+
+	"znkr.io/diff/internal/config"
 )
 
 type myersInt struct {
@@ -18,6 +21,18 @@ type myersInt struct {
 	xidx, yidx []int
 
 	rx, ry []bool
+
+	goodDiagMinLen, goodDiagCostLimit, goodDiagMagic int
+
+	goodDiagUsed, tooExpensiveUsed bool
+
+	preferLongMatches bool
+
+	maxWork, work int
+
+	deadline       time.Time
+	deadlineChecks int
+	pastDeadline   bool
 }
 
 func (m *myersInt) init(x, y []int) (smin, smax, tmin, tmax int) {
@@ -50,6 +65,9 @@ func (m *myersInt) init(x, y []int) (smin, smax, tmin, tmax int) {
 		costLimit <<= 1
 	}
 	m.costLimit = max(minCostLimit, costLimit)
+	m.goodDiagMinLen = goodDiagMinLen
+	m.goodDiagCostLimit = goodDiagCostLimit
+	m.goodDiagMagic = goodDiagMagic
 
 	if m.xidx == nil || m.yidx == nil {
 		idx := make([]int, max(len(x), len(y)))
@@ -69,6 +87,34 @@ func (m *myersInt) init(x, y []int) (smin, smax, tmin, tmax int) {
 	return
 }
 
+func (m *myersInt) tune(cfg config.Config) {
+	if cfg.CostLimit > 0 {
+		m.costLimit = cfg.CostLimit
+	}
+	if cfg.GoodDiagMinLen > 0 {
+		m.goodDiagMinLen = cfg.GoodDiagMinLen
+		m.goodDiagCostLimit = cfg.GoodDiagCostLimit
+		m.goodDiagMagic = cfg.GoodDiagMagic
+	}
+	m.preferLongMatches = cfg.PreferLongMatches
+	if cfg.MinimalBudget > 0 && !cfg.Stable {
+		m.deadline = time.Now().Add(cfg.MinimalBudget)
+	}
+}
+
+func (m *myersInt) exceedsDeadline() bool {
+	if m.deadline.IsZero() || m.pastDeadline {
+		return m.pastDeadline
+	}
+	m.deadlineChecks++
+	if m.deadlineChecks < deadlineCheckInterval {
+		return false
+	}
+	m.deadlineChecks = 0
+	m.pastDeadline = time.Now().After(m.deadline)
+	return m.pastDeadline
+}
+
 func (m *myersInt) compare(smin, smax, tmin, tmax int, optimal bool) {
 	if smin == smax {
 
@@ -80,6 +126,14 @@ func (m *myersInt) compare(smin, smax, tmin, tmax int, optimal bool) {
 		for s := smin; s < smax; s++ {
 			m.rx[m.xidx[s]] = true
 		}
+	} else if m.maxWork > 0 && m.work > m.maxWork {
+
+		for s := smin; s < smax; s++ {
+			m.rx[m.xidx[s]] = true
+		}
+		for t := tmin; t < tmax; t++ {
+			m.ry[m.yidx[t]] = true
+		}
 	} else {
 
 		s0, s1, t0, t1, opt0, opt1 := m.split(smin, smax, tmin, tmax, optimal)
@@ -108,8 +162,18 @@ func (m *myersInt) split(smin, smax, tmin, tmax int, optimal bool) (s0, s1, t0,
 
 	for d := 1; ; d++ {
 
+		if m.maxWork > 0 {
+			m.work++
+			if m.work > m.maxWork {
+				return smin, smin, tmin, tmin, optimal, optimal
+			}
+		}
+
 		longestDiag := 0
 
+		found := false
+		var snake struct{ s0, s1, t0, t1 int }
+
 		if fmin > kmin {
 			fmin--
 			vf[v0+fmin-1] = math.MinInt
@@ -147,9 +211,18 @@ func (m *myersInt) split(smin, smax, tmin, tmax int, optimal bool) (s0, s1, t0,
 			vf[k0] = s
 
 			if odd && bmin <= k && k <= bmax && s >= vb[k0] {
-				return s0, s, t0, t, true, true
+				if !m.preferLongMatches {
+					return s0, s, t0, t, true, true
+				}
+				if !found || s-s0 > snake.s1-snake.s0 {
+					found = true
+					snake.s0, snake.s1, snake.t0, snake.t1 = s0, s, t0, t
+				}
 			}
 		}
+		if found {
+			return snake.s0, snake.s1, snake.t0, snake.t1, true, true
+		}
 
 		if bmin > kmin {
 			bmin--
@@ -184,15 +257,28 @@ func (m *myersInt) split(smin, smax, tmin, tmax int, optimal bool) (s0, s1, t0,
 			vb[k0] = s
 
 			if !odd && fmin <= k && k <= fmax && s <= vf[v0+k] {
-				return s, s0, t, t0, true, true
+				if !m.preferLongMatches {
+					return s, s0, t, t0, true, true
+				}
+				if !found || s0-s > snake.s1-snake.s0 {
+					found = true
+					snake.s0, snake.s1, snake.t0, snake.t1 = s, s0, t, t0
+				}
 			}
 		}
+		if found {
+			return snake.s0, snake.s1, snake.t0, snake.t1, true, true
+		}
 
 		if optimal {
-			continue
+			if !m.exceedsDeadline() {
+				continue
+			}
+
+			optimal = false
 		}
 
-		if longestDiag >= goodDiagMinLen && d >= goodDiagCostLimit {
+		if longestDiag >= m.goodDiagMinLen && d >= m.goodDiagCostLimit {
 			best := struct {
 				v              int
 				s0, s1, t0, t1 int
@@ -207,7 +293,7 @@ func (m *myersInt) split(smin, smax, tmin, tmax int, optimal bool) (s0, s1, t0,
 				if s < smin || smax <= s || t < tmin || tmax <= t {
 					continue
 				}
-				if v <= goodDiagMagic*d || v < best.v {
+				if v <= m.goodDiagMagic*d || v < best.v {
 					continue
 				}
 
@@ -220,7 +306,7 @@ func (m *myersInt) split(smin, smax, tmin, tmax int, optimal bool) (s0, s1, t0,
 				ps := vf[pk+v0]
 				pt := ps - pk
 				diag := min(s-ps, t-pt)
-				if diag < goodDiagMinLen {
+				if diag < m.goodDiagMinLen {
 					best.v = v
 					best.s0 = s - diag
 					best.s1 = s
@@ -239,7 +325,7 @@ func (m *myersInt) split(smin, smax, tmin, tmax int, optimal bool) (s0, s1, t0,
 					continue
 				}
 				v := (smax - s) + (tmax - t) - max(bmid-d, d-bmid)
-				if v <= goodDiagMagic*d || v < best.v {
+				if v <= m.goodDiagMagic*d || v < best.v {
 					continue
 				}
 
@@ -252,7 +338,7 @@ func (m *myersInt) split(smin, smax, tmin, tmax int, optimal bool) (s0, s1, t0,
 				ps := vb[pk+v0]
 				pt := ps - pk
 				diag := min(ps-s, pt-t)
-				if diag >= goodDiagMinLen {
+				if diag >= m.goodDiagMinLen {
 					best.v = v
 					best.s0 = s
 					best.s1 = s + diag
@@ -263,11 +349,13 @@ func (m *myersInt) split(smin, smax, tmin, tmax int, optimal bool) (s0, s1, t0,
 				}
 			}
 			if best.v > 0 {
+				m.goodDiagUsed = true
 				return best.s0, best.s1, best.t0, best.t1, best.opt0, best.opt1
 			}
 		}
 
 		if d >= m.costLimit {
+			m.tooExpensiveUsed = true
 
 			fbest, fbestk := math.MinInt, math.MinInt
 			for k := fmin; k <= fmax; k += 2 {
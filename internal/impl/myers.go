@@ -16,6 +16,9 @@ package impl
 
 import (
 	"math"
+	"time"
+
+	"znkr.io/diff/internal/config"
 )
 
 type myers[T any] struct {
@@ -38,6 +41,29 @@ type myers[T any] struct {
 
 	// Result vectors.
 	rx, ry []bool
+
+	// Parameters for the GOOD_DIAGONAL heuristic, see constants.go.
+	goodDiagMinLen, goodDiagCostLimit, goodDiagMagic int
+
+	// Set if the GOOD_DIAGONAL or TOO_EXPENSIVE heuristic was applied respectively.
+	goodDiagUsed, tooExpensiveUsed bool
+
+	// If set, split picks the longest middle snake among all optimal candidates instead of the
+	// first one it finds.
+	preferLongMatches bool
+
+	// If maxWork > 0, the search gives up once the total number of d-iterations across all calls to
+	// split exceeds maxWork. All remaining ranges are then reported as changed. work is the number
+	// of d-iterations so far.
+	maxWork, work int
+
+	// If deadline is set, split stops searching for optimal paths once the deadline has passed and
+	// uses the heuristics instead. The clock is only read every deadlineCheckInterval d-iterations,
+	// deadlineChecks counts the d-iterations since the last check. pastDeadline is set once the
+	// deadline has passed.
+	deadline       time.Time
+	deadlineChecks int
+	pastDeadline   bool
 }
 
 func (m *myers[T]) init(x, y []T, eq func(a, b T) bool) (smin, smax, tmin, tmax int) {
@@ -74,6 +100,9 @@ func (m *myers[T]) init(x, y []T, eq func(a, b T) bool) (smin, smax, tmin, tmax
 		costLimit <<= 1
 	}
 	m.costLimit = max(minCostLimit, costLimit)
+	m.goodDiagMinLen = goodDiagMinLen
+	m.goodDiagCostLimit = goodDiagCostLimit
+	m.goodDiagMagic = goodDiagMagic
 
 	if m.xidx == nil || m.yidx == nil {
 		idx := make([]int, max(len(x), len(y)))
@@ -94,6 +123,36 @@ func (m *myers[T]) init(x, y []T, eq func(a, b T) bool) (smin, smax, tmin, tmax
 	return
 }
 
+// tune overrides the heuristic parameters set by init with the ones configured in cfg.
+func (m *myers[T]) tune(cfg config.Config) {
+	if cfg.CostLimit > 0 {
+		m.costLimit = cfg.CostLimit
+	}
+	if cfg.GoodDiagMinLen > 0 {
+		m.goodDiagMinLen = cfg.GoodDiagMinLen
+		m.goodDiagCostLimit = cfg.GoodDiagCostLimit
+		m.goodDiagMagic = cfg.GoodDiagMagic
+	}
+	m.preferLongMatches = cfg.PreferLongMatches
+	if cfg.MinimalBudget > 0 && !cfg.Stable {
+		m.deadline = time.Now().Add(cfg.MinimalBudget)
+	}
+}
+
+// exceedsDeadline reports whether the deadline set by tune has passed.
+func (m *myers[T]) exceedsDeadline() bool {
+	if m.deadline.IsZero() || m.pastDeadline {
+		return m.pastDeadline
+	}
+	m.deadlineChecks++
+	if m.deadlineChecks < deadlineCheckInterval {
+		return false
+	}
+	m.deadlineChecks = 0
+	m.pastDeadline = time.Now().After(m.deadline)
+	return m.pastDeadline
+}
+
 // compare finds an optimal d-path from (smin, tmin) to (smax, tmax).
 //
 // Important: x[smin:smax] and y[tmin:tmax] must not have a common prefix or a common suffix.
@@ -108,6 +167,14 @@ func (m *myers[T]) compare(smin, smax, tmin, tmax int, optimal bool, eq func(x,
 		for s := smin; s < smax; s++ {
 			m.rx[m.xidx[s]] = true
 		}
+	} else if m.maxWork > 0 && m.work > m.maxWork {
+		// The work limit is exceeded, report everything as changed.
+		for s := smin; s < smax; s++ {
+			m.rx[m.xidx[s]] = true
+		}
+		for t := tmin; t < tmax; t++ {
+			m.ry[m.yidx[t]] = true
+		}
 	} else {
 		// Use split to divide the input into three pieces:
 		//
@@ -165,8 +232,22 @@ func (m *myers[T]) split(smin, smax, tmin, tmax int, optimal bool, eq func(x, y
 		// searching backwards for a d-path. If two paths overlap, we have found a d-path, if not
 		// we're going to continue searching.
 
+		// Work limit: Give up and return an empty middle snake at the start. This makes compare
+		// report the whole range as changed.
+		if m.maxWork > 0 {
+			m.work++
+			if m.work > m.maxWork {
+				return smin, smin, tmin, tmin, optimal, optimal
+			}
+		}
+
 		longestDiag := 0 // Longest diagonal we found
 
+		// Longest middle snake found in this iteration, only used if preferLongMatches is set. All
+		// overlaps found in the same iteration belong to an optimal path.
+		found := false
+		var snake struct{ s0, s1, t0, t1 int }
+
 		// Forwards iteration.
 		//
 		// First determine which diagonals k to search. Originally, we would search k = [fmid-d,
@@ -239,9 +320,18 @@ func (m *myers[T]) split(smin, smax, tmin, tmax int, optimal bool, eq func(x, y
 			// Potentially, check for an overlap with a backwards d-path. We're done when we found
 			// it.
 			if odd && bmin <= k && k <= bmax && s >= vb[k0] {
-				return s0, s, t0, t, true, true
+				if !m.preferLongMatches {
+					return s0, s, t0, t, true, true
+				}
+				if !found || s-s0 > snake.s1-snake.s0 {
+					found = true
+					snake.s0, snake.s1, snake.t0, snake.t1 = s0, s, t0, t
+				}
 			}
 		}
+		if found {
+			return snake.s0, snake.s1, snake.t0, snake.t1, true, true
+		}
 
 		// Backwards iteration.
 		//
@@ -279,12 +369,25 @@ func (m *myers[T]) split(smin, smax, tmin, tmax int, optimal bool, eq func(x, y
 			vb[k0] = s
 
 			if !odd && fmin <= k && k <= fmax && s <= vf[v0+k] {
-				return s, s0, t, t0, true, true
+				if !m.preferLongMatches {
+					return s, s0, t, t0, true, true
+				}
+				if !found || s0-s > snake.s1-snake.s0 {
+					found = true
+					snake.s0, snake.s1, snake.t0, snake.t1 = s, s0, t, t0
+				}
 			}
 		}
+		if found {
+			return snake.s0, snake.s1, snake.t0, snake.t1, true, true
+		}
 
 		if optimal {
-			continue
+			if !m.exceedsDeadline() {
+				continue
+			}
+			// The time budget is exhausted, fall back to the heuristics for the rest of the search.
+			optimal = false
 		}
 
 		// Heuristic (GOOD_DIAGONAL): If we're over the cost limit for this heuristic, we accept a
@@ -292,7 +395,7 @@ func (m *myers[T]) split(smin, smax, tmin, tmax int, optimal bool, eq func(x, y
 		//
 		// A good diagonal is one that's longer than goodDiagMinLen, not too far from a corner and
 		// not too far from the middle diagonal.
-		if longestDiag >= goodDiagMinLen && d >= goodDiagCostLimit {
+		if longestDiag >= m.goodDiagMinLen && d >= m.goodDiagCostLimit {
 			best := struct {
 				v              int
 				s0, s1, t0, t1 int
@@ -307,7 +410,7 @@ func (m *myers[T]) split(smin, smax, tmin, tmax int, optimal bool, eq func(x, y
 				if s < smin || smax <= s || t < tmin || tmax <= t {
 					continue
 				}
-				if v <= goodDiagMagic*d || v < best.v {
+				if v <= m.goodDiagMagic*d || v < best.v {
 					continue // not good enough, check next diagonal
 				}
 
@@ -324,7 +427,7 @@ func (m *myers[T]) split(smin, smax, tmin, tmax int, optimal bool, eq func(x, y
 				ps := vf[pk+v0]
 				pt := ps - pk
 				diag := min(s-ps, t-pt) // number of diagonal steps
-				if diag < goodDiagMinLen {
+				if diag < m.goodDiagMinLen {
 					best.v = v
 					best.s0 = s - diag
 					best.s1 = s
@@ -343,7 +446,7 @@ func (m *myers[T]) split(smin, smax, tmin, tmax int, optimal bool, eq func(x, y
 					continue
 				}
 				v := (smax - s) + (tmax - t) - max(bmid-d, d-bmid)
-				if v <= goodDiagMagic*d || v < best.v {
+				if v <= m.goodDiagMagic*d || v < best.v {
 					continue
 				}
 
@@ -356,7 +459,7 @@ func (m *myers[T]) split(smin, smax, tmin, tmax int, optimal bool, eq func(x, y
 				ps := vb[pk+v0]
 				pt := ps - pk
 				diag := min(ps-s, pt-t) // number of diagonal steps
-				if diag >= goodDiagMinLen {
+				if diag >= m.goodDiagMinLen {
 					best.v = v
 					best.s0 = s
 					best.s1 = s + diag
@@ -367,6 +470,7 @@ func (m *myers[T]) split(smin, smax, tmin, tmax int, optimal bool, eq func(x, y
 				}
 			}
 			if best.v > 0 {
+				m.goodDiagUsed = true
 				return best.s0, best.s1, best.t0, best.t1, best.opt0, best.opt1
 			}
 		}
@@ -374,6 +478,8 @@ func (m *myers[T]) split(smin, smax, tmin, tmax int, optimal bool, eq func(x, y
 		// Heuristic (TOO_EXPENSIVE): Limit the amount of work to find an optimal path by picking
 		// a good-enough middle diagonal if we're over the cost limit.
 		if d >= m.costLimit {
+			m.tooExpensiveUsed = true
+
 			// Find endpoint of the furthest reaching forward d-path that maximizes x+y.
 			fbest, fbestk := math.MinInt, math.MinInt
 			for k := fmin; k <= fmax; k += 2 {
@@ -0,0 +1,47 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+// SetDiff compares x and y as multisets of the keys returned by key. It returns the elements that
+// only occur in x, the elements that only occur in y, and the elements that occur in both. The
+// first occurrences of a key are the common ones and all results are in the order of appearance,
+// common in the order of x.
+func SetDiff[T any, K comparable](x, y []T, key func(T) K) (onlyX, onlyY, common []T) {
+	// Number of occurrences in y that are still available to match elements in x.
+	avail := make(map[K]int, len(y))
+	for _, v := range y {
+		avail[key(v)]++
+	}
+	matched := make(map[K]int)
+	for _, v := range x {
+		k := key(v)
+		if avail[k] > 0 {
+			avail[k]--
+			matched[k]++
+			common = append(common, v)
+		} else {
+			onlyX = append(onlyX, v)
+		}
+	}
+	for _, v := range y {
+		k := key(v)
+		if matched[k] > 0 {
+			matched[k]--
+		} else {
+			onlyY = append(onlyY, v)
+		}
+	}
+	return onlyX, onlyY, common
+}
@@ -0,0 +1,79 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package impl
+
+// MatchesAtLeast reports whether the longest common subsequence of x and y has at least k
+// elements.
+//
+// It runs the greedy forward search of the Myers algorithm and stops as soon as the result is
+// known: A d-path that reaches (s, t) contains (s+t-d)/2 matches, which is a lower bound for the
+// length of the longest common subsequence. Conversely, if no (d-1)-path reaches the end of both
+// inputs, a minimal diff has at least d edits and the longest common subsequence has at most
+// (N+M-d)/2 elements. Similar inputs are therefore decided after only a few iterations.
+func MatchesAtLeast[T comparable](x, y []T, k int) bool {
+	if k <= 0 {
+		return true
+	}
+	if k > min(len(x), len(y)) {
+		return false
+	}
+	smin, smax, tmin, tmax := findChangeBounds(x, y)
+	k -= smin + len(x) - smax // common prefix and suffix
+	if k <= 0 {
+		return true
+	}
+	x, y = x[smin:smax], y[tmin:tmax]
+	n, m := len(x), len(y)
+
+	// v stores the furthest reaching s-coordinate of a d-path in diagonal k = s - t in v[v0+k], or
+	// -1 if no d-path within the bounds of x and y ends in diagonal k.
+	v0 := n + m
+	v := make([]int, 2*v0+1)
+	for d := 0; d <= n+m; d++ {
+		if (n+m-d)/2 < k {
+			return false
+		}
+		for kd := -d; kd <= d; kd += 2 {
+			s := -1
+			if d == 0 {
+				s = 0
+			}
+			if kd < d {
+				if p := v[v0+kd+1]; p >= 0 && p-kd <= m {
+					s = p // Step down from diagonal kd+1.
+				}
+			}
+			if kd > -d {
+				if p := v[v0+kd-1]; p >= 0 && p < n && p+1 > s {
+					s = p + 1 // Step right from diagonal kd-1.
+				}
+			}
+			v[v0+kd] = s
+			if s < 0 {
+				continue
+			}
+			t := s - kd
+			for s < n && t < m && x[s] == y[t] {
+				s++
+				t++
+			}
+			v[v0+kd] = s
+			if (s+t-d)/2 >= k {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -58,30 +58,61 @@ const maxIndent = 200
 // and avoid integer overflows.
 const maxBlanks = 20
 
-const startOfFilePenalty = 1               // No no-blank lines before the split
-const endOfFilePenalty = 21                // No non-blank lines after the split
-const totalBlankWeight = -30               // Weight for number of blank lines around the split
-const postBlankWeight = 6                  // Weight for number of blank lines after the split
-const relativeIndentPenalty = -4           // Indented more than predecessor
-const relativeIndentWithBlankPenalty = 10  // Indented more than predecessor, with blank lines
-const relativeOutdentPenalty = 24          // Indented less than predecessor
-const relativeOutdentWithBlankPenalty = 17 // Indented less than predecessor, with blank lines
-const relativeDedentPenalty = 23           // Indented less than predecessor but not less than successor
-const relativeDedentWithBlankPenalty = 17  // Indented less than predecessor but not less than successor, with blank lines
-
-// We only consider whether the sum of the effective indents for splits are less than (-1), equal
-// to (0), or greater than (+1) each other. The resulting value is multiplied by the following
-// weight and combined with the penalty to determine the better of two scores.
-const indentWeight = 60
-
-// Apply applies the indent heuristics to rx and ry.
+// Weights are the weights and penalties used to score the possible shifts of a group.
+type Weights struct {
+	StartOfFilePenalty              int // No non-blank lines before the split
+	EndOfFilePenalty                int // No non-blank lines after the split
+	TotalBlankWeight                int // Weight for number of blank lines around the split
+	PostBlankWeight                 int // Weight for number of blank lines after the split
+	RelativeIndentPenalty           int // Indented more than predecessor
+	RelativeIndentWithBlankPenalty  int // Indented more than predecessor, with blank lines
+	RelativeOutdentPenalty          int // Indented less than predecessor
+	RelativeOutdentWithBlankPenalty int // Indented less than predecessor, with blank lines
+	RelativeDedentPenalty           int // Indented less than predecessor but not less than successor
+	RelativeDedentWithBlankPenalty  int // Indented less than predecessor but not less than successor, with blank lines
+
+	// We only consider whether the sum of the effective indents for splits are less than (-1),
+	// equal to (0), or greater than (+1) each other. The resulting value is multiplied by the
+	// following weight and combined with the penalty to determine the better of two scores.
+	IndentWeight int
+}
+
+// DefaultWeights are the weights used by git.
+var DefaultWeights = Weights{
+	StartOfFilePenalty:              1,
+	EndOfFilePenalty:                21,
+	TotalBlankWeight:                -30,
+	PostBlankWeight:                 6,
+	RelativeIndentPenalty:           -4,
+	RelativeIndentWithBlankPenalty:  10,
+	RelativeOutdentPenalty:          24,
+	RelativeOutdentWithBlankPenalty: 17,
+	RelativeDedentPenalty:           23,
+	RelativeDedentWithBlankPenalty:  17,
+	IndentWeight:                    60,
+}
+
+// Apply applies the indent heuristics to rx and ry using the default weights.
 func Apply(x, y []byteview.ByteView, rx, ry []bool) {
-	apply0(x, y, rx, ry) // for deletions
-	apply0(y, x, ry, rx) // for insertions
+	ApplyWeights(x, y, rx, ry, &DefaultWeights)
+}
+
+// ApplyWeights applies the indent heuristics to rx and ry using the weights w.
+func ApplyWeights(x, y []byteview.ByteView, rx, ry []bool, w *Weights) {
+	ApplyWeightsExcept(x, y, rx, ry, w, nil, nil)
+}
+
+// ApplyWeightsExcept is like [ApplyWeights], but doesn't use the indentation to place groups of
+// deletions or insertions whose lines are all marked in skipx or skipy, respectively. These groups
+// are still merged and aligned, but are otherwise shifted down as far as possible, like git does
+// without the indent heuristic. A nil skipx or skipy doesn't mark any lines.
+func ApplyWeightsExcept(x, y []byteview.ByteView, rx, ry []bool, w *Weights, skipx, skipy []bool) {
+	apply0(x, y, rx, ry, w, skipx) // for deletions
+	apply0(y, x, ry, rx, w, skipy) // for insertions
 }
 
 // apply0 applies the indentation heuristics to r.
-func apply0(lines, lineso []byteview.ByteView, r, ro []bool) {
+func apply0(lines, lineso []byteview.ByteView, r, ro []bool, w *Weights, skip []bool) {
 	s, so := newScanner(lines, r), newScanner(lineso, ro)
 	for s.nextGroup() {
 		if !so.nextGroup() {
@@ -135,6 +166,8 @@ func apply0(lines, lineso []byteview.ByteView, r, ro []bool) {
 					panic("scanner sync broken")
 				}
 			}
+		case skip != nil && allSet(skip[s.start:s.end]):
+			// excluded from the heuristic, keep the group at its lowest position
 		default:
 			// The group can be shifted around somewhat, we can use the possible shift range to
 			// apply heuristics that make the diff easier to read. Right now, the group is shifted
@@ -144,9 +177,9 @@ func apply0(lines, lineso []byteview.ByteView, r, ro []bool) {
 			var bestScore shiftScore
 			for shift := max(minEnd, s.end-grpLen-1, s.end-maxSliding); shift <= s.end; shift++ {
 				score := shiftScore{}
-				score.add(measureShift(lines, shift))
-				score.add(measureShift(lines, shift-grpLen))
-				if bestShift == -1 || score.cmp(bestScore) <= 0 {
+				score.add(measureShift(lines, shift), w)
+				score.add(measureShift(lines, shift-grpLen), w)
+				if bestShift == -1 || score.cmp(bestScore, w) <= 0 {
 					bestShift = shift
 					bestScore = score
 				}
@@ -168,6 +201,16 @@ func apply0(lines, lineso []byteview.ByteView, r, ro []bool) {
 	}
 }
 
+// allSet reports whether all values in v are true.
+func allSet(v []bool) bool {
+	for _, b := range v {
+		if !b {
+			return false
+		}
+	}
+	return true
+}
+
 type scanner struct {
 	start int // First changed line of the current group if non-empty, or unchanged line if empty.
 	end   int // First unchanged line after the group. For an empty group, start == end.
@@ -318,12 +361,12 @@ type shiftScore struct {
 	penalty         int // smaller is better
 }
 
-func (s *shiftScore) add(m measure) {
+func (s *shiftScore) add(m measure, w *Weights) {
 	if m.preIndent == 1 && m.preBlank == 0 {
-		s.penalty += startOfFilePenalty
+		s.penalty += w.StartOfFilePenalty
 	}
 	if m.endOfFile {
-		s.penalty += endOfFilePenalty
+		s.penalty += w.EndOfFilePenalty
 	}
 
 	postBlank := 0
@@ -333,8 +376,8 @@ func (s *shiftScore) add(m measure) {
 	totalBlank := m.preBlank + postBlank
 
 	// Penalties based on nearby blank lines
-	s.penalty += totalBlankWeight * totalBlank
-	s.penalty += postBlankWeight * postBlank
+	s.penalty += w.TotalBlankWeight * totalBlank
+	s.penalty += w.PostBlankWeight * postBlank
 
 	indent := m.indent
 	if indent == -1 {
@@ -348,9 +391,9 @@ func (s *shiftScore) add(m measure) {
 	} else if indent > m.preIndent {
 		// The line is indented more than its predecessors.
 		if totalBlank != 0 {
-			s.penalty += relativeIndentWithBlankPenalty
+			s.penalty += w.RelativeIndentWithBlankPenalty
 		} else {
-			s.penalty = relativeIndentPenalty
+			s.penalty = w.RelativeIndentPenalty
 		}
 	} else if indent == m.preIndent {
 		// Same indentation as previous line, no adjustments need.
@@ -363,20 +406,20 @@ func (s *shiftScore) add(m measure) {
 			// The following line is indented more. So it's likely that this line is the start of a
 			// block.
 			if totalBlank != 0 {
-				s.penalty += relativeOutdentWithBlankPenalty
+				s.penalty += w.RelativeOutdentWithBlankPenalty
 			} else {
-				s.penalty += relativeOutdentPenalty
+				s.penalty += w.RelativeOutdentPenalty
 			}
 		} else {
 			if totalBlank != 0 {
-				s.penalty += relativeDedentWithBlankPenalty
+				s.penalty += w.RelativeDedentWithBlankPenalty
 			} else {
-				s.penalty += relativeDedentPenalty
+				s.penalty += w.RelativeDedentPenalty
 			}
 		}
 	}
 }
 
-func (s *shiftScore) cmp(t shiftScore) int {
-	return indentWeight*cmp.Compare(s.effectiveIndent, t.effectiveIndent) + s.penalty - t.penalty
+func (s *shiftScore) cmp(t shiftScore, w *Weights) int {
+	return w.IndentWeight*cmp.Compare(s.effectiveIndent, t.effectiveIndent) + s.penalty - t.penalty
 }
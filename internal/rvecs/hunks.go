@@ -27,9 +27,18 @@ type Hunk struct {
 	Edits  int // Number of edits in this hunk.
 }
 
+// Hunks groups the edits in rx and ry into hunks with cfg.Context matches of context. Hunks are
+// computed from the final result vectors, i.e., after all heuristics and post-processing steps have
+// been applied. Two groups of edits that are separated by at most 2*cfg.Context matches always end
+// up in the same hunk, independently of how the result vectors were computed. Consequently, hunks
+// never touch or overlap: No element of x or y is ever part of more than one hunk. This also holds
+// for the hunks returned by [HunksExpanded] and [SplitHunks].
+//
+// If cfg.MaxHunks > 0, the iteration stops after that many hunks.
 func Hunks(rx, ry []bool, cfg config.Config) iter.Seq[Hunk] {
 	return func(yield func(Hunk) bool) {
 		context := cfg.Context
+		nhunks := 0      // number of hunks yielded so far
 		s, t := 0, 0     // current index into x, y
 		s0, t0 := -1, -1 // start of the current hunk
 		d := 0           // number of edits in the current hunk
@@ -70,8 +79,201 @@ func Hunks(rx, ry []bool, cfg config.Config) iter.Seq[Hunk] {
 				if !yield(Hunk{s0, s + Δ, t0, t + Δ, d + Δ}) {
 					break
 				}
+				nhunks++
+				if nhunks == cfg.MaxHunks {
+					break
+				}
 				s0, t0 = -1, -1
 			}
 		}
 	}
 }
+
+// HunksExpanded is like [Hunks], but additionally extends every hunk to the range in x returned by
+// expand. expand is called with the range of the changes in a hunk, i.e., without the context, and
+// returns the range that needs to be part of the hunk. Hunks that touch or overlap after expansion
+// are merged. Just like the context, the additional lines are always matches.
+//
+// The expansion may reach back to earlier hunks, all hunks are therefore computed before the first
+// one is yielded. If cfg.MaxHunks > 0, the iteration stops after that many expanded hunks.
+func HunksExpanded(rx, ry []bool, cfg config.Config, expand func(s0, s1 int) (int, int)) iter.Seq[Hunk] {
+	return func(yield func(Hunk) bool) {
+		maxHunks := cfg.MaxHunks
+		cfg.MaxHunks = 0
+
+		// A group of hunks that is merged into a single hunk, hunk describes the first and the last
+		// hunk in the group, s0 and s1 is the range in x the group needs to cover.
+		type group struct {
+			first, last Hunk
+			s0, s1      int
+		}
+		var groups []group
+		for h := range Hunks(rx, ry, cfg) {
+			// Find the changes in h by skipping the context.
+			c0, t := h.S0, h.T0
+			for c0 < h.S1 && t < h.T1 && !rx[c0] && !ry[t] {
+				c0++
+				t++
+			}
+			c1, t := h.S1, h.T1
+			for c1 > c0 && t > h.T0 && !rx[c1-1] && !ry[t-1] {
+				c1--
+				t--
+			}
+			s0, s1 := expand(c0, c1)
+			g := group{first: h, last: h, s0: min(s0, h.S0), s1: max(s1, h.S1)}
+			for len(groups) > 0 && g.s0 <= groups[len(groups)-1].s1 {
+				prev := groups[len(groups)-1]
+				groups = groups[:len(groups)-1]
+				g = group{first: prev.first, last: g.last, s0: min(prev.s0, g.s0), s1: max(prev.s1, g.s1)}
+			}
+			groups = append(groups, g)
+		}
+
+		for i, g := range groups {
+			if maxHunks > 0 && i == maxHunks {
+				return
+			}
+			// The lines between the hunks of different groups are matches, x and y advance in lockstep.
+			t0 := g.first.T0 - (g.first.S0 - g.s0)
+			t1 := g.last.T1 + (g.s1 - g.last.S1)
+			edits := g.s1 - g.s0
+			for _, r := range ry[t0:t1] {
+				if r {
+					edits++
+				}
+			}
+			if !yield(Hunk{g.s0, g.s1, t0, t1, edits}) {
+				return
+			}
+		}
+	}
+}
+
+// SplitHunks splits every hunk in hunks with more than n edits into consecutive hunks with at most
+// n edits each. Hunks are preferably split right after the last match that follows a change in a
+// part, so that the next part starts with a change. If there's no such match, hunks are split after
+// exactly n edits. Unlike the hunks returned by [Hunks], consecutive parts of a split hunk touch each
+// other, but they still don't overlap.
+//
+// Every part contains at least one change. Matches that would end up in a part without changes,
+// e.g. the leading or trailing context of a hunk, are merged into the neighboring part instead,
+// which then has more than n edits.
+func SplitHunks(hunks iter.Seq[Hunk], rx, ry []bool, n int) iter.Seq[Hunk] {
+	return func(yield func(Hunk) bool) {
+		for h := range hunks {
+			if h.Edits <= n {
+				if !yield(h) {
+					return
+				}
+				continue
+			}
+			s0, t0 := h.S0, h.T0 // start of the current part
+			for s0 < h.S1 || t0 < h.T1 {
+				s, t := s0, t0
+				d, c := 0, 0             // number of edits and changes in the current part
+				cs, ct, cd := -1, -1, -1 // last position right after a match and edits up to there
+				for (d < n || c == 0) && (s < h.S1 || t < h.T1) {
+					switch {
+					case s < h.S1 && rx[s]:
+						s++
+						c++
+					case t < h.T1 && ry[t]:
+						t++
+						c++
+					default:
+						s++
+						t++
+						if c > 0 {
+							cs, ct, cd = s, t, d+1
+						}
+					}
+					d++
+				}
+				if (s < h.S1 || t < h.T1) && cd > 0 && cd < d {
+					s, t, d = cs, ct, cd
+				}
+				if !hasChanges(rx[s:h.S1]) && !hasChanges(ry[t:h.T1]) {
+					// Only matches are left, merge them into this part.
+					d += h.S1 - s
+					s, t = h.S1, h.T1
+				}
+				if !yield(Hunk{s0, s, t0, t, d}) {
+					return
+				}
+				s0, t0 = s, t
+			}
+		}
+	}
+}
+
+// hasChanges reports whether r contains any edit.
+func hasChanges(r []bool) bool {
+	for _, v := range r {
+		if v {
+			return true
+		}
+	}
+	return false
+}
+
+// Op is the kind of a [Run].
+type Op int
+
+const (
+	Match Op = iota
+	Delete
+	Insert
+)
+
+// Run is a sequence of N consecutive edits of the same kind. S and T are the positions in x and y
+// at the start of the run.
+type Run struct {
+	Op   Op
+	S, T int
+	N    int
+}
+
+// Runs returns the runs of edits in h in the order they are presented to users. If paired is set,
+// a run of deletions that's directly followed by a run of insertions of the same length is
+// interleaved into deletion and insertion pairs, see [config.Config.PairedOrdering].
+func Runs(rx, ry []bool, h Hunk, paired bool) iter.Seq[Run] {
+	return func(yield func(Run) bool) {
+		for s, t := h.S0, h.T0; s < h.S1 || t < h.T1; {
+			ndel, nins := RunLen(rx[s:h.S1]), RunLen(ry[t:h.T1])
+			if paired && ndel > 0 && ndel == nins {
+				for range ndel {
+					if !yield(Run{Delete, s, t, 1}) || !yield(Run{Insert, s + 1, t, 1}) {
+						return
+					}
+					s++
+					t++
+				}
+				ndel, nins = 0, 0
+			}
+			if ndel > 0 {
+				if !yield(Run{Delete, s, t, ndel}) {
+					return
+				}
+				s += ndel
+			}
+			if nins > 0 {
+				if !yield(Run{Insert, s, t, nins}) {
+					return
+				}
+				t += nins
+			}
+			n := 0
+			for s+n < h.S1 && t+n < h.T1 && !rx[s+n] && !ry[t+n] {
+				n++
+			}
+			if n > 0 {
+				if !yield(Run{Match, s, t, n}) {
+					return
+				}
+				s += n
+				t += n
+			}
+		}
+	}
+}
@@ -15,6 +15,8 @@
 package rvecs
 
 import (
+	"iter"
+	"math/rand/v2"
 	"slices"
 	"testing"
 
@@ -27,6 +29,7 @@ func TestHunks(t *testing.T) {
 		name      string
 		rx, ry    []bool
 		context   int
+		maxHunks  int
 		wantHunks []Hunk
 		wantEdits int
 	}{
@@ -71,14 +74,328 @@ func TestHunks(t *testing.T) {
 			},
 			wantEdits: 5,
 		},
+		{
+			name:     "ABCABBA_to_CBABAC_context_0_max_2",
+			rx:       []bool{true, false, true, false, false, true, false, false},
+			ry:       []bool{true, false, false, false, false, true, false},
+			context:  0,
+			maxHunks: 2,
+			wantHunks: []Hunk{
+				{0, 1, 0, 1, 2},
+				{2, 3, 2, 2, 1},
+			},
+			wantEdits: 3,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := slices.Collect(Hunks(tt.rx, tt.ry, config.Config{Context: tt.context}))
+			got := slices.Collect(Hunks(tt.rx, tt.ry, config.Config{Context: tt.context, MaxHunks: tt.maxHunks}))
 			if diff := cmp.Diff(tt.wantHunks, got); diff != "" {
 				t.Errorf("Hunks(...) result are different [-want,+got]:\n%s", diff)
 			}
 		})
 	}
 }
+
+// randomResultVectors creates random but consistent result vectors, i.e., with the same number of
+// matches in x and y.
+func randomResultVectors(rng *rand.Rand) (rx, ry []bool) {
+	for {
+		rx, ry = make([]bool, rng.IntN(40)+1), make([]bool, rng.IntN(40)+1)
+		matches := 0
+		for s := range len(rx) - 1 {
+			rx[s] = rng.IntN(3) == 0
+			if !rx[s] {
+				matches++
+			}
+		}
+		if matches > len(ry)-1 {
+			continue
+		}
+		for _, t := range rng.Perm(len(ry) - 1)[:len(ry)-1-matches] {
+			ry[t] = true
+		}
+		return rx, ry
+	}
+}
+
+func TestHunksNeverTouch(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := range 10000 {
+		rx, ry := randomResultVectors(rng)
+		context := rng.IntN(4)
+		hunks := slices.Collect(Hunks(rx, ry, config.Config{Context: context}))
+		edits := 0
+		for j, h := range hunks {
+			edits += h.Edits
+			if j > 0 && (h.S0 <= hunks[j-1].S1 || h.T0 <= hunks[j-1].T1) {
+				t.Fatalf("%d: hunks touch with context %d:\nrx = %v\nry = %v\nhunks = %v", i, context, rx, ry, hunks)
+			}
+		}
+		for _, r := range [][]bool{rx, ry} {
+			for _, v := range r {
+				if v {
+					edits--
+				}
+			}
+		}
+		if context == 0 && edits != 0 {
+			t.Fatalf("%d: hunks don't cover all edits:\nrx = %v\nry = %v\nhunks = %v", i, rx, ry, hunks)
+		}
+	}
+}
+
+func TestHunksDisjoint(t *testing.T) {
+	// checkDisjoint fails if an element of x or y is part of more than one hunk.
+	checkDisjoint := func(i int, name string, rx, ry []bool, hunks iter.Seq[Hunk]) {
+		t.Helper()
+		cx, cy := make([]int, len(rx)-1), make([]int, len(ry)-1)
+		for h := range hunks {
+			if h.S0 < 0 || h.S1 > len(cx) || h.T0 < 0 || h.T1 > len(cy) {
+				t.Fatalf("%d: %s: hunk %v is out of bounds:\nrx = %v\nry = %v", i, name, h, rx, ry)
+			}
+			for _, c := range [][]int{cx[h.S0:h.S1], cy[h.T0:h.T1]} {
+				for j := range c {
+					c[j]++
+					if c[j] > 1 {
+						t.Fatalf("%d: %s: element is part of more than one hunk, hunk %v:\nrx = %v\nry = %v", i, name, h, rx, ry)
+					}
+				}
+			}
+		}
+	}
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := range 2000 {
+		rx, ry := randomResultVectors(rng)
+		for context := range 6 {
+			cfg := config.Config{Context: context}
+			checkDisjoint(i, "Hunks", rx, ry, Hunks(rx, ry, cfg))
+			checkDisjoint(i, "SplitHunks", rx, ry, SplitHunks(Hunks(rx, ry, cfg), rx, ry, rng.IntN(5)+1))
+			expand := func(s0, s1 int) (int, int) {
+				return max(0, s0-rng.IntN(4)), min(len(rx)-1, s1+rng.IntN(4))
+			}
+			checkDisjoint(i, "HunksExpanded", rx, ry, HunksExpanded(rx, ry, cfg, expand))
+		}
+	}
+}
+
+func TestHunksExpanded(t *testing.T) {
+	// x has 10 elements, x[2] and x[7] are deleted.
+	rx := []bool{false, false, true, false, false, false, false, true, false, false, false}
+	ry := []bool{false, false, false, false, false, false, false, false, false}
+	identity := func(s0, s1 int) (int, int) { return s0, s1 }
+	tests := []struct {
+		name     string
+		context  int
+		maxHunks int
+		expand   func(s0, s1 int) (int, int)
+		want     []Hunk
+	}{
+		{
+			name:   "identity",
+			expand: identity,
+			want:   []Hunk{{2, 3, 2, 2, 1}, {7, 8, 6, 6, 1}},
+		},
+		{
+			name:    "identity-context",
+			context: 1,
+			expand:  identity,
+			want:    []Hunk{{1, 4, 1, 3, 3}, {6, 9, 5, 7, 3}},
+		},
+		{
+			name: "expand-start",
+			expand: func(s0, s1 int) (int, int) {
+				if s0 == 7 {
+					return 5, s1
+				}
+				return s0, s1
+			},
+			want: []Hunk{{2, 3, 2, 2, 1}, {5, 8, 4, 6, 3}},
+		},
+		{
+			name: "expand-end",
+			expand: func(s0, s1 int) (int, int) {
+				return s0, min(s1+2, 10)
+			},
+			want: []Hunk{{2, 5, 2, 4, 3}, {7, 10, 6, 8, 3}},
+		},
+		{
+			name: "merge-backward",
+			expand: func(s0, s1 int) (int, int) {
+				if s0 == 7 {
+					return 1, 9
+				}
+				return s0, s1
+			},
+			want: []Hunk{{1, 9, 1, 7, 8}},
+		},
+		{
+			name: "merge-forward",
+			expand: func(s0, s1 int) (int, int) {
+				if s0 == 2 {
+					return 0, 8
+				}
+				return s0, s1
+			},
+			want: []Hunk{{0, 8, 0, 6, 8}},
+		},
+		{
+			name: "merge-touching",
+			expand: func(s0, s1 int) (int, int) {
+				if s0 == 2 {
+					return s0, 7
+				}
+				return s0, s1
+			},
+			want: []Hunk{{2, 8, 2, 6, 6}},
+		},
+		{
+			name:     "max-hunks",
+			maxHunks: 1,
+			expand: func(s0, s1 int) (int, int) {
+				return max(s0-1, 0), s1
+			},
+			want: []Hunk{{1, 3, 1, 2, 2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Config{Context: tt.context, MaxHunks: tt.maxHunks}
+			got := slices.Collect(HunksExpanded(rx, ry, cfg, tt.expand))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("HunksExpanded(...) result is different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSplitHunks(t *testing.T) {
+	// x = "abcdef" and y = "ABcDEF", i.e., a single hunk with 11 edits.
+	rx := []bool{true, true, false, true, true, true, false}
+	ry := []bool{true, true, false, true, true, true, false}
+	tests := []struct {
+		name string
+		n    int
+		want []Hunk
+	}{
+		{
+			name: "not-split",
+			n:    11,
+			want: []Hunk{{0, 6, 0, 6, 11}},
+		},
+		{
+			name: "at-match",
+			n:    6,
+			want: []Hunk{{0, 3, 0, 3, 5}, {3, 6, 3, 6, 6}},
+		},
+		{
+			name: "arbitrary",
+			n:    5,
+			want: []Hunk{{0, 3, 0, 3, 5}, {3, 6, 3, 5, 5}, {6, 6, 5, 6, 1}},
+		},
+		{
+			name: "single-edits",
+			n:    1,
+			want: []Hunk{
+				{0, 1, 0, 0, 1}, {1, 2, 0, 0, 1}, {2, 2, 0, 1, 1}, {2, 2, 1, 2, 1},
+				{2, 4, 2, 3, 2}, {4, 5, 3, 3, 1}, {5, 6, 3, 3, 1},
+				{6, 6, 3, 4, 1}, {6, 6, 4, 5, 1}, {6, 6, 5, 6, 1},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := slices.Collect(SplitHunks(Hunks(rx, ry, config.Default), rx, ry, tt.n))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("SplitHunks(...) result is different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSplitHunksRandom(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := range 10000 {
+		rx, ry := randomResultVectors(rng)
+		cfg := config.Config{Context: rng.IntN(4)}
+		n := rng.IntN(10) + 1
+		for h := range Hunks(rx, ry, cfg) {
+			parts := slices.Collect(SplitHunks(slices.Values([]Hunk{h}), rx, ry, n))
+			edits := 0
+			for j, p := range parts {
+				edits += p.Edits
+				changes := 0
+				for _, r := range [][]bool{rx[p.S0:p.S1], ry[p.T0:p.T1]} {
+					for _, v := range r {
+						if v {
+							changes++
+						}
+					}
+				}
+				if changes == 0 {
+					t.Fatalf("%d: part %v of hunk %v has no changes: %v", i, p, h, parts)
+				}
+				// Only context that would otherwise end up in a part without changes is merged into
+				// a part with more than n edits.
+				if p.Edits > n+3*cfg.Context {
+					t.Fatalf("%d: part %v of hunk %v has more than %d edits", i, p, h, n+3*cfg.Context)
+				}
+				if j > 0 && (p.S0 != parts[j-1].S1 || p.T0 != parts[j-1].T1) {
+					t.Fatalf("%d: parts of hunk %v aren't consecutive: %v", i, h, parts)
+				}
+			}
+			first, last := parts[0], parts[len(parts)-1]
+			if first.S0 != h.S0 || first.T0 != h.T0 || last.S1 != h.S1 || last.T1 != h.T1 || edits != h.Edits {
+				t.Fatalf("%d: parts don't cover hunk %v: %v", i, h, parts)
+			}
+		}
+	}
+}
+
+func TestRuns(t *testing.T) {
+	// x = "abcde" and y = "aBCdEF".
+	rx := []bool{false, true, true, false, true, false}
+	ry := []bool{false, true, true, false, true, true, false}
+	h := Hunk{0, 5, 0, 6, 9}
+	tests := []struct {
+		name   string
+		paired bool
+		want   []Run
+	}{
+		{
+			name: "default",
+			want: []Run{
+				{Match, 0, 0, 1},
+				{Delete, 1, 1, 2},
+				{Insert, 3, 1, 2},
+				{Match, 3, 3, 1},
+				{Delete, 4, 4, 1},
+				{Insert, 5, 4, 2},
+			},
+		},
+		{
+			name:   "paired",
+			paired: true,
+			want: []Run{
+				{Match, 0, 0, 1},
+				{Delete, 1, 1, 1},
+				{Insert, 2, 1, 1},
+				{Delete, 2, 2, 1},
+				{Insert, 3, 2, 1},
+				{Match, 3, 3, 1},
+				{Delete, 4, 4, 1},
+				{Insert, 5, 4, 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := slices.Collect(Runs(rx, ry, h, tt.paired))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Runs(...) result is different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -24,3 +24,12 @@ func Make[T any](x, y []T) (rx, ry []bool) {
 	ry = r[len(x)+1:]
 	return
 }
+
+// RunLen returns the number of consecutive edits at the beginning of r.
+func RunLen(r []bool) int {
+	n := 0
+	for n < len(r) && r[n] {
+		n++
+	}
+	return n
+}
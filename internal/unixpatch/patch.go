@@ -60,3 +60,30 @@ func Patch(orig, diff string) (string, error) {
 
 	return string(out), nil
 }
+
+// Ed applies an ed script to orig using the ed line editor.
+func Ed(orig, script string) (string, error) {
+	dir, err := os.MkdirTemp("", "ed-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	origfile := filepath.Join(dir, "orig")
+	if err := os.WriteFile(origfile, []byte(orig), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write orig file: %v", err)
+	}
+
+	cmd := exec.Command("ed", "-s", origfile)
+	cmd.Stdin = strings.NewReader(script + "w\nq\n")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to run ed command: %s: %v\n%s", strings.Join(cmd.Args, " "), err, out)
+	}
+
+	out, err := os.ReadFile(origfile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read outfile: %v", err)
+	}
+
+	return string(out), nil
+}
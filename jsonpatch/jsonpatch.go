@@ -0,0 +1,108 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package jsonpatch creates JSON Patch (RFC 6902) operations from diffs of JSON arrays.
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+
+	"znkr.io/diff"
+	"znkr.io/diff/internal/config"
+	"znkr.io/diff/internal/impl"
+	"znkr.io/diff/internal/rvecs"
+)
+
+// Operation is a single JSON Patch operation. It marshals to the JSON representation defined in
+// RFC 6902.
+type Operation struct {
+	Op    string          `json:"op"`              // One of "add", "remove", or "replace".
+	Path  string          `json:"path"`            // JSON Pointer to the array element, e.g. "/3".
+	Value json.RawMessage `json:"value,omitempty"` // Value for "add" and "replace".
+}
+
+// Array compares the JSON array elements x and y and returns the JSON Patch operations necessary to
+// convert from one to the other.
+//
+// Elements are compared by their canonical form, i.e., differences in whitespace or in the order of
+// object keys don't result in an operation. A removal that is followed by an addition at the same
+// position is reported as a replacement.
+//
+// The operations are meant to be applied in order: Every operation refers to the array after all
+// preceding operations have been applied.
+//
+// The following options are supported: [diff.Minimal], [diff.PreferLongMatches], [diff.Fast],
+// [diff.AutoFast], [diff.Parallel]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func Array(x, y []json.RawMessage, opts ...diff.Option) []Operation {
+	cfg := config.FromOptions(opts, config.Algorithm)
+	rx, ry := impl.Diff(canonicalize(x), canonicalize(y), cfg)
+
+	var ops []Operation
+	i := 0 // Index into the array after applying all operations so far.
+	for s, t := 0, 0; s < len(x) || t < len(y); {
+		if !rx[s] && !ry[t] {
+			i++
+			s++
+			t++
+			continue
+		}
+
+		// A run of deletions and insertions.
+		ndel, nins := rvecs.RunLen(rx[s:]), rvecs.RunLen(ry[t:])
+		n := min(ndel, nins)
+		for k := range n {
+			ops = append(ops, Operation{Op: "replace", Path: path(i), Value: y[t+k]})
+			i++
+		}
+		for range ndel - n {
+			ops = append(ops, Operation{Op: "remove", Path: path(i)})
+		}
+		for k := n; k < nins; k++ {
+			ops = append(ops, Operation{Op: "add", Path: path(i), Value: y[t+k]})
+			i++
+		}
+		s += ndel
+		t += nins
+	}
+	return ops
+}
+
+func path(i int) string { return "/" + strconv.Itoa(i) }
+
+// canonicalize returns the canonical form of every element in in. Objects keys are sorted and
+// insignificant whitespace is removed. Invalid JSON is compared as is.
+func canonicalize(in []json.RawMessage) []string {
+	out := make([]string, len(in))
+	for i, raw := range in {
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber() // don't lose precision
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			out[i] = string(raw)
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			out[i] = string(raw)
+			continue
+		}
+		out[i] = string(b)
+	}
+	return out
+}
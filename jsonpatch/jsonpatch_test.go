@@ -0,0 +1,182 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jsonpatch
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"znkr.io/diff"
+)
+
+func TestArray(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y string
+		want []Operation
+	}{
+		{
+			name: "empty",
+			x:    `[]`,
+			y:    `[]`,
+		},
+		{
+			name: "identical",
+			x:    `[1, {"a": 1, "b": 2}]`,
+			y:    `[1, {"b":2,"a":1}]`,
+		},
+		{
+			name: "append",
+			x:    `[1, 2]`,
+			y:    `[1, 2, 3]`,
+			want: []Operation{
+				{Op: "add", Path: "/2", Value: json.RawMessage(`3`)},
+			},
+		},
+		{
+			name: "remove",
+			x:    `[1, 2, 3, 4]`,
+			y:    `[1, 4]`,
+			want: []Operation{
+				{Op: "remove", Path: "/1"},
+				{Op: "remove", Path: "/1"},
+			},
+		},
+		{
+			name: "replace",
+			x:    `[1, 2, 3]`,
+			y:    `[1, "two", 3]`,
+			want: []Operation{
+				{Op: "replace", Path: "/1", Value: json.RawMessage(`"two"`)},
+			},
+		},
+		{
+			name: "mixed",
+			x:    `["a", "b", "c", "d", "e"]`,
+			y:    `["x", "b", "d", "e", "f", "g"]`,
+			want: []Operation{
+				{Op: "replace", Path: "/0", Value: json.RawMessage(`"x"`)},
+				{Op: "remove", Path: "/2"},
+				{Op: "add", Path: "/4", Value: json.RawMessage(`"f"`)},
+				{Op: "add", Path: "/5", Value: json.RawMessage(`"g"`)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y := parse(t, tt.x), parse(t, tt.y)
+			got := Array(x, y)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Array(...) result is different (-want, +got):\n%s", diff)
+			}
+
+			patched := apply(t, x, got)
+			if diff := cmp.Diff(canonicalize(y), canonicalize(patched)); diff != "" {
+				t.Errorf("array is different after applying patch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestArrayOptions(t *testing.T) {
+	x, y := parse(t, `[1, 2, 3, 4]`), parse(t, `[2, 1, 3, 5]`)
+	for _, tt := range []struct {
+		name string
+		opts []diff.Option
+	}{
+		{"minimal", []diff.Option{diff.Minimal()}},
+		{"prefer-long-matches", []diff.Option{diff.PreferLongMatches()}},
+		{"fast", []diff.Option{diff.Fast()}},
+		{"parallel", []diff.Option{diff.Parallel(2)}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			patched := apply(t, x, Array(x, y, tt.opts...))
+			if diff := cmp.Diff(canonicalize(y), canonicalize(patched)); diff != "" {
+				t.Errorf("array is different after applying patch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestArrayUnsupportedOption(t *testing.T) {
+	// Options that make diff.Edits report moves must be rejected instead of being passed on.
+	for _, tt := range []struct {
+		name string
+		opt  diff.Option
+	}{
+		{"detect-swaps", diff.DetectSwaps()},
+		{"detect-modified-moves", diff.DetectModifiedMoves(0.5)},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Array(...) didn't panic")
+				}
+			}()
+			Array(parse(t, `[1, 2]`), parse(t, `[2, 1]`), tt.opt)
+		})
+	}
+}
+
+func TestOperationJSON(t *testing.T) {
+	ops := []Operation{
+		{Op: "add", Path: "/0", Value: json.RawMessage(`{"a":1}`)},
+		{Op: "remove", Path: "/1"},
+	}
+	got, err := json.Marshal(ops)
+	if err != nil {
+		t.Fatalf("json.Marshal(...) failed: %v", err)
+	}
+	want := `[{"op":"add","path":"/0","value":{"a":1}},{"op":"remove","path":"/1"}]`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("json.Marshal(...) result is different (-want, +got):\n%s", diff)
+	}
+}
+
+func parse(t *testing.T, s string) []json.RawMessage {
+	t.Helper()
+	var out []json.RawMessage
+	if err := json.Unmarshal([]byte(s), &out); err != nil {
+		t.Fatalf("failed to parse %q: %v", s, err)
+	}
+	return out
+}
+
+// apply applies ops to a copy of x.
+func apply(t *testing.T, x []json.RawMessage, ops []Operation) []json.RawMessage {
+	t.Helper()
+	out := slices.Clone(x)
+	for _, op := range ops {
+		i, err := strconv.Atoi(strings.TrimPrefix(op.Path, "/"))
+		if err != nil || i < 0 || i > len(out) {
+			t.Fatalf("invalid path in %+v", op)
+		}
+		switch op.Op {
+		case "add":
+			out = slices.Insert(out, i, op.Value)
+		case "remove":
+			out = slices.Delete(out, i, i+1)
+		case "replace":
+			out[i] = op.Value
+		default:
+			t.Fatalf("unknown op in %+v", op)
+		}
+	}
+	return out
+}
@@ -11,11 +11,14 @@ func _() {
 	_ = x[Match-0]
 	_ = x[Delete-1]
 	_ = x[Insert-2]
+	_ = x[Modify-3]
+	_ = x[Move-4]
+	_ = x[MoveModify-5]
 }
 
-const _Op_name = "MatchDeleteInsert"
+const _Op_name = "MatchDeleteInsertModifyMoveMoveModify"
 
-var _Op_index = [...]uint8{0, 5, 11, 17}
+var _Op_index = [...]uint8{0, 5, 11, 17, 23, 27, 37}
 
 func (i Op) String() string {
 	idx := int(i) - 0
@@ -14,11 +14,29 @@
 
 package diff
 
-import "znkr.io/diff/internal/config"
+import (
+	"fmt"
+	"time"
+
+	"znkr.io/diff/internal/config"
+)
 
 // Option configures the behavior of comparison functions.
 type Option = config.Option
 
+// Sets of options supported by the comparison functions in this package.
+const (
+	// diffFlags are the options that select and tune the diff algorithm for comparable elements.
+	diffFlags = config.Algorithm | config.CostLimit | config.GoodDiagonalTuning
+
+	// funcFlags are the options that tune the diff algorithm for elements that are compared using
+	// an equality function.
+	funcFlags = config.Minimal | config.PreferLongMatches | config.CostLimit | config.GoodDiagonalTuning | config.DeterministicSplit
+
+	// hunkFlags are the options that control how hunks are formed.
+	hunkFlags = config.Context | config.PairedOrdering | config.MaxHunks
+)
+
 // Context sets the number of unchanged elements to include around each hunk. The default is 3.
 //
 // Context anchors diffs in the surrounding context in addition to position information. For
@@ -32,6 +50,72 @@ func Context(n int) Option {
 	}
 }
 
+// MaxHunks limits the number of hunks to at most k. Values < 1 are treated as 1.
+//
+// The diff is always computed for the whole input, but only the first k hunks are created. Omitted
+// hunks are only counted, not computed. Use [HunksTruncated] to find out how many hunks were
+// omitted, e.g., to show a "… and N more" note.
+//
+// Only supported by functions that return hunks.
+func MaxHunks(k int) Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.MaxHunks = max(1, k)
+		return config.MaxHunks
+	}
+}
+
+// PairedOrdering changes the order of edits within hunks: A run of deletions that is followed by a
+// run of insertions of the same length is emitted as alternating pairs of a deletion and an
+// insertion. Runs of different lengths are emitted unchanged, i.e., all deletions before all
+// insertions.
+//
+// This only affects the order of edits, not which elements are reported as edits.
+func PairedOrdering() Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.PairedOrdering = true
+		return config.PairedOrdering
+	}
+}
+
+// DetectSwaps reports a deletion and an insertion of identical elements as a pair of Move edits
+// instead, e.g., when two lines swap their order.
+//
+// This is a heuristic: Only identical elements are detected as moves and if an element is deleted
+// or inserted multiple times, deletions and insertions are paired in order of appearance.
+func DetectSwaps() Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.DetectSwaps = true
+		return config.DetectSwaps
+	}
+}
+
+// DetectModifiedMoves reports a run of deletions and a run of insertions elsewhere as a moved and
+// modified block if they are similar enough, e.g., when a function is moved and one of its lines
+// is changed. All edits of such a block use the MoveModify op and share a [MovedBlock] that
+// contains the edits within the block.
+//
+// The similarity of two runs is 2*M/(N+K), where M is the number of matching elements and N and K
+// are the lengths of the runs. Runs are only paired if their similarity is at least threshold,
+// which must be in the range (0, 1]. A run of deletions is never paired with the run of insertions
+// that directly follows it, because that's a modification in place and not a move.
+//
+// If used together with [DetectSwaps], moved elements are detected first and the remaining runs
+// are compared afterwards.
+//
+// Performance impact: Every run of insertions is compared with every run of deletions of a similar
+// length using a secondary diff. To bound the cost for inputs with many changes, at most 10000
+// pairs of runs are compared. Runs that are left afterwards are reported as regular deletions and
+// insertions.
+func DetectModifiedMoves(threshold float64) Option {
+	if threshold <= 0 || threshold > 1 {
+		panic(fmt.Sprintf("diff.DetectModifiedMoves: threshold %v is not in the range (0, 1]", threshold))
+	}
+	return func(cfg *config.Config) config.Flag {
+		cfg.ModifiedMovesThreshold = threshold
+		return config.DetectModifiedMoves
+	}
+}
+
 // Minimal ensures the diff algorithm finds the shortest possible diff by disabling performance
 // heuristics.
 //
@@ -39,6 +123,10 @@ func Context(n int) Option {
 // changes, which may produce slightly longer diffs. Use this option when you need the absolute
 // shortest diff, at the cost of potentially slower performance.
 //
+// Diffs only consist of insertions and deletions. A minimal diff therefore also minimizes any
+// weighted cost with positive weights for insertions and deletions: Every diff with the maximum
+// number of matches has the lowest cost, independent of the weights.
+//
 // Performance impact: Changes time complexity from O(N^1.5 log N) to O(ND) where N = len(x) +
 // len(y) and D is the number of differences.
 func Minimal() Option {
@@ -48,6 +136,131 @@ func Minimal() Option {
 	}
 }
 
+// MinimalWithBudget is like [Minimal], but only spends about d on finding the shortest possible
+// diff. If the budget is exhausted, the rest of the inputs are compared with the heuristics of the
+// default mode instead. The result is always a valid diff, but it's only guaranteed to be minimal
+// if the budget wasn't exhausted. Values < 1 are treated as 1ns.
+//
+// The budget only covers the diff algorithm itself, not the preprocessing of the inputs, and it's
+// checked periodically: A diff can therefore take longer than d. MinimalWithBudget is supported by
+// every function that supports [Minimal], [Stable] takes precedence over it.
+//
+// Performance impact: Same as [Minimal] until the budget is exhausted and same as the default
+// afterwards. Checking the time adds a minor overhead.
+func MinimalWithBudget(d time.Duration) Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.Mode = config.ModeMinimal
+		cfg.MinimalBudget = max(1, d)
+		return config.Minimal
+	}
+}
+
+// PreferLongMatches makes the diff algorithm prefer long runs of matches: If there are several
+// equally good ways to split the inputs, the one with the longest run of matching elements in the
+// middle is used. This tends to produce fewer, larger hunks, which is often easier to read, e.g.,
+// for code.
+//
+// PreferLongMatches doesn't change the number of edits, only which of several equally short diffs
+// is returned. It applies to [Minimal] and the default mode, but not to [Fast].
+func PreferLongMatches() Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.PreferLongMatches = true
+		return config.PreferLongMatches
+	}
+}
+
+// Stable computes a minimal diff (see [Minimal]) and guarantees that the output is stable: The
+// same inputs and options produce the same output across all versions with the same major version
+// of this module. This is useful for golden file tests and for storing diffs.
+//
+// Stable takes precedence over [Fast] and [AutoFast] and is supported by every function that
+// supports [Minimal]. The guarantee only covers which elements are reported as matches,
+// deletions, and insertions. Presentation options like the indentation heuristic in the textdiff
+// package can still change with minor version upgrades.
+//
+// Performance impact: Same as [Minimal].
+func Stable() Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.Mode = config.ModeMinimal
+		cfg.Stable = true
+		return config.Minimal
+	}
+}
+
+// CostLimit sets the cost limit of the heuristic that limits the runtime of the default mode for
+// inputs with many differences. If finding the optimal path between two points in the inputs costs
+// more than d, the heuristic settles for a good-enough path instead. Values < 1 are treated as 1.
+//
+// By default, the limit is derived from the input size, but it's at least 4096. A higher limit
+// trades runtime for shorter diffs, a very large limit effectively results in a [Minimal] diff. A
+// lower limit does the opposite. The limit has no effect on [Minimal] and [Fast].
+func CostLimit(d int) Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.CostLimit = max(1, d)
+		return config.CostLimit
+	}
+}
+
+// GoodDiagonalTuning sets the parameters of the heuristic that makes the default mode settle for a
+// long diagonal (a run of matching elements) when finding the optimal path gets expensive:
+//
+//   - minLen is the minimal length of a diagonal to be considered (default 20),
+//   - costLimit is the cost above which the heuristic is applied (default 256), and
+//   - magic is the factor that determines how far along the inputs a diagonal has to be relative
+//     to the cost to be selected (default 4).
+//
+// Values < 1 are treated as 1. Lower values make the heuristic kick in earlier, trading shorter
+// diffs for runtime. The parameters have no effect on [Minimal] and [Fast].
+func GoodDiagonalTuning(minLen, costLimit, magic int) Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.GoodDiagMinLen = max(1, minLen)
+		cfg.GoodDiagCostLimit = max(1, costLimit)
+		cfg.GoodDiagMagic = max(1, magic)
+		return config.GoodDiagonalTuning
+	}
+}
+
+// Parallel compares the parts of the inputs between anchors on up to n goroutines. Values < 1 are
+// treated as 1, i.e., sequential comparison, which is the default.
+//
+// The anchors are elements that appear exactly once in both inputs and are matched, the same
+// anchors that [Fast] uses. They are only used for large inputs; small inputs and inputs without
+// anchors are always compared sequentially. The result is the same as without Parallel. Parallel
+// has no effect on [Fast], [Stable], and [Minimal].
+func Parallel(n int) Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.Parallel = max(1, n)
+		return config.Parallel
+	}
+}
+
+// FloatTolerance makes [EditsFloat] treat two values as equal if their absolute difference is at
+// most eps. Values < 0 are treated as 0, i.e., exact comparison.
+//
+// Performance impact: With a tolerance, the comparison can't use the optimizations for comparable
+// types and has the same performance characteristics as [EditsFunc]. [Fast] and [AutoFast] have no
+// effect then.
+func FloatTolerance(eps float64) Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.FloatTolerance = max(0, eps)
+		return config.FloatTolerance
+	}
+}
+
+// MaxWork limits the work [EditsFuncChecked] spends on comparing x and y to roughly ops steps of
+// the diff algorithm. Once the limit is exceeded, the comparison stops and all elements that
+// weren't compared yet are reported as deleted or inserted. Values < 1 are treated as 1.
+//
+// Unlike [Fast] or [CostLimit], this isn't a heuristic to trade diff quality for speed, it's a hard
+// limit that bounds the runtime for untrusted inputs. Each step costs O(D) time, where D is the
+// number of differences found so far.
+func MaxWork(ops int) Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.MaxWork = max(1, ops)
+		return config.MaxWork
+	}
+}
+
 // Fast uses a heuristic to find a reasonable diff instead of trying to find a minimal diff.
 //
 // This option trades diff minimality for runtime performance. The resulting diff can be a lot
@@ -64,3 +277,20 @@ func Fast() Option {
 		return config.Fast
 	}
 }
+
+// AutoFast switches to [Fast] for inputs that are too large to diff with the configured mode.
+//
+// The switch happens if len(x)*len(y) > maxProduct after removing the common prefix and suffix of
+// both inputs, that is, only the part of the inputs that actually differs is taken into account.
+// This bounds the latency for large inputs without the need to compute their sizes up front. The
+// tradeoff is the same as for [Fast]: The resulting diff can be a lot larger than the one created
+// by the configured mode.
+//
+// The heuristic only works for comparable types.
+func AutoFast(maxProduct int) Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.AutoFast = true
+		cfg.AutoFastMaxProduct = max(0, maxProduct)
+		return config.AutoFast
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build experimental
+
+package diff
+
+import "znkr.io/diff/internal/config"
+
+// DeterministicSplit disables all heuristics and computes a minimal diff using the optimal split
+// of the diff algorithm, the same code path that the fuzz tests of this module exercise. This takes
+// precedence over all options that select or tune heuristics: [Fast], [AutoFast], [Parallel],
+// [PreferLongMatches], [CostLimit], and [GoodDiagonalTuning] have no effect. Limits like [MaxWork]
+// still apply.
+//
+// This is useful for fuzzers and differential testing against other diff implementations. It's
+// supported by every function that supports [Minimal].
+//
+// Experimental: This option is only available with the "experimental" build tag and may change or
+// be removed in any version.
+//
+// Performance impact: Same as [Minimal].
+func DeterministicSplit() Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.DeterministicSplit = true
+		return config.DeterministicSplit
+	}
+}
+
+// AnchorMaxCount relaxes the definition of anchors used by [Fast] and by the heuristics of the
+// default mode: By default, an anchor is an element that appears exactly once in x and in y. With
+// AnchorMaxCount(k), an element is an anchor if it appears equally often in x and y, at most k
+// times. The i-th occurrence in x is paired with the i-th occurrence in y. Values < 1 are treated
+// as 1, which is the default.
+//
+// More anchors can improve the alignment of repetitive inputs, e.g. inputs where every line appears
+// twice, because there are too few unique elements to anchor the diff otherwise. On the other hand,
+// a repeated element is more likely to be paired with the wrong occurrence, which can produce
+// worse diffs for other inputs. It has no effect on [Minimal] diffs. It's supported by every
+// function that supports [Fast].
+//
+// Experimental: This option is only available with the "experimental" build tag and may change or
+// be removed in any version.
+//
+// Performance impact: Counting the occurrences requires an additional pass over the inputs. More
+// anchors make the longest common subsequence of anchors more expensive to compute, but also
+// split the inputs into more, smaller ranges.
+func AnchorMaxCount(k int) Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.AnchorMaxCount = max(1, k)
+		return config.AnchorMaxCount
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build experimental
+
+package diff
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"znkr.io/diff/internal/config"
+)
+
+func TestDeterministicSplit(t *testing.T) {
+	x, y := spec{500, 500, 200}.generate([]byte{})
+	want := Edits(x, y, Minimal())
+	for _, opts := range [][]Option{
+		nil,
+		{Fast()},
+		{AutoFast(1)},
+		{Parallel(4)},
+		{PreferLongMatches()},
+		{CostLimit(1)},
+		{GoodDiagonalTuning(4, 8, 1)},
+	} {
+		// DeterministicSplit takes precedence, independently of the order of the options.
+		for _, opts := range [][]Option{slices.Concat([]Option{DeterministicSplit()}, opts), slices.Concat(opts, []Option{DeterministicSplit()})} {
+			got := Edits(x, y, opts...)
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Edits(...) with %d options is different from Edits(..., Minimal()) (-want, +got):\n%s", len(opts), diff)
+			}
+			if _, trace := HunksWithTrace(x, y, opts...); trace.HeuristicFired {
+				t.Errorf("HunksWithTrace(...) with %d options fired a heuristic: %+v", len(opts), trace)
+			}
+		}
+	}
+}
+
+func TestAnchorMaxCount(t *testing.T) {
+	// Every common element appears twice, there are no unique anchors.
+	x := strings.Fields("a b c x a b c")
+	y := strings.Fields("b c a y b c a")
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "k=1",
+			opts: []Option{Fast(), AnchorMaxCount(1)},
+			want: "-a\n-b\n-c\n-x\n-a\n-b\n-c\n+b\n+c\n+a\n+y\n+b\n+c\n+a\n",
+		},
+		{
+			name: "k=2",
+			opts: []Option{Fast(), AnchorMaxCount(2)},
+			want: "-a\n b\n c\n-x\n a\n+y\n b\n c\n+a\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			for _, e := range Edits(x, y, tt.opts...) {
+				b.WriteString(e.String())
+				b.WriteString("\n")
+			}
+			if diff := cmp.Diff(tt.want, b.String()); diff != "" {
+				t.Errorf("Edits(...) result is different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAnchorMaxCountValid(t *testing.T) {
+	// Relaxed anchors must always produce a valid diff, also for inputs with many repeated elements
+	// that appear a different number of times in x and y.
+	rng := rand.New(rand.NewPCG(1, 2))
+	for _, n := range []int{100, 1000, 6000} {
+		x, y := make([]int, n), make([]int, n)
+		for i := range n {
+			x[i], y[i] = rng.IntN(n/2), rng.IntN(n/2)
+		}
+		for _, opts := range [][]Option{
+			nil,
+			{Fast()},
+		} {
+			for _, k := range []int{2, 3, 10} {
+				edits := Edits(x, y, append(opts, AnchorMaxCount(k))...)
+				got, err := ApplyEdits(x, edits)
+				if err != nil || !slices.Equal(got, y) {
+					t.Errorf("n=%d: ApplyEdits(x, Edits(..., AnchorMaxCount(%d))) doesn't reproduce y, err = %v", n, k, err)
+				}
+			}
+		}
+	}
+}
+
+func TestExperimentalOptionNotAllowed(t *testing.T) {
+	// A rejected option must be reported by its own name.
+	for _, tt := range []struct {
+		name string
+		opt  Option
+		want string
+	}{
+		{"deterministic-split", DeterministicSplit(), "Option diff.DeterministicSplit not allowed here"},
+		{"anchor-max-count", AnchorMaxCount(2), "Option diff.AnchorMaxCount not allowed here"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if got := recover(); got != tt.want {
+					t.Errorf("FromOptions(...) panicked with %v, want %q", got, tt.want)
+				}
+			}()
+			config.FromOptions([]Option{tt.opt}, config.Context)
+		})
+	}
+}
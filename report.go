@@ -0,0 +1,78 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Report compares the contents of x and y and returns a human readable report of the differences
+// with one line per element. Every element is formatted using [fmt.Sprint] and prefixed with " "
+// for a match, "-" for a deletion, and "+" for an insertion. If x and y are identical, the report
+// is empty.
+//
+// Report is intended for test failure messages, e.g.
+//
+//	if r := diff.Report(want, got); r != "" {
+//		t.Errorf("result is different (-want, +got):\n%s", r)
+//	}
+//
+// The following options are supported: [Minimal], [PreferLongMatches], [CostLimit],
+// [GoodDiagonalTuning], [Fast], [AutoFast], [Parallel]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func Report[T comparable](x, y []T, opts ...Option) string {
+	return report(Edits(x, y, opts...), func(v T) string { return fmt.Sprint(v) })
+}
+
+// ReportFunc is like [Report] but uses the provided equality comparison to compare elements and
+// the provided format function to format them.
+//
+// The following options are supported: [Minimal], [PreferLongMatches], [CostLimit],
+// [GoodDiagonalTuning]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func ReportFunc[T any](x, y []T, eq func(a, b T) bool, format func(T) string, opts ...Option) string {
+	return report(EditsFunc(x, y, eq, opts...), format)
+}
+
+func report[T any](edits []Edit[T], format func(T) string) string {
+	if !hasChanges(edits) {
+		return ""
+	}
+	var b strings.Builder
+	for _, edit := range edits {
+		b.WriteString(edit.Op.Prefix())
+		if edit.PosX < 0 {
+			b.WriteString(format(edit.Y))
+		} else {
+			b.WriteString(format(edit.X))
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func hasChanges[T any](edits []Edit[T]) bool {
+	for _, edit := range edits {
+		if edit.Op.IsChange() {
+			return true
+		}
+	}
+	return false
+}
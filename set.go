@@ -0,0 +1,83 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import (
+	"cmp"
+
+	"znkr.io/diff/internal/impl"
+)
+
+// SetDiff compares x and y as multisets, i.e., ignoring the order of elements. It returns the
+// elements that only occur in x, the elements that only occur in y, and the elements that occur in
+// both.
+//
+// Duplicates are respected: If an element occurs n times in x and m times in y, it's included
+// min(n, m) times in common and the remaining occurrences are included in onlyX or onlyY. The
+// first occurrences are the common ones. All results are in the order of appearance in the input,
+// common in the order of x.
+//
+// In contrast to the other functions in this package, SetDiff doesn't compute a diff of sequences.
+// It's useful to compare inputs where the order is irrelevant, e.g., the output of a command that
+// prints lines in random order. Runtime and memory are linear in len(x) + len(y).
+func SetDiff[T comparable](x, y []T) (onlyX, onlyY, common []T) {
+	return impl.SetDiff(x, y, func(v T) T { return v })
+}
+
+// SortedEdits compares x and y, which must both be sorted in ascending order, and returns the
+// changes necessary to convert from one to the other.
+//
+// Instead of running a diff algorithm, SortedEdits merges both inputs in a single linear pass. For
+// sorted inputs, the result is a minimal diff, i.e., it has the same number of matches as [Edits]
+// would find. Runtime is linear in len(x) + len(y) and no memory besides the output is allocated.
+// Like [Edits], SortedEdits returns one edit for every element in the input slices.
+//
+// Elements are compared using [cmp.Compare]. In contrast to [Edits], this means that NaN is equal to
+// NaN and sorted before all other floating point numbers.
+//
+// The inputs are not checked for sortedness. If they aren't sorted, the result is still a valid
+// sequence of edits that converts x to y, but it's not necessarily minimal.
+func SortedEdits[T cmp.Ordered](x, y []T) []Edit[T] {
+	return SortedEditsFunc(x, y, cmp.Compare[T])
+}
+
+// SortedEditsFunc is like [SortedEdits], but uses the provided comparison function. The inputs must
+// be sorted according to cmp, which returns a negative number when a < b, a positive number when
+// a > b, and zero when a and b are equal.
+func SortedEditsFunc[T any](x, y []T, cmp func(a, b T) int) []Edit[T] {
+	eout := make([]Edit[T], 0, max(len(x), len(y)))
+	s, t := 0, 0
+	for s < len(x) && t < len(y) {
+		switch c := cmp(x[s], y[t]); {
+		case c < 0:
+			eout = append(eout, Edit[T]{Op: Delete, PosX: s, PosY: -1, X: x[s]})
+			s++
+		case c > 0:
+			eout = append(eout, Edit[T]{Op: Insert, PosX: -1, PosY: t, Y: y[t]})
+			t++
+		default:
+			eout = append(eout, Edit[T]{Op: Match, PosX: s, PosY: t, X: x[s], Y: y[t]})
+			s++
+			t++
+		}
+	}
+	for ; s < len(x); s++ {
+		eout = append(eout, Edit[T]{Op: Delete, PosX: s, PosY: -1, X: x[s]})
+	}
+	for ; t < len(y); t++ {
+		eout = append(eout, Edit[T]{Op: Insert, PosX: -1, PosY: t, Y: y[t]})
+	}
+	return eout
+}
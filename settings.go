@@ -0,0 +1,120 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diff
+
+import "time"
+
+// Settings is a declarative alternative to the variadic options, e.g., for configurations that are
+// read from flags or a configuration file. Every field corresponds to an [Option], the zero value
+// of a field means that the option isn't set. The zero value of Settings therefore corresponds to
+// using no options at all.
+//
+// Like options, settings are only supported by some functions. Functions that take Settings panic
+// if a setting is used that the function doesn't support, e.g., [EditsWith] panics if Context is
+// set.
+type Settings struct {
+	Context           *int          // See [Context], a pointer to distinguish 0 from unset.
+	MaxHunks          int           // See [MaxHunks].
+	Mode              Mode          // See [Minimal] and [Fast].
+	MinimalBudget     time.Duration // See [MinimalWithBudget], only used with ModeMinimal.
+	Stable            bool          // See [Stable], takes precedence over Mode.
+	PreferLongMatches bool          // See [PreferLongMatches].
+	PairedOrdering    bool          // See [PairedOrdering].
+	CostLimit         int           // See [CostLimit].
+	Parallel          int           // See [Parallel].
+	AutoFast          int           // See [AutoFast], the maximal product of the input lengths.
+	MaxWork           int           // See [MaxWork].
+	FloatTolerance    float64       // See [FloatTolerance].
+
+	// See [GoodDiagonalTuning], only used if at least one of the parameters is set.
+	GoodDiagonalMinLen, GoodDiagonalCostLimit, GoodDiagonalMagic int
+
+	DetectSwaps         bool    // See [DetectSwaps].
+	DetectModifiedMoves float64 // See [DetectModifiedMoves], the similarity threshold.
+}
+
+// Options returns the options that correspond to s. Like the option functions, Options panics if a
+// value is out of range, e.g., a DetectModifiedMoves threshold > 1.
+func (s Settings) Options() []Option {
+	var opts []Option
+	if s.Context != nil {
+		opts = append(opts, Context(*s.Context))
+	}
+	if s.MaxHunks > 0 {
+		opts = append(opts, MaxHunks(s.MaxHunks))
+	}
+	switch s.Mode {
+	case ModeMinimal:
+		if s.MinimalBudget > 0 {
+			opts = append(opts, MinimalWithBudget(s.MinimalBudget))
+		} else {
+			opts = append(opts, Minimal())
+		}
+	case ModeFast:
+		opts = append(opts, Fast())
+	}
+	if s.Stable {
+		opts = append(opts, Stable())
+	}
+	if s.PreferLongMatches {
+		opts = append(opts, PreferLongMatches())
+	}
+	if s.PairedOrdering {
+		opts = append(opts, PairedOrdering())
+	}
+	if s.CostLimit > 0 {
+		opts = append(opts, CostLimit(s.CostLimit))
+	}
+	if s.Parallel > 0 {
+		opts = append(opts, Parallel(s.Parallel))
+	}
+	if s.AutoFast > 0 {
+		opts = append(opts, AutoFast(s.AutoFast))
+	}
+	if s.MaxWork > 0 {
+		opts = append(opts, MaxWork(s.MaxWork))
+	}
+	if s.FloatTolerance > 0 {
+		opts = append(opts, FloatTolerance(s.FloatTolerance))
+	}
+	if s.GoodDiagonalMinLen > 0 || s.GoodDiagonalCostLimit > 0 || s.GoodDiagonalMagic > 0 {
+		opts = append(opts, GoodDiagonalTuning(s.GoodDiagonalMinLen, s.GoodDiagonalCostLimit, s.GoodDiagonalMagic))
+	}
+	if s.DetectSwaps {
+		opts = append(opts, DetectSwaps())
+	}
+	if s.DetectModifiedMoves != 0 {
+		opts = append(opts, DetectModifiedMoves(s.DetectModifiedMoves))
+	}
+	return opts
+}
+
+// HunksWith is like [Hunks], but takes its configuration from s. It panics if s contains settings
+// that [Hunks] doesn't support, like DetectSwaps.
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func HunksWith[T comparable](x, y []T, s Settings) []Hunk[T] {
+	return Hunks(x, y, s.Options()...)
+}
+
+// EditsWith is like [Edits], but takes its configuration from s. It panics if s contains settings
+// that [Edits] doesn't support, like Context.
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func EditsWith[T comparable](x, y []T, s Settings) []Edit[T] {
+	return Edits(x, y, s.Options()...)
+}
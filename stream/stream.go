@@ -0,0 +1,70 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package stream compares sequences of records provided as iterators, e.g. records decoded from
+// a JSON lines or protobuf stream.
+//
+// All functions in this package materialize both sequences in memory before comparing them. The
+// diff algorithm needs random access to all records, so there is no way to compare sequences
+// incrementally. For very large streams, the memory requirements are the same as for the slices
+// of all records.
+//
+// To compare records from an [io.Reader], wrap the decoder in an iterator, e.g.:
+//
+//	func records(r io.Reader) iter.Seq[Record] {
+//		return func(yield func(Record) bool) {
+//			dec := json.NewDecoder(r)
+//			for {
+//				var rec Record
+//				if err := dec.Decode(&rec); err != nil {
+//					return // handle errors as appropriate
+//				}
+//				if !yield(rec) {
+//					return
+//				}
+//			}
+//		}
+//	}
+package stream
+
+import (
+	"iter"
+	"slices"
+
+	"znkr.io/diff"
+)
+
+// Records compares the records in x and y and returns the changes necessary to convert from one
+// to the other. It's a shorthand for calling [diff.Edits] with the collected records.
+//
+// The following options are supported: [diff.Minimal], [diff.PreferLongMatches], [diff.CostLimit],
+// [diff.Fast], [diff.AutoFast], [diff.Parallel], [diff.DetectSwaps], [diff.DetectModifiedMoves]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func Records[T comparable](x, y iter.Seq[T], opts ...diff.Option) []diff.Edit[T] {
+	return diff.Edits(slices.Collect(x), slices.Collect(y), opts...)
+}
+
+// RecordsFunc compares the records in x and y using the provided equality comparison and returns
+// the changes necessary to convert from one to the other. It's a shorthand for calling
+// [diff.EditsFunc] with the collected records.
+//
+// The following options are supported: [diff.Minimal], [diff.PreferLongMatches], [diff.CostLimit]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func RecordsFunc[T any](x, y iter.Seq[T], eq func(a, b T) bool, opts ...diff.Option) []diff.Edit[T] {
+	return diff.EditsFunc(slices.Collect(x), slices.Collect(y), eq, opts...)
+}
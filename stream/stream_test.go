@@ -0,0 +1,61 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stream
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"znkr.io/diff"
+)
+
+type record struct {
+	ID   int
+	Name string
+}
+
+func TestRecords(t *testing.T) {
+	x := []record{{1, "alpha"}, {2, "beta"}, {3, "gamma"}}
+	y := []record{{1, "alpha"}, {3, "gamma"}, {4, "delta"}}
+	want := []diff.Edit[record]{
+		{Op: diff.Match, X: record{1, "alpha"}, Y: record{1, "alpha"}, PosX: 0, PosY: 0},
+		{Op: diff.Delete, X: record{2, "beta"}, PosX: 1, PosY: -1},
+		{Op: diff.Match, X: record{3, "gamma"}, Y: record{3, "gamma"}, PosX: 2, PosY: 1},
+		{Op: diff.Insert, Y: record{4, "delta"}, PosX: -1, PosY: 2},
+	}
+
+	got := Records(slices.Values(x), slices.Values(y))
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Records(...) result is different (-want, +got):\n%s", diff)
+	}
+
+	// Compare names case-insensitively.
+	y[0].Name = "ALPHA"
+	eq := func(a, b record) bool { return a.ID == b.ID && strings.EqualFold(a.Name, b.Name) }
+	got = RecordsFunc(slices.Values(x), slices.Values(y), eq)
+	want[0].Y = y[0]
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("RecordsFunc(...) result is different (-want, +got):\n%s", diff)
+	}
+}
+
+func TestRecordsEmpty(t *testing.T) {
+	empty := slices.Values([]record(nil))
+	if got := Records(empty, empty); len(got) != 0 {
+		t.Errorf("Records(empty, empty) = %v, want empty", got)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"slices"
+	"sort"
+
+	"znkr.io/diff/internal/byteview"
+	"znkr.io/diff/internal/config"
+	"znkr.io/diff/internal/impl"
+	"znkr.io/diff/internal/rvecs"
+)
+
+// diffAnchored compares xkeys and ykeys like impl.Diff, but uses the lines selected by cfg.Anchor
+// as fixed matches, see [AnchorOn].
+func diffAnchored(x, y, xkeys, ykeys []byteview.ByteView, cfg config.Config) (rx, ry []bool) {
+	rx, ry = rvecs.Make(x, y)
+	s, t := 0, 0
+	for _, a := range anchors(x, y, xkeys, ykeys, cfg.Anchor) {
+		rxs, rys := impl.Diff(xkeys[s:a.s], ykeys[t:a.t], cfg)
+		copy(rx[s:a.s], rxs)
+		copy(ry[t:a.t], rys)
+		s, t = a.s+1, a.t+1
+	}
+	rxs, rys := impl.Diff(xkeys[s:], ykeys[t:], cfg)
+	copy(rx[s:], rxs)
+	copy(ry[t:], rys)
+	return rx, ry
+}
+
+type anchor struct{ s, t int }
+
+// anchors returns the positions of lines selected by pred that are unique in both inputs. If the
+// anchors appear in a different order in x and y, the longest sequence of anchors that appears in
+// the same order in both inputs is used.
+func anchors(x, y, xkeys, ykeys []byteview.ByteView, pred func(line string) bool) []anchor {
+	type candidate struct {
+		nx, ny int // Number of occurrences in x and y.
+		s, t   int // Position of the last occurrence in x and y.
+	}
+	candidates := make(map[byteview.ByteView]*candidate)
+	for s, line := range x {
+		if pred(byteview.UnsafeAs[string](line)) {
+			candidates[xkeys[s]] = &candidate{}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+	for s, key := range xkeys {
+		if c, ok := candidates[key]; ok {
+			c.nx++
+			c.s = s
+		}
+	}
+	for t, key := range ykeys {
+		if c, ok := candidates[key]; ok {
+			c.ny++
+			c.t = t
+		}
+	}
+
+	var all []anchor
+	for _, c := range candidates {
+		if c.nx == 1 && c.ny == 1 && pred(byteview.UnsafeAs[string](y[c.t])) {
+			all = append(all, anchor{c.s, c.t})
+		}
+	}
+	slices.SortFunc(all, func(a, b anchor) int { return a.s - b.s })
+	return increasing(all)
+}
+
+// increasing returns the longest subsequence of anchors (sorted by s) that is also sorted by t.
+func increasing(anchors []anchor) []anchor {
+	// Patience sorting: tails[k] is the index of the anchor with the smallest t that ends an
+	// increasing subsequence of length k+1, prev links every anchor to its predecessor.
+	var tails []int
+	prev := make([]int, len(anchors))
+	for i, a := range anchors {
+		k := sort.Search(len(tails), func(k int) bool { return anchors[tails[k]].t > a.t })
+		prev[i] = -1
+		if k > 0 {
+			prev[i] = tails[k-1]
+		}
+		if k == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[k] = i
+		}
+	}
+	if len(tails) == 0 {
+		return nil
+	}
+	out := make([]anchor, len(tails))
+	for i, k := len(out)-1, tails[len(tails)-1]; i >= 0; i, k = i-1, prev[k] {
+		out[i] = anchors[k]
+	}
+	return out
+}
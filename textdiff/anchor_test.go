@@ -0,0 +1,75 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"znkr.io/diff/internal/byteview"
+)
+
+func TestAnchors(t *testing.T) {
+	isAnchor := func(line string) bool { return strings.HasPrefix(line, "#") }
+	tests := []struct {
+		name string
+		x, y string
+		want []anchor
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "no-anchors",
+			x:    "a\nb\n",
+			y:    "a\nb\n",
+		},
+		{
+			name: "unique",
+			x:    "#1\na\n#2\nb\n",
+			y:    "c\n#1\n#2\n",
+			want: []anchor{{0, 1}, {2, 2}},
+		},
+		{
+			name: "only-in-one-input",
+			x:    "#1\na\n#2\n",
+			y:    "#1\na\n",
+			want: []anchor{{0, 0}},
+		},
+		{
+			name: "duplicate",
+			x:    "#1\n#2\n#1\n",
+			y:    "#1\n#2\n",
+			want: []anchor{{1, 1}},
+		},
+		{
+			name: "reordered",
+			x:    "#1\n#2\n#3\n#4\n",
+			y:    "#4\n#1\n#2\n#3\n",
+			want: []anchor{{0, 1}, {1, 2}, {2, 3}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, _ := byteview.SplitLines(byteview.From(tt.x))
+			y, _ := byteview.SplitLines(byteview.From(tt.y))
+			got := anchors(x, y, x, y, isAnchor)
+			if diff := cmp.Diff(tt.want, got, cmp.AllowUnexported(anchor{})); diff != "" {
+				t.Errorf("anchors(...) result is different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"strings"
+
+	"znkr.io/diff/internal/byteview"
+	"znkr.io/diff/internal/config"
+)
+
+// binaryDetectionLen is the number of bytes at the start of an input that are searched for a NUL
+// byte to detect binary inputs, see [BinaryDetection]. This is the same limit git uses.
+const binaryDetectionLen = 8000
+
+// isBinary reports whether s looks like a binary input.
+func isBinary(s string) bool {
+	return strings.IndexByte(s[:min(len(s), binaryDetectionLen)], 0) >= 0
+}
+
+// binaryInputs reports whether x and y must be compared as binary inputs, see [BinaryDetection].
+func binaryInputs[T string | []byte](x, y T, cfg *config.Config) bool {
+	if !cfg.BinaryDetection {
+		return false
+	}
+	return isBinary(byteview.UnsafeAs[string](byteview.From(x))) || isBinary(byteview.UnsafeAs[string](byteview.From(y)))
+}
+
+// binaryHunks returns the hunks for binary inputs: A single hunk that covers both inputs if they
+// differ and no hunk otherwise.
+func binaryHunks[T string | []byte](x, y T) []Hunk[T] {
+	sx := byteview.UnsafeAs[string](byteview.From(x))
+	sy := byteview.UnsafeAs[string](byteview.From(y))
+	if sx == sy {
+		return nil
+	}
+	return []Hunk[T]{{
+		EndLineNoX:  numLines(sx),
+		EndLineNoY:  numLines(sy),
+		AtFileStart: true,
+		AtFileEnd:   true,
+		Binary:      true,
+	}}
+}
+
+// numLines returns the number of lines in s, including an incomplete last line.
+func numLines(s string) int {
+	n := strings.Count(s, "\n")
+	if len(s) > 0 && s[len(s)-1] != '\n' {
+		n++
+	}
+	return n
+}
+
+// binaryUnified returns the output of [Unified] for binary inputs. Like git, it only reports
+// whether the inputs differ.
+func binaryUnified[T string | []byte](x, y T, cfg *config.Config) T {
+	var b byteview.Builder[T]
+	if len(binaryHunks(x, y)) > 0 {
+		b.WriteString(binaryMessage(cfg))
+	}
+	return b.Build()
+}
+
+// binaryMessage returns the message for binary inputs that differ, preceded by the index line of
+// the file header (see [IndexHeader]), if any.
+func binaryMessage(cfg *config.Config) string {
+	nl := "\n"
+	if cfg.OutputNewline != "" {
+		nl = cfg.OutputNewline
+	}
+	if cfg.LabelX != "" || cfg.LabelY != "" {
+		return indexLine(cfg, nl) + "Binary files " + cfg.LabelX + " and " + cfg.LabelY + " differ" + nl
+	}
+	return indexLine(cfg, nl) + "Binary files differ" + nl
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"znkr.io/diff"
+)
+
+func TestBinaryDetection(t *testing.T) {
+	tests := []struct {
+		name  string
+		x, y  string
+		opts  []diff.Option
+		want  string
+		hunks []Hunk[string]
+	}{
+		{
+			name:  "binary",
+			x:     "a\nb\x00c\n",
+			y:     "a\nb\x00d\nx",
+			want:  "Binary files differ\n",
+			hunks: []Hunk[string]{{EndLineNoX: 2, EndLineNoY: 3, AtFileStart: true, AtFileEnd: true, Binary: true}},
+		},
+		{
+			name:  "binary-y",
+			x:     "text\n",
+			y:     "\x00",
+			want:  "Binary files differ\n",
+			hunks: []Hunk[string]{{EndLineNoX: 1, EndLineNoY: 1, AtFileStart: true, AtFileEnd: true, Binary: true}},
+		},
+		{
+			name:  "binary-labels",
+			x:     "\x00\x01",
+			y:     "\x00\x02",
+			opts:  []diff.Option{Labels("a/file.bin", "b/file.bin"), IndexHeader("1234567", "89abcde", "100644")},
+			want:  "index 1234567..89abcde 100644\nBinary files a/file.bin and b/file.bin differ\n",
+			hunks: []Hunk[string]{{EndLineNoX: 1, EndLineNoY: 1, AtFileStart: true, AtFileEnd: true, Binary: true}},
+		},
+		{
+			name: "binary-equal",
+			x:    "a\x00b\n",
+			y:    "a\x00b\n",
+		},
+		{
+			name: "text",
+			x:    "caf\xc3\xa9\n\xff\xfe\n",
+			y:    "caf\xc3\xa9\n\xff\xfd\n",
+			want: "@@ -1,2 +1,2 @@\n caf\xc3\xa9\n-\xff\xfe\n+\xff\xfd\n",
+			hunks: []Hunk[string]{{
+				EndLineNoX:  2,
+				EndLineNoY:  2,
+				AtFileStart: true,
+				AtFileEnd:   true,
+				Edits: []Edit[string]{
+					{Op: diff.Match, LineNoX: 0, LineNoY: 0, Line: "caf\xc3\xa9\n"},
+					{Op: diff.Delete, LineNoX: 1, LineNoY: -1, Line: "\xff\xfe\n"},
+					{Op: diff.Insert, LineNoX: -1, LineNoY: 1, Line: "\xff\xfd\n"},
+				},
+			}},
+		},
+		{
+			name: "nul-after-threshold",
+			x:    strings.Repeat("a", binaryDetectionLen) + "\n\x00\n",
+			y:    strings.Repeat("a", binaryDetectionLen) + "\n\x01\n",
+			want: "@@ -1,2 +1,2 @@\n " + strings.Repeat("a", binaryDetectionLen) + "\n-\x00\n+\x01\n",
+			hunks: []Hunk[string]{{
+				EndLineNoX:  2,
+				EndLineNoY:  2,
+				AtFileStart: true,
+				AtFileEnd:   true,
+				Edits: []Edit[string]{
+					{Op: diff.Match, LineNoX: 0, LineNoY: 0, Line: strings.Repeat("a", binaryDetectionLen) + "\n"},
+					{Op: diff.Delete, LineNoX: 1, LineNoY: -1, Line: "\x00\n"},
+					{Op: diff.Insert, LineNoX: -1, LineNoY: 1, Line: "\x01\n"},
+				},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]diff.Option{BinaryDetection()}, tt.opts...)
+			if got := Unified(tt.x, tt.y, opts...); got != tt.want {
+				t.Errorf("Unified(...) = %q, want %q", got, tt.want)
+			}
+			if got := string(Unified([]byte(tt.x), []byte(tt.y), opts...)); got != tt.want {
+				t.Errorf("Unified([]byte(...), ...) = %q, want %q", got, tt.want)
+			}
+			var b strings.Builder
+			if err := WriteUnified(&b, tt.x, tt.y, opts...); err != nil || b.String() != tt.want {
+				t.Errorf("WriteUnified(...) = %q, %v, want %q, nil", b.String(), err, tt.want)
+			}
+			if tt.opts != nil {
+				// Hunks doesn't support the file header options.
+				return
+			}
+			if diff := cmp.Diff(tt.hunks, Hunks(tt.x, tt.y, opts...)); diff != "" {
+				t.Errorf("Hunks(...) result is different (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.hunks, slices.Collect(HunksSeq(tt.x, tt.y, opts...))); diff != "" {
+				t.Errorf("HunksSeq(...) result is different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBinaryDetectionDisabled(t *testing.T) {
+	// Without the option, binary inputs are compared line by line.
+	got := Unified("a\nb\x00c\n", "a\nb\x00d\n")
+	want := "@@ -1,2 +1,2 @@\n a\n-b\x00c\n+b\x00d\n"
+	if got != want {
+		t.Errorf("Unified(...) = %q, want %q", got, want)
+	}
+}
+
+func TestBinaryHunkString(t *testing.T) {
+	hunks := Hunks("\x00a", "\x00b", BinaryDetection())
+	if len(hunks) != 1 {
+		t.Fatalf("Hunks(...) returned %d hunks, want 1", len(hunks))
+	}
+	if got, want := hunks[0].String(), "Binary files differ\n"; got != want {
+		t.Errorf("Hunk.String() = %q, want %q", got, want)
+	}
+}
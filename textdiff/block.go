@@ -0,0 +1,151 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"iter"
+
+	"znkr.io/diff/internal/byteview"
+	"znkr.io/diff/internal/config"
+	"znkr.io/diff/internal/rvecs"
+)
+
+// nestingDepth returns the nesting depth in front of every line and after the last line, as
+// reported by openClose, see [BlockContext].
+func nestingDepth(lines []byteview.ByteView, openClose func(line string) int) []int {
+	depth := make([]int, len(lines)+1)
+	for i, line := range lines {
+		depth[i+1] = depth[i] + openClose(byteview.UnsafeAs[string](line))
+	}
+	return depth
+}
+
+// hunkSeq returns the hunks of d. With [BlockContext] or [FunctionContext], hunks are expanded to
+// cover the innermost block or the functions that enclose all their changes. With [MinHunkChanges],
+// small hunks are dropped afterwards and with [MaxHunkLines], large hunks are split. [diff.MaxHunks]
+// limits the number of the resulting hunks.
+func (d *lineDiff) hunkSeq(cfg config.Config) iter.Seq[rvecs.Hunk] {
+	maxHunks := 0
+	if cfg.MinHunkChanges > 1 || cfg.MaxHunkLines > 0 {
+		// Limit the number of hunks after filtering and splitting them.
+		maxHunks, cfg.MaxHunks = cfg.MaxHunks, 0
+	}
+	var hunks iter.Seq[rvecs.Hunk]
+	if d.depth == nil && d.funcStart == nil {
+		hunks = rvecs.Hunks(d.rx, d.ry, cfg)
+	} else {
+		hunks = rvecs.HunksExpanded(d.rx, d.ry, cfg, d.expand)
+	}
+	if cfg.MinHunkChanges > 1 {
+		hunks = d.filterHunks(hunks, cfg.MinHunkChanges)
+	}
+	if cfg.MaxHunkLines > 0 {
+		hunks = rvecs.SplitHunks(hunks, d.rx, d.ry, cfg.MaxHunkLines)
+	}
+	if maxHunks > 0 {
+		hunks = limitHunks(hunks, maxHunks)
+	}
+	return hunks
+}
+
+// filterHunks drops all hunks with fewer than n inserted and deleted lines from hunks.
+func (d *lineDiff) filterHunks(hunks iter.Seq[rvecs.Hunk], n int) iter.Seq[rvecs.Hunk] {
+	return func(yield func(rvecs.Hunk) bool) {
+		for h := range hunks {
+			if countChanges(d.rx[h.S0:h.S1])+countChanges(d.ry[h.T0:h.T1]) < n {
+				continue
+			}
+			if !yield(h) {
+				return
+			}
+		}
+	}
+}
+
+// limitHunks stops the iteration over hunks after n hunks.
+func limitHunks(hunks iter.Seq[rvecs.Hunk], n int) iter.Seq[rvecs.Hunk] {
+	return func(yield func(rvecs.Hunk) bool) {
+		nhunks := 0
+		for h := range hunks {
+			if !yield(h) {
+				return
+			}
+			nhunks++
+			if nhunks == n {
+				return
+			}
+		}
+	}
+}
+
+// expand returns the range of lines in x that a hunk with changes between the lines s0 and s1 needs
+// to cover, see [rvecs.HunksExpanded].
+func (d *lineDiff) expand(s0, s1 int) (int, int) {
+	e0, e1 := s0, s1
+	if d.depth != nil {
+		e0, e1 = d.enclosingBlock(s0, s1)
+	}
+	if d.funcStart != nil {
+		f0, f1 := d.enclosingFunctions(s0, s1)
+		e0, e1 = min(e0, f0), max(e1, f1)
+	}
+	return e0, e1
+}
+
+// enclosingBlock returns the range of lines in x that make up the innermost block that encloses all
+// changes between the lines s0 and s1. The block includes the lines that open and close it. If the
+// changes aren't enclosed by a block, the range is returned unchanged.
+func (d *lineDiff) enclosingBlock(s0, s1 int) (int, int) {
+	level := d.depth[s0]
+	for _, v := range d.depth[s0 : s1+1] {
+		level = min(level, v)
+	}
+	if level <= 0 {
+		return s0, s1
+	}
+	// The block starts with the line that increases the depth to level and ends with the line
+	// that decreases it below level again. Unbalanced blocks extend to the start or end of x.
+	for s0 > 0 && d.depth[s0] >= level {
+		s0--
+	}
+	for s1 < len(d.x) && d.depth[s1] >= level {
+		s1++
+	}
+	return s0, s1
+}
+
+// enclosingFunctions returns the range of lines in x that make up the functions that enclose all
+// changes between the lines s0 and s1, see [FunctionContext]. If the changes start in front of the
+// first function, the range is returned unchanged.
+func (d *lineDiff) enclosingFunctions(s0, s1 int) (int, int) {
+	// The lines of x that are affected by the changes. Insertions only (s0 == s1) belong to the
+	// function of the line in front of them.
+	first, last := s0, s1-1
+	if s0 == s1 {
+		first, last = s0-1, s0-1
+	}
+	start := first
+	for start >= 0 && !d.funcStart[start] {
+		start--
+	}
+	if start < 0 {
+		return s0, s1
+	}
+	end := last + 1
+	for end < len(d.x) && !d.funcStart[end] {
+		end++
+	}
+	return start, max(end, s1)
+}
@@ -0,0 +1,213 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"znkr.io/diff"
+)
+
+// braces reports how a line of JSON changes the nesting depth.
+func braces(line string) int {
+	line = strings.TrimSpace(line)
+	switch {
+	case strings.HasSuffix(line, "{") || strings.HasSuffix(line, "["):
+		return 1
+	case strings.HasPrefix(line, "}") || strings.HasPrefix(line, "]"):
+		return -1
+	default:
+		return 0
+	}
+}
+
+func TestBlockContext(t *testing.T) {
+	x := `{
+  "name": "demo",
+  "config": {
+    "a": 1,
+    "b": 2,
+    "c": 3,
+    "d": 4,
+    "e": 5,
+    "f": 6
+  },
+  "other": true
+}
+`
+	tests := []struct {
+		name string
+		y    string
+		opts []diff.Option
+		want string
+	}{
+		{
+			name: "without-block-context",
+			y:    strings.Replace(x, `"e": 5`, `"e": 50`, 1),
+			opts: []diff.Option{diff.Context(1)},
+			want: "@@ -7,3 +7,3 @@\n" +
+				"     \"d\": 4,\n" +
+				"-    \"e\": 5,\n" +
+				"+    \"e\": 50,\n" +
+				"     \"f\": 6\n",
+		},
+		{
+			name: "object",
+			y:    strings.Replace(x, `"e": 5`, `"e": 50`, 1),
+			opts: []diff.Option{diff.Context(1), BlockContext(braces)},
+			want: "@@ -3,8 +3,8 @@\n" +
+				"   \"config\": {\n" +
+				"     \"a\": 1,\n" +
+				"     \"b\": 2,\n" +
+				"     \"c\": 3,\n" +
+				"     \"d\": 4,\n" +
+				"-    \"e\": 5,\n" +
+				"+    \"e\": 50,\n" +
+				"     \"f\": 6\n" +
+				"   },\n",
+		},
+		{
+			name: "merged",
+			y:    strings.Replace(strings.Replace(x, `"a": 1`, `"a": 10`, 1), `"f": 6`, `"f": 60`, 1),
+			opts: []diff.Option{diff.Context(0), BlockContext(braces)},
+			want: "@@ -3,8 +3,8 @@\n" +
+				"   \"config\": {\n" +
+				"-    \"a\": 1,\n" +
+				"+    \"a\": 10,\n" +
+				"     \"b\": 2,\n" +
+				"     \"c\": 3,\n" +
+				"     \"d\": 4,\n" +
+				"     \"e\": 5,\n" +
+				"-    \"f\": 6\n" +
+				"+    \"f\": 60\n" +
+				"   },\n",
+		},
+		{
+			name: "insertion",
+			y:    strings.Replace(x, `"c": 3,`, "\"c\": 3,\n    \"cc\": 33,", 1),
+			opts: []diff.Option{diff.Context(0), BlockContext(braces)},
+			want: "@@ -3,8 +3,9 @@\n" +
+				"   \"config\": {\n" +
+				"     \"a\": 1,\n" +
+				"     \"b\": 2,\n" +
+				"     \"c\": 3,\n" +
+				"+    \"cc\": 33,\n" +
+				"     \"d\": 4,\n" +
+				"     \"e\": 5,\n" +
+				"     \"f\": 6\n" +
+				"   },\n",
+		},
+		{
+			name: "whole-document",
+			y:    strings.Replace(x, `"name": "demo"`, `"name": "test"`, 1),
+			opts: []diff.Option{diff.Context(0), BlockContext(braces)},
+			want: "@@ -1,12 +1,12 @@\n" +
+				" {\n" +
+				"-  \"name\": \"demo\",\n" +
+				"+  \"name\": \"test\",\n" +
+				"   \"config\": {\n" +
+				"     \"a\": 1,\n" +
+				"     \"b\": 2,\n" +
+				"     \"c\": 3,\n" +
+				"     \"d\": 4,\n" +
+				"     \"e\": 5,\n" +
+				"     \"f\": 6\n" +
+				"   },\n" +
+				"   \"other\": true\n" +
+				" }\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unified(x, tt.y, tt.opts...)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Unified(...) result is different (-want, +got):\n%s", diff)
+			}
+			var b strings.Builder
+			if err := WriteUnified(&b, x, tt.y, tt.opts...); err != nil || b.String() != tt.want {
+				t.Errorf("WriteUnified(...) = %q, %v, want the same as Unified(...)", b.String(), err)
+			}
+		})
+	}
+}
+
+func TestBlockContextHunks(t *testing.T) {
+	// The hunks returned by Hunks are expanded the same way and all expanded lines are matches.
+	x := "a {\n  b\n  c {\n    d\n    e\n  }\n  f\n}\ng\n"
+	y := "a {\n  b\n  c {\n    D\n    e\n  }\n  f\n}\ng\n"
+	hunks := Hunks(x, y, diff.Context(0), BlockContext(braces))
+	if len(hunks) != 1 {
+		t.Fatalf("Hunks(...) returned %d hunks, want 1", len(hunks))
+	}
+	h := hunks[0]
+	if h.LineNoX != 2 || h.EndLineNoX != 6 || h.LineNoY != 2 || h.EndLineNoY != 6 {
+		t.Errorf("Hunks(...) = %v, want hunk covering the block from line 2 to 6", h)
+	}
+	want := []string{" ", "-", "+", " ", " "}
+	var got []string
+	for _, e := range h.Edits {
+		got = append(got, e.Op.Prefix())
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Hunks(...) edits are different (-want, +got):\n%s", diff)
+	}
+}
+
+func TestFunctionContext(t *testing.T) {
+	isFuncStart := func(line string) bool { return strings.HasPrefix(line, "func ") }
+	x := "header\n\nfunc a() {\n\t1\n\t2\n}\n\nfunc b() {\n\t3\n\t4\n}\n"
+	tests := []struct {
+		name string
+		y    string
+		want string
+	}{
+		{
+			name: "before-first-function",
+			y:    strings.Replace(x, "header", "HEADER", 1),
+			want: "@@ -1,1 +1,1 @@\n-header\n+HEADER\n",
+		},
+		{
+			name: "one-function",
+			y:    strings.Replace(x, "\t2", "\tTWO", 1),
+			want: "@@ -3,5 +3,5 @@\n func a() {\n \t1\n-\t2\n+\tTWO\n }\n \n",
+		},
+		{
+			name: "last-function",
+			y:    strings.Replace(x, "\t3", "\tTHREE", 1),
+			want: "@@ -8,4 +8,4 @@\n func b() {\n-\t3\n+\tTHREE\n \t4\n }\n",
+		},
+		{
+			name: "two-functions",
+			y:    strings.Replace(strings.Replace(x, "\t2", "\tTWO", 1), "\t3", "\tTHREE", 1),
+			want: "@@ -3,9 +3,9 @@\n func a() {\n \t1\n-\t2\n+\tTWO\n }\n \n func b() {\n-\t3\n+\tTHREE\n \t4\n }\n",
+		},
+		{
+			// An insertion belongs to the function in front of it.
+			name: "insertion",
+			y:    strings.Replace(x, "}\n\nfunc b", "}\n\nfunc new() {}\n\nfunc b", 1),
+			want: "@@ -3,5 +3,7 @@\n func a() {\n \t1\n \t2\n }\n \n+func new() {}\n+\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unified(x, tt.y, diff.Context(0), FunctionContext(isFuncStart))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Unified(...) result is different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"strings"
+	"unicode"
+
+	"znkr.io/diff"
+	"znkr.io/diff/internal/byteview"
+)
+
+// ChangeClass describes how substantial a change is.
+//
+//go:generate go tool golang.org/x/tools/cmd/stringer -type=ChangeClass
+type ChangeClass int
+
+const (
+	Unchanged      ChangeClass = iota // A matching line
+	WhitespaceOnly                    // The lines only differ in white space, including newlines
+	CaseOnly                          // The lines only differ in case and possibly white space
+	Substantive                       // The lines differ otherwise or the change isn't a replacement
+)
+
+// ClassifiedEdit is an [Edit] together with the class of the change.
+type ClassifiedEdit[T string | []byte] struct {
+	Edit[T]
+	Class ChangeClass
+
+	// TrailingWS is set if the line differs from the line it's paired with only in trailing
+	// spaces and tabs, e.g. "foo\t\n" and "foo  \n". Line endings are not trailing whitespace.
+	// For matches, it's set if the line in x differs from LineY in this way, which is only possible
+	// with options that transform lines, like [IgnoreMatching].
+	TrailingWS bool
+}
+
+// ClassifyEdits classifies all edits in hunks, e.g. to allow a UI to de-emphasize trivial changes.
+//
+// Deletions and insertions are classified in pairs: Within a hunk, a run of deletions that is
+// directly followed by a run of insertions is a replacement. The first deleted line is paired with
+// the first inserted line, the second with the second, and so on. Both edits of a pair get the
+// same class, depending on how the two lines differ. Edits that aren't part of a pair are always
+// [Substantive], matches are [Unchanged].
+//
+// The edits keep the original lines, including all whitespace, e.g. to allow a formatter to fix
+// lines with [ClassifiedEdit.TrailingWS] set.
+func ClassifyEdits[T string | []byte](hunks []Hunk[T]) []ClassifiedEdit[T] {
+	var out []ClassifiedEdit[T]
+	for _, h := range hunks {
+		edits := h.Edits
+		for i := 0; i < len(edits); {
+			if edits[i].Op == diff.Match {
+				e := edits[i]
+				out = append(out, ClassifiedEdit[T]{e, Unchanged, len(e.LineY) > 0 && trailingWSOnly(e.Line, e.LineY)})
+				i++
+				continue
+			}
+			// Find the runs of deletions and insertions.
+			del := i
+			for i < len(edits) && edits[i].Op == diff.Delete {
+				i++
+			}
+			ins := i
+			for i < len(edits) && edits[i].Op == diff.Insert {
+				i++
+			}
+			ndel, nins := ins-del, i-ins
+			for j, e := range edits[del:ins] {
+				class, ws := Substantive, false
+				if j < nins {
+					class, ws = classifyChange(e.Line, edits[ins+j].Line), trailingWSOnly(e.Line, edits[ins+j].Line)
+				}
+				out = append(out, ClassifiedEdit[T]{e, class, ws})
+			}
+			for j, e := range edits[ins:i] {
+				class, ws := Substantive, false
+				if j < ndel {
+					class, ws = classifyChange(edits[del+j].Line, e.Line), trailingWSOnly(edits[del+j].Line, e.Line)
+				}
+				out = append(out, ClassifiedEdit[T]{e, class, ws})
+			}
+		}
+	}
+	return out
+}
+
+// classifyChange classifies the change from line x to line y.
+func classifyChange[T string | []byte](x, y T) ChangeClass {
+	a := byteview.UnsafeAs[string](byteview.From(x))
+	b := byteview.UnsafeAs[string](byteview.From(y))
+	switch {
+	case removeSpace(a) == removeSpace(b):
+		return WhitespaceOnly
+	case strings.EqualFold(removeSpace(a), removeSpace(b)):
+		return CaseOnly
+	default:
+		return Substantive
+	}
+}
+
+// trailingWSOnly reports whether the lines x and y only differ in the spaces and tabs in front of
+// their line endings.
+func trailingWSOnly[T string | []byte](x, y T) bool {
+	a := byteview.UnsafeAs[string](byteview.From(x))
+	b := byteview.UnsafeAs[string](byteview.From(y))
+	if a == b {
+		return false
+	}
+	a, eolA := splitLineEnding(a)
+	b, eolB := splitLineEnding(b)
+	return eolA == eolB && strings.TrimRight(a, " \t") == strings.TrimRight(b, " \t")
+}
+
+// splitLineEnding splits line into its content and its line ending ("\n", "\r\n", or "").
+func splitLineEnding(line string) (content, eol string) {
+	switch {
+	case strings.HasSuffix(line, "\r\n"):
+		return line[:len(line)-2], "\r\n"
+	case strings.HasSuffix(line, "\n"):
+		return line[:len(line)-1], "\n"
+	default:
+		return line, ""
+	}
+}
+
+func removeSpace(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// markReindent marks pairs of deleted and inserted lines that only differ in their leading
+// whitespace, see [MarkReindent]. Lines are paired like in [ClassifyEdits].
+func markReindent[T string | []byte](edits []Edit[T]) {
+	for i := 0; i < len(edits); {
+		if edits[i].Op != diff.Delete {
+			i++
+			continue
+		}
+		del := i
+		for i < len(edits) && edits[i].Op == diff.Delete {
+			i++
+		}
+		ins := i
+		for i < len(edits) && edits[i].Op == diff.Insert {
+			i++
+		}
+		for j := range min(ins-del, i-ins) {
+			if isReindent(edits[del+j].Line, edits[ins+j].Line) {
+				edits[del+j].Reindent = true
+				edits[ins+j].Reindent = true
+			}
+		}
+	}
+}
+
+// isReindent reports whether x and y only differ in their leading spaces and tabs.
+func isReindent[T string | []byte](x, y T) bool {
+	a := byteview.UnsafeAs[string](byteview.From(x))
+	b := byteview.UnsafeAs[string](byteview.From(y))
+	return a != b && strings.TrimLeft(a, " \t") == strings.TrimLeft(b, " \t")
+}
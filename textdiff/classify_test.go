@@ -0,0 +1,260 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"znkr.io/diff"
+)
+
+func TestClassifyEdits(t *testing.T) {
+	type classified struct {
+		Line       string
+		Class      ChangeClass
+		TrailingWS bool
+	}
+	tests := []struct {
+		name string
+		x, y string
+		opts []diff.Option
+		want []classified
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "trailing-whitespace",
+			x:    "a\nb\nc\n",
+			y:    "a\nb \nc\n",
+			want: []classified{
+				{"a\n", Unchanged, false},
+				{"b\n", WhitespaceOnly, true},
+				{"b \n", WhitespaceOnly, true},
+				{"c\n", Unchanged, false},
+			},
+		},
+		{
+			name: "trailing-tabs-vs-spaces",
+			x:    "a\nb\t\t\nc\n",
+			y:    "a\nb  \nc\n",
+			want: []classified{
+				{"a\n", Unchanged, false},
+				{"b\t\t\n", WhitespaceOnly, true},
+				{"b  \n", WhitespaceOnly, true},
+				{"c\n", Unchanged, false},
+			},
+		},
+		{
+			name: "trailing-whitespace-crlf",
+			x:    "a\r\nb\t\r\n",
+			y:    "a\r\nb \r\n",
+			want: []classified{
+				{"a\r\n", Unchanged, false},
+				{"b\t\r\n", WhitespaceOnly, true},
+				{"b \r\n", WhitespaceOnly, true},
+			},
+		},
+		{
+			name: "trailing-whitespace-ignored",
+			x:    "a\nb\t\nc\n",
+			y:    "a\nb \nC\n",
+			opts: []diff.Option{IgnoreMatching(func(line string) bool { return strings.HasPrefix(line, "b") })},
+			want: []classified{
+				{"a\n", Unchanged, false},
+				{"b\t\n", Unchanged, true},
+				{"c\n", CaseOnly, false},
+				{"C\n", CaseOnly, false},
+			},
+		},
+		{
+			name: "inner-whitespace",
+			x:    "a b\n",
+			y:    "a\tb\n",
+			want: []classified{
+				{"a b\n", WhitespaceOnly, false},
+				{"a\tb\n", WhitespaceOnly, false},
+			},
+		},
+		{
+			name: "indentation",
+			x:    "if x {\nfoo()\n}\n",
+			y:    "if x {\n\tfoo()\n}\n",
+			want: []classified{
+				{"if x {\n", Unchanged, false},
+				{"foo()\n", WhitespaceOnly, false},
+				{"\tfoo()\n", WhitespaceOnly, false},
+				{"}\n", Unchanged, false},
+			},
+		},
+		{
+			name: "missing-newline",
+			x:    "a\nb",
+			y:    "a\nb\n",
+			want: []classified{
+				{"a\n", Unchanged, false},
+				{"b", WhitespaceOnly, false},
+				{"b\n", WhitespaceOnly, false},
+			},
+		},
+		{
+			name: "case",
+			x:    "a\nhello world\nc\n",
+			y:    "a\nHello  World\nc\n",
+			want: []classified{
+				{"a\n", Unchanged, false},
+				{"hello world\n", CaseOnly, false},
+				{"Hello  World\n", CaseOnly, false},
+				{"c\n", Unchanged, false},
+			},
+		},
+		{
+			name: "substantive",
+			x:    "a\nfoo\nc\n",
+			y:    "a\nbar\nc\n",
+			want: []classified{
+				{"a\n", Unchanged, false},
+				{"foo\n", Substantive, false},
+				{"bar\n", Substantive, false},
+				{"c\n", Unchanged, false},
+			},
+		},
+		{
+			name: "unpaired",
+			x:    "a\nb\nc\nd\n",
+			y:    "a\nB\nd\n",
+			want: []classified{
+				{"a\n", Unchanged, false},
+				{"b\n", CaseOnly, false},
+				{"c\n", Substantive, false},
+				{"B\n", CaseOnly, false},
+				{"d\n", Unchanged, false},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []classified
+			for _, e := range ClassifyEdits(Hunks(tt.x, tt.y, tt.opts...)) {
+				got = append(got, classified{e.Line, e.Class, e.TrailingWS})
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ClassifyEdits(...) result is different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMarkReindent(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y string
+		want []string
+	}{
+		{
+			name: "reindented-block",
+			x:    "if x {\nfoo()\nbar()\n}\n",
+			y:    "if x {\n\tfoo()\n\tbar()\n}\n",
+			want: []string{
+				" if x {",
+				">foo()",
+				">bar()",
+				">\tfoo()",
+				">\tbar()",
+				" }",
+			},
+		},
+		{
+			name: "edited-block",
+			x:    "if x {\nfoo()\nbar()\n}\n",
+			y:    "if x {\n\tfoo(1)\n\tbaz()\n}\n",
+			want: []string{
+				" if x {",
+				"-foo()",
+				"-bar()",
+				"+\tfoo(1)",
+				"+\tbaz()",
+				" }",
+			},
+		},
+		{
+			name: "partially-edited-block",
+			x:    "if x {\nfoo()\nbar()\n}\n",
+			y:    "if x {\n\tfoo()\n\tbaz()\n}\n",
+			want: []string{
+				" if x {",
+				">foo()",
+				"-bar()",
+				">\tfoo()",
+				"+\tbaz()",
+				" }",
+			},
+		},
+		{
+			name: "trailing-whitespace",
+			x:    "a\nb\nc\n",
+			y:    "a\nb \nc\n",
+			want: []string{
+				" a",
+				"-b",
+				"+b ",
+				" c",
+			},
+		},
+		{
+			name: "unpaired",
+			x:    "a\nc\n",
+			y:    "a\n  b\nc\n",
+			want: []string{
+				" a",
+				"+  b",
+				" c",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, e := range Edits(tt.x, tt.y, MarkReindent()) {
+				got = append(got, e.String())
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Edits(...) result is different [-want,+got]:\n%s", diff)
+			}
+
+			// Hunks must mark the same edits.
+			got = got[:0]
+			for _, h := range Hunks(tt.x, tt.y, MarkReindent()) {
+				for _, e := range h.Edits {
+					got = append(got, e.String())
+				}
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Hunks(...) result is different [-want,+got]:\n%s", diff)
+			}
+		})
+	}
+
+	// ClassifyEdits and HunkRanges treat reindented lines like deletions and insertions.
+	hunks := Hunks("foo()\n", "\tfoo()\n", MarkReindent())
+	if got := ClassifyEdits(hunks); len(got) != 2 || !got[0].Reindent || got[0].Class != WhitespaceOnly || got[1].Class != WhitespaceOnly {
+		t.Errorf("ClassifyEdits(...) = %v, want two WhitespaceOnly reindent edits", got)
+	}
+	if del, ins := HunkRanges(hunks[0]); !cmp.Equal(del, [][2]int{{0, 1}}) || !cmp.Equal(ins, [][2]int{{0, 1}}) {
+		t.Errorf("HunkRanges(...) = %v, %v, want [[0 1]], [[0 1]]", del, ins)
+	}
+}
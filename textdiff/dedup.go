@@ -0,0 +1,81 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"slices"
+
+	"znkr.io/diff/internal/byteview"
+)
+
+// DedupHunks removes duplicate hunks from hunks, e.g., to show a change that was made in several
+// places only once and to reference it from the other places. Two hunks are duplicates if their
+// edits have the same ops and lines. The positions of the edits and hunks are ignored.
+//
+// DedupHunks returns the first hunk of every group of duplicates in their original order and a
+// map from the index of a kept hunk in the returned slice to the indices of its duplicates in
+// hunks. Hunks without duplicates have no entry in the map.
+//
+// DedupHunks is a post-processing helper for the output of [Hunks] and related functions, it
+// doesn't modify hunks.
+func DedupHunks[T string | []byte](hunks []Hunk[T]) ([]Hunk[T], map[int][]int) {
+	var (
+		out  []Hunk[T]
+		dups map[int][]int
+	)
+	kept := make(map[uint64][]int) // hash -> indices into out
+outer:
+	for i, h := range hunks {
+		sum := hunkHash(h)
+		for _, k := range kept[sum] {
+			if sameEdits(out[k].Edits, h.Edits) {
+				if dups == nil {
+					dups = make(map[int][]int)
+				}
+				dups[k] = append(dups[k], i)
+				continue outer
+			}
+		}
+		kept[sum] = append(kept[sum], len(out))
+		out = append(out, h)
+	}
+	return out, dups
+}
+
+// hunkHash returns a 64-bit FNV-1a hash of the ops and lines of all edits in h.
+func hunkHash[T string | []byte](h Hunk[T]) uint64 {
+	hash := fnv.New64a()
+	var buf [binary.MaxVarintLen64]byte
+	write := func(line T) {
+		// Prefix the line with its length to make the encoding unambiguous.
+		hash.Write(binary.AppendUvarint(buf[:0], uint64(len(line))))
+		hash.Write(byteview.UnsafeAs[[]byte](byteview.From(line)))
+	}
+	for _, e := range h.Edits {
+		hash.Write([]byte{byte(e.Op)})
+		write(e.Line)
+		write(e.LineY)
+	}
+	return hash.Sum64()
+}
+
+// sameEdits reports whether a and b have the same ops and lines.
+func sameEdits[T string | []byte](a, b []Edit[T]) bool {
+	return slices.EqualFunc(a, b, func(a, b Edit[T]) bool {
+		return a.Op == b.Op && string(a.Line) == string(b.Line) && string(a.LineY) == string(b.LineY)
+	})
+}
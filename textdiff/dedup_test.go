@@ -0,0 +1,98 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"znkr.io/diff"
+)
+
+func TestDedupHunks(t *testing.T) {
+	x := "foo\nold\nbar\n1\n2\n3\n4\nfoo\nold\nbar\n5\n6\n7\n8\nbaz\n9\n10\n11\n12\nfoo\nold\nbar\n"
+	y := "foo\nnew\nbar\n1\n2\n3\n4\nfoo\nnew\nbar\n5\n6\n7\n8\nqux\n9\n10\n11\n12\nfoo\nnew\nbar\n"
+	hunks := Hunks(x, y, diff.Context(1))
+	if len(hunks) != 4 {
+		t.Fatalf("Hunks(...) returned %d hunks, want 4", len(hunks))
+	}
+
+	got, gotDups := DedupHunks(hunks)
+	want := []Hunk[string]{hunks[0], hunks[2]}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("DedupHunks(...) hunks are different (-want, +got):\n%s", diff)
+	}
+	wantDups := map[int][]int{0: {1, 3}}
+	if diff := cmp.Diff(wantDups, gotDups); diff != "" {
+		t.Errorf("DedupHunks(...) duplicates are different (-want, +got):\n%s", diff)
+	}
+}
+
+func TestDedupHunksBytes(t *testing.T) {
+	x := []byte("a\nb\nc\n1\n2\n3\na\nb\nc\n")
+	y := []byte("a\nB\nc\n1\n2\n3\na\nB\nc\n")
+	hunks := Hunks(x, y, diff.Context(1))
+	if len(hunks) != 2 {
+		t.Fatalf("Hunks(...) returned %d hunks, want 2", len(hunks))
+	}
+
+	got, gotDups := DedupHunks(hunks)
+	if diff := cmp.Diff([]Hunk[[]byte]{hunks[0]}, got); diff != "" {
+		t.Errorf("DedupHunks(...) hunks are different (-want, +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[int][]int{0: {1}}, gotDups); diff != "" {
+		t.Errorf("DedupHunks(...) duplicates are different (-want, +got):\n%s", diff)
+	}
+}
+
+func TestDedupHunksUnique(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "identical",
+			x:    "a\nb\n",
+			y:    "a\nb\n",
+		},
+		{
+			// Same changes, but different context.
+			name: "different-context",
+			x:    "a\nold\nb\n1\n2\n3\n4\nc\nold\nd\n",
+			y:    "a\nnew\nb\n1\n2\n3\n4\nc\nnew\nd\n",
+		},
+		{
+			// Same lines, but different ops.
+			name: "different-ops",
+			x:    "a\nb\n1\n2\n3\n4\na\n",
+			y:    "a\n1\n2\n3\n4\na\nb\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hunks := Hunks(tt.x, tt.y, diff.Context(1))
+			got, gotDups := DedupHunks(hunks)
+			if diff := cmp.Diff(hunks, got); diff != "" {
+				t.Errorf("DedupHunks(...) hunks are different (-want, +got):\n%s", diff)
+			}
+			if len(gotDups) != 0 {
+				t.Errorf("DedupHunks(...) duplicates = %v, want none", gotDups)
+			}
+		})
+	}
+}
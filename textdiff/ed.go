@@ -0,0 +1,88 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"strconv"
+
+	"znkr.io/diff/internal/byteview"
+	"znkr.io/diff/internal/config"
+	"znkr.io/diff/internal/rvecs"
+)
+
+// Ed compares the lines in x and y and returns an edit script for the ed line editor that converts
+// x to y, like the output of diff -e.
+//
+// The commands are emitted from the end of the input to the beginning, so that line numbers stay
+// valid while the script is applied. Inserted lines that consist of a single "." are escaped in the
+// same way GNU diff does it. An ed script can't express a missing newline at the end of the input,
+// a missing newline is therefore added.
+//
+// The following options are supported: [diff.Minimal], [diff.PreferLongMatches], [diff.Fast],
+// [diff.AutoFast], [diff.Parallel], [IndentHeuristic]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func Ed[T string | []byte](x, y T, opts ...Option) T {
+	cfg := config.FromOptions(opts, config.Algorithm|config.IndentHeuristic)
+	cfg.Context = 0
+
+	d := diffLines(x, y, cfg)
+	var hunks []rvecs.Hunk
+	for h := range rvecs.Hunks(d.rx, d.ry, cfg) {
+		hunks = append(hunks, h)
+	}
+
+	var b byteview.Builder[T]
+	for i := len(hunks) - 1; i >= 0; i-- {
+		h := hunks[i]
+		switch {
+		case h.S0 == h.S1:
+			b.WriteString(strconv.Itoa(h.S0))
+			b.WriteString("a\n")
+		case h.T0 == h.T1:
+			writeEdRange(&b, h.S0, h.S1)
+			b.WriteString("d\n")
+			continue
+		default:
+			writeEdRange(&b, h.S0, h.S1)
+			b.WriteString("c\n")
+		}
+		for _, line := range d.y[h.T0:h.T1] {
+			s := byteview.UnsafeAs[string](line)
+			if s == "." || s == ".\n" {
+				// A single "." terminates the input mode. Write ".." instead, leave input mode,
+				// remove the extra ".", and continue appending after the current line.
+				b.WriteString("..\n.\ns/.//\na\n")
+				continue
+			}
+			b.WriteByteView(line)
+			if !hasNewline(line) {
+				b.WriteString("\n")
+			}
+		}
+		b.WriteString(".\n")
+	}
+	return b.Build()
+}
+
+// writeEdRange writes the ed address for the zero-based, half-open line range [s0, s1).
+func writeEdRange[T string | []byte](b *byteview.Builder[T], s0, s1 int) {
+	b.WriteString(strconv.Itoa(s0 + 1))
+	if s1-s0 > 1 {
+		b.WriteString(",")
+		b.WriteString(strconv.Itoa(s1))
+	}
+}
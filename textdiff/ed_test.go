@@ -0,0 +1,161 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"znkr.io/diff/internal/unixpatch"
+)
+
+func TestEd(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y string
+		want string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "identical",
+			x:    "a\nb\n",
+			y:    "a\nb\n",
+		},
+		{
+			name: "delete",
+			x:    "a\nb\nc\nd\n",
+			y:    "a\nd\n",
+			want: "2,3d\n",
+		},
+		{
+			name: "change-and-append",
+			x:    "a\nb\nc\n",
+			y:    "a\nB\nc\nd\n",
+			want: "3a\nd\n.\n2c\nB\n.\n",
+		},
+		{
+			name: "prepend",
+			x:    "b\n",
+			y:    "a\nb\n",
+			want: "0a\na\n.\n",
+		},
+		{
+			name: "dot",
+			x:    "a\n",
+			y:    "a\n.\nb\n",
+			want: "1a\n..\n.\ns/.//\na\nb\n.\n",
+		},
+		{
+			name: "missing-newline",
+			x:    "a\nb",
+			y:    "a\nc",
+			want: "2c\nc\n.\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Ed(tt.x, tt.y)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Ed(...) result is different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEdApply(t *testing.T) {
+	for _, tt := range parseTests(t) {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y := withNewline(string(tt.x)), withNewline(string(tt.y))
+			script := Ed(x, y)
+
+			got, err := applyEd(x, script)
+			if err != nil {
+				t.Fatalf("failed to apply ed script: %v", err)
+			}
+			if got != y {
+				t.Errorf("result is different after applying ed script [-want,+got]:\n%s", cmp.Diff(y, got))
+			}
+
+			if *validate {
+				got, err := unixpatch.Ed(x, script)
+				if err != nil {
+					t.Fatalf("failed to run ed: %v", err)
+				}
+				if got != y {
+					t.Errorf("result is different after running ed [-want,+got]:\n%s", cmp.Diff(y, got))
+				}
+			}
+		})
+	}
+}
+
+func withNewline(s string) string {
+	if s != "" && !strings.HasSuffix(s, "\n") {
+		return s + "\n"
+	}
+	return s
+}
+
+var edCommand = regexp.MustCompile(`^(?:(\d+)(?:,(\d+))?)?([acd])$`)
+
+// applyEd applies the subset of ed commands that's used by Ed to x.
+func applyEd(x, script string) (string, error) {
+	lines := slices.Collect(strings.Lines(x))
+	cur := len(lines) // current line, one-based
+	cmds := slices.Collect(strings.Lines(script))
+	for i := 0; i < len(cmds); i++ {
+		cmd := strings.TrimSuffix(cmds[i], "\n")
+		if cmd == "s/.//" {
+			lines[cur-1] = lines[cur-1][1:]
+			continue
+		}
+		m := edCommand.FindStringSubmatch(cmd)
+		if m == nil {
+			return "", fmt.Errorf("line %d: unknown command %q", i+1, cmd)
+		}
+		from, to := cur, cur
+		if m[1] != "" {
+			from, _ = strconv.Atoi(m[1])
+			to = from
+		}
+		if m[2] != "" {
+			to, _ = strconv.Atoi(m[2])
+		}
+		switch m[3] {
+		case "d":
+			lines = slices.Delete(lines, from-1, to)
+			cur = from - 1
+			continue
+		case "c":
+			lines = slices.Delete(lines, from-1, to)
+			cur = from - 1
+		case "a":
+			cur = from
+		}
+		// Input mode.
+		for i++; i < len(cmds) && cmds[i] != ".\n"; i++ {
+			lines = slices.Insert(lines, cur, cmds[i])
+			cur++
+		}
+	}
+	return strings.Join(lines, ""), nil
+}
@@ -95,3 +95,29 @@ end
 	//    i.upcase
 	//  end
 }
+
+func ExampleHunk_String() {
+	x := "a\nb\nc\n"
+	y := "a\nB\nc"
+	for _, h := range textdiff.Hunks(x, y) {
+		fmt.Print(h)
+	}
+	// Output:
+	// @@ -1,3 +1,3 @@
+	//  a
+	// -b
+	// -c
+	// +B
+	// +c
+	// \ No newline at end of file
+}
+
+func ExampleEdit_String() {
+	for _, e := range textdiff.Edits("a\nb\n", "a\nc\n") {
+		fmt.Println(e)
+	}
+	// Output:
+	//  a
+	// -b
+	// +c
+}
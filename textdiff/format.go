@@ -0,0 +1,82 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"io"
+
+	"znkr.io/diff/internal/byteview"
+	"znkr.io/diff/internal/config"
+)
+
+// Formatter formats the hunks of a line-by-line diff, see [Format].
+type Formatter[T string | []byte] interface {
+	// FormatHunk writes hunk h to w. The index i is the position of h in the diff, starting at 0
+	// for the first hunk. Writes to w never fail. The edits of h are only valid until FormatHunk
+	// returns, they must not be retained.
+	FormatHunk(w io.Writer, i int, h Hunk[T])
+}
+
+// Format compares the lines in x and y and formats the result using f. It calls f.FormatHunk for
+// every hunk in order and returns the concatenated output. If x and y are identical, the output is
+// empty.
+//
+// Format allows to create custom output formats, e.g., annotations for a code review system.
+// [UnifiedNoContextCopy] is implemented the same way.
+//
+// The following options are supported: [diff.Context], [diff.Minimal], [diff.PreferLongMatches],
+// [diff.Fast], [diff.AutoFast], [diff.Parallel], [diff.PairedOrdering], [IndentHeuristic],
+// [NormalizeUnicode], [IgnoreMatching], [IgnoreComments], [IgnoreReorderedBlocks], [AnchorOn],
+// [FuzzyLines], [BlockContext], [FunctionContext], [MaxHunkLines], [MinHunkChanges]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func Format[T string | []byte](x, y T, f Formatter[T], opts ...Option) T {
+	cfg := config.FromOptions(opts, config.Algorithm|lineFlags|hunkFlags)
+	return format(diffLines(x, y, cfg), cfg, f)
+}
+
+// sizer is implemented by formatters that can compute the length of their output upfront. This
+// allows format to allocate the output buffer only once.
+type sizer[T string | []byte] interface {
+	size(i int, h Hunk[T]) int
+}
+
+func format[T string | []byte](d lineDiff, cfg config.Config, f Formatter[T]) T {
+	// Hunks are created one by one, reusing the edits buffer, to avoid materializing all edits
+	// at once.
+	nedits := 0
+	for hunk := range d.hunkSeq(cfg) {
+		nedits = max(nedits, hunk.Edits)
+	}
+	eout := make([]Edit[T], 0, nedits)
+	var b byteview.Builder[T]
+	if s, ok := f.(sizer[T]); ok {
+		n, i := 0, 0
+		for hunk := range d.hunkSeq(cfg) {
+			eout = appendEdits(eout[:0], d, hunk, cfg)
+			n += s.size(i, makeHunk(d, hunk, eout))
+			i++
+		}
+		b.Grow(n)
+	}
+	i := 0
+	for hunk := range d.hunkSeq(cfg) {
+		eout = appendEdits(eout[:0], d, hunk, cfg)
+		f.FormatHunk(&b, i, makeHunk(d, hunk, eout))
+		i++
+	}
+	return b.Build()
+}
@@ -0,0 +1,56 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"znkr.io/diff"
+)
+
+// annotationFormatter writes one annotation per changed line range in y.
+type annotationFormatter struct{}
+
+func (annotationFormatter) FormatHunk(w io.Writer, i int, h Hunk[string]) {
+	deletes, inserts := HunkRanges(h)
+	fmt.Fprintf(w, "hunk %d: %d deleted, %d inserted\n", i, len(deletes), len(inserts))
+	for _, r := range inserts {
+		fmt.Fprintf(w, "::notice line=%d,endLine=%d::changed\n", r[0]+1, r[1])
+	}
+}
+
+func TestFormat(t *testing.T) {
+	x := "a\nb\nc\nd\ne\nf\ng\nh\n"
+	y := "a\nB\nc\nd\ne\nf\nG\nH\nh\n"
+	want := strings.Join([]string{
+		"hunk 0: 1 deleted, 1 inserted",
+		"::notice line=2,endLine=2::changed",
+		"hunk 1: 1 deleted, 1 inserted",
+		"::notice line=7,endLine=8::changed",
+		"",
+	}, "\n")
+	got := Format(x, y, annotationFormatter{}, diff.Context(1))
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Format(...) result is different [-want,+got]:\n%s", diff)
+	}
+
+	if got := Format(x, x, annotationFormatter{}); got != "" {
+		t.Errorf("Format(x, x, ...) = %q, want empty output", got)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"hash/fnv"
+
+	"znkr.io/diff/internal/byteview"
+)
+
+// IdentifiedHunk is a [Hunk] together with an ID for every edit, see [HunksStableIDs].
+type IdentifiedHunk[T string | []byte] struct {
+	Hunk[T]
+	IDs []uint64 // IDs[i] is the ID of Edits[i]
+}
+
+// HunksStableIDs is like [Hunks], but additionally assigns an ID to every edit. This is meant for
+// incremental rendering, e.g., in an editor that diffs the inputs again after every change: The ID
+// only depends on the content of the line, so a line keeps its ID across diffs of different
+// versions of the inputs and a renderer can reuse whatever it created for that line before.
+//
+// The ID is a 64-bit FNV-1a hash of [Edit.Line]. Identical lines always have the same ID, even if
+// they appear multiple times in the same diff or with a different op. This is intended: A matched
+// line that was rendered as an insertion before keeps its ID. Callers that need unique IDs within
+// one diff can combine the ID with the number of previous occurrences of the same ID.
+//
+// The following options are supported: [diff.Context], [diff.Minimal], [diff.PreferLongMatches],
+// [diff.Fast], [diff.AutoFast], [diff.Parallel], [diff.PairedOrdering], [IndentHeuristic],
+// [NormalizeUnicode], [IgnoreMatching], [IgnoreComments], [IgnoreReorderedBlocks], [AnchorOn],
+// [FuzzyLines], [BlockContext], [FunctionContext], [MaxHunkLines], [MinHunkChanges],
+// [MarkReindent], [diff.MaxHunks], [BinaryDetection]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func HunksStableIDs[T string | []byte](x, y T, opts ...Option) []IdentifiedHunk[T] {
+	hunks := Hunks(x, y, opts...)
+	if len(hunks) == 0 {
+		return nil
+	}
+	out := make([]IdentifiedHunk[T], len(hunks))
+	for i, h := range hunks {
+		ids := make([]uint64, len(h.Edits))
+		for j, e := range h.Edits {
+			ids[j] = lineID(e.Line)
+		}
+		out[i] = IdentifiedHunk[T]{h, ids}
+	}
+	return out
+}
+
+// lineID returns the ID of line, see [HunksStableIDs].
+func lineID[T string | []byte](line T) uint64 {
+	h := fnv.New64a()
+	h.Write(byteview.UnsafeAs[[]byte](byteview.From(line)))
+	return h.Sum64()
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"testing"
+
+	"znkr.io/diff"
+)
+
+func TestHunksStableIDs(t *testing.T) {
+	// Two successive diffs, like an editor would compute them while the user is typing.
+	x := "a\nb\nc\nd\ne\n"
+	y1 := "a\nb\nX\nd\ne\n"
+	y2 := "a\nb\nXY\nd\ne\n"
+
+	ids := func(y string) map[string]uint64 {
+		m := make(map[string]uint64)
+		for _, h := range HunksStableIDs(x, y) {
+			if len(h.IDs) != len(h.Edits) {
+				t.Fatalf("HunksStableIDs(...) returned %d IDs for %d edits", len(h.IDs), len(h.Edits))
+			}
+			for i, e := range h.Edits {
+				m[e.Op.Prefix()+e.Line] = h.IDs[i]
+			}
+		}
+		return m
+	}
+	first, second := ids(y1), ids(y2)
+
+	// Matched and deleted lines keep their IDs.
+	for _, line := range []string{" a\n", " b\n", "-c\n", " d\n", " e\n"} {
+		id1, ok1 := first[line]
+		id2, ok2 := second[line]
+		if !ok1 || !ok2 {
+			t.Fatalf("line %q is missing in one of the diffs", line)
+		}
+		if id1 != id2 {
+			t.Errorf("ID of line %q changed from %x to %x", line, id1, id2)
+		}
+	}
+	// The changed line gets a new ID.
+	if first["+X\n"] == second["+XY\n"] {
+		t.Errorf("lines %q and %q have the same ID", "X\n", "XY\n")
+	}
+	// IDs only depend on the content, not on the op.
+	if got, want := first["-c\n"], ids("a\nb\nc\n")[" c\n"]; got != want {
+		t.Errorf("ID of deleted line c = %x, ID of matched line c = %x, want them to be equal", got, want)
+	}
+}
+
+func TestHunksStableIDsCollide(t *testing.T) {
+	// Identical lines have the same ID, even within the same diff.
+	hunks := HunksStableIDs("x\n", "x\ny\nx\n", diff.Context(1))
+	if len(hunks) != 1 {
+		t.Fatalf("HunksStableIDs(...) returned %d hunks, want 1", len(hunks))
+	}
+	h := hunks[0]
+	var xs []uint64
+	for i, e := range h.Edits {
+		if e.Line == "x\n" {
+			xs = append(xs, h.IDs[i])
+		}
+	}
+	if len(xs) != 2 || xs[0] != xs[1] {
+		t.Errorf("IDs of line %q = %x, want two identical IDs", "x\n", xs)
+	}
+}
+
+func TestHunksStableIDsIdentical(t *testing.T) {
+	if got := HunksStableIDs("a\n", "a\n"); got != nil {
+		t.Errorf("HunksStableIDs(...) = %v, want nil", got)
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"znkr.io/diff"
+	"znkr.io/diff/internal/byteview"
+	"znkr.io/diff/internal/config"
+)
+
+// Interdiff compares two unified diffs of the same file, e.g., two versions of a patch, and returns
+// a unified diff from the content patchA produces to the content patchB produces.
+//
+// A unified diff only contains the parts of the original file that are close to a change. Interdiff
+// reconstructs these parts from both patches and compares the result of applying each patch to
+// them. Changes in regions that only one of the patches touches are part of the output, too. The
+// patches may use different amounts of context. Lines before the first hunk header (e.g. file
+// headers) and between hunks are ignored.
+//
+// An error is returned if a patch is malformed or if the patches disagree about the content of the
+// original file.
+//
+// The following options are supported: [diff.Context], [diff.Minimal], [diff.PreferLongMatches],
+// [diff.Fast], [diff.AutoFast], [diff.Parallel], [IndentHeuristic]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func Interdiff[T string | []byte](patchA, patchB T, opts ...Option) (T, error) {
+	cfg := config.FromOptions(opts, config.Algorithm|config.Context|config.IndentHeuristic)
+
+	var zero T
+	ha, err := parsePatch(byteview.UnsafeAs[string](byteview.From(patchA)))
+	if err != nil {
+		return zero, fmt.Errorf("failed to parse patchA: %v", err)
+	}
+	hb, err := parsePatch(byteview.UnsafeAs[string](byteview.From(patchB)))
+	if err != nil {
+		return zero, fmt.Errorf("failed to parse patchB: %v", err)
+	}
+
+	// Reconstruct the known parts of the original file.
+	orig := make(map[int]string)
+	for _, h := range slices.Concat(ha, hb) {
+		for i, line := range h.old {
+			if l, ok := orig[h.pos+i]; ok && l != line {
+				return zero, fmt.Errorf("patches disagree about line %d of the original file", h.pos+i+1)
+			}
+			orig[h.pos+i] = line
+		}
+	}
+
+	var b byteview.Builder[T]
+	deltaA, deltaB := 0, 0 // Difference in the number of lines before the current island.
+	for _, is := range islands(ha, hb) {
+		xlines, dA, err := applyHunks(orig, is.pos, is.end, is.a)
+		if err != nil {
+			return zero, fmt.Errorf("failed to apply patchA: %v", err)
+		}
+		ylines, dB, err := applyHunks(orig, is.pos, is.end, is.b)
+		if err != nil {
+			return zero, fmt.Errorf("failed to apply patchB: %v", err)
+		}
+		// The patched lines are taken from complete files, only the last line can be missing its
+		// newline.
+		d := diffSplitLines(xlines, ylines, cfg)
+		d.xMissingNewline, d.yMissingNewline = lastMissingNewline(xlines), lastMissingNewline(ylines)
+		for _, h := range hunks[string](d, cfg) {
+			writeInterdiffHunk(&b, h, is.pos+deltaA, is.pos+deltaB)
+		}
+		deltaA += dA
+		deltaB += dB
+	}
+	return b.Build(), nil
+}
+
+// patchHunk is a hunk of a unified diff. The hunk replaces the lines old starting at line pos
+// (zero-based) of the original file with the lines new.
+type patchHunk struct {
+	pos      int
+	old, new []string
+}
+
+// parsePatch parses the hunks of a unified diff.
+func parsePatch(patch string) ([]patchHunk, error) {
+	var out []patchHunk
+	lines := slices.Collect(strings.Lines(patch))
+	for i := 0; i < len(lines); {
+		if !strings.HasPrefix(lines[i], "@@ ") {
+			i++ // skip everything outside of hunks
+			continue
+		}
+		h, n, err := parseHunk[string](lines[i:])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+1, err)
+		}
+		ph := patchHunk{pos: h.LineNoX}
+		for _, e := range h.Edits {
+			if e.Op != diff.Insert {
+				ph.old = append(ph.old, e.Line)
+			}
+			if e.Op != diff.Delete {
+				ph.new = append(ph.new, e.Line)
+			}
+		}
+		out = append(out, ph)
+		i += n
+	}
+	return out, nil
+}
+
+// island is a contiguous region of the original file, original[pos:end], that is covered by
+// hunks. The content of all lines in an island is known.
+type island struct {
+	pos, end int
+	a, b     []patchHunk // Hunks of patchA and patchB in this island.
+}
+
+// islands groups the hunks of both patches into islands.
+func islands(ha, hb []patchHunk) []island {
+	type tagged struct {
+		patchHunk
+		fromB bool
+	}
+	var all []tagged
+	for _, h := range ha {
+		all = append(all, tagged{h, false})
+	}
+	for _, h := range hb {
+		all = append(all, tagged{h, true})
+	}
+	slices.SortStableFunc(all, func(a, b tagged) int { return a.pos - b.pos })
+
+	var out []island
+	for _, h := range all {
+		end := h.pos + len(h.old)
+		if len(out) == 0 || h.pos > out[len(out)-1].end {
+			out = append(out, island{pos: h.pos, end: end})
+		}
+		is := &out[len(out)-1]
+		is.end = max(is.end, end)
+		if h.fromB {
+			is.b = append(is.b, h.patchHunk)
+		} else {
+			is.a = append(is.a, h.patchHunk)
+		}
+	}
+	return out
+}
+
+// applyHunks applies hunks to original[pos:end] and returns the resulting lines and the
+// difference in the number of lines.
+func applyHunks(orig map[int]string, pos, end int, hunks []patchHunk) (lines []string, delta int, err error) {
+	for _, h := range hunks {
+		if h.pos < pos {
+			return nil, 0, fmt.Errorf("overlapping hunks at line %d", h.pos+1)
+		}
+		for ; pos < h.pos; pos++ {
+			lines = append(lines, orig[pos])
+		}
+		lines = append(lines, h.new...)
+		pos += len(h.old)
+		delta += len(h.new) - len(h.old)
+	}
+	for ; pos < end; pos++ {
+		lines = append(lines, orig[pos])
+	}
+	return lines, delta, nil
+}
+
+// lastMissingNewline returns the index of the last line if it doesn't end in a newline or -1.
+func lastMissingNewline(lines []string) int {
+	if n := len(lines); n > 0 && !strings.HasSuffix(lines[n-1], "\n") {
+		return n - 1
+	}
+	return -1
+}
+
+func writeInterdiffHunk[T string | []byte](b *byteview.Builder[T], h Hunk[string], offsetX, offsetY int) {
+	fmt.Fprintf(b, "@@ -%d,%d +%d,%d @@\n", h.LineNoX+offsetX+1, h.EndLineNoX-h.LineNoX, h.LineNoY+offsetY+1, h.EndLineNoY-h.LineNoY)
+	for _, e := range h.Edits {
+		switch e.Op {
+		case diff.Match:
+			b.WriteString(prefixMatch)
+		case diff.Delete:
+			b.WriteString(prefixDelete)
+		case diff.Insert:
+			b.WriteString(prefixInsert)
+		}
+		b.WriteString(e.Line)
+		if h.missingNewline(e) {
+			writeMissingNewline(b, "\n")
+		}
+	}
+}
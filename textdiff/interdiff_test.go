@@ -0,0 +1,169 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"znkr.io/diff"
+)
+
+func TestInterdiff(t *testing.T) {
+	// numbered returns n numbered lines, lines in replace are replaced with the given content.
+	numbered := func(n int, replace map[int]string) string {
+		var b strings.Builder
+		for i := 1; i <= n; i++ {
+			if r, ok := replace[i]; ok {
+				b.WriteString(r)
+				continue
+			}
+			fmt.Fprintf(&b, "line %d\n", i)
+		}
+		return b.String()
+	}
+	orig := numbered(30, nil)
+	v1 := numbered(30, map[int]string{5: "line 5 changed\n"})
+	v2 := numbered(30, map[int]string{5: "line 5 changed\nextra line\n"})
+	v3 := numbered(30, map[int]string{5: "line 5 changed\nextra line\n", 20: "line 20 changed\n"})
+
+	tests := []struct {
+		name           string
+		patchA, patchB string
+		want           string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:   "identical",
+			patchA: Unified(orig, v1),
+			patchB: Unified(orig, v1),
+		},
+		{
+			name:   "extra-line",
+			patchA: Unified(orig, v1),
+			patchB: Unified(orig, v2),
+			want: `@@ -3,6 +3,7 @@
+ line 3
+ line 4
+ line 5 changed
++extra line
+ line 6
+ line 7
+ line 8
+`,
+		},
+		{
+			name:   "extra-hunk-different-context",
+			patchA: Unified(orig, v1),
+			patchB: Unified(orig, v3, diff.Context(1)),
+			want: `@@ -3,6 +3,7 @@
+ line 3
+ line 4
+ line 5 changed
++extra line
+ line 6
+ line 7
+ line 8
+@@ -19,3 +20,3 @@
+ line 19
+-line 20
++line 20 changed
+ line 21
+`,
+		},
+		{
+			name:   "file-headers",
+			patchA: "diff --git a/f b/f\n--- a/f\n+++ b/f\n" + Unified(orig, v1),
+			patchB: "--- a/f\n+++ b/f\n" + Unified(orig, v2),
+			want: `@@ -3,6 +3,7 @@
+ line 3
+ line 4
+ line 5 changed
++extra line
+ line 6
+ line 7
+ line 8
+`,
+		},
+		{
+			name:   "missing-newline",
+			patchA: Unified("a\nb\nc", "a\nB\nc"),
+			patchB: Unified("a\nb\nc", "a\nB\nC"),
+			want: `@@ -1,3 +1,3 @@
+ a
+ B
+-c
+\ No newline at end of file
++C
+\ No newline at end of file
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Interdiff(tt.patchA, tt.patchB)
+			if err != nil {
+				t.Fatalf("Interdiff(...) failed: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Interdiff(...) result is different (-want, +got):\n%s", diff)
+			}
+
+			gotBytes, err := Interdiff([]byte(tt.patchA), []byte(tt.patchB))
+			if err != nil {
+				t.Fatalf("Interdiff[[]byte](...) failed: %v", err)
+			}
+			if string(gotBytes) != got {
+				t.Errorf("Interdiff[[]byte](...) = %q, want %q", gotBytes, got)
+			}
+		})
+	}
+}
+
+func TestInterdiffError(t *testing.T) {
+	tests := []struct {
+		name           string
+		patchA, patchB string
+	}{
+		{
+			name:   "invalid-header",
+			patchA: "@@ -1 +1 @\n-a\n+b\n",
+		},
+		{
+			name:   "invalid-line",
+			patchA: "@@ -1 +1 @@\n-a\n*b\n",
+		},
+		{
+			name:   "truncated-hunk",
+			patchB: "@@ -1,2 +1,2 @@\n-a\n+b\n",
+		},
+		{
+			name:   "different-originals",
+			patchA: "@@ -1 +1 @@\n-a\n+b\n",
+			patchB: "@@ -1 +1 @@\n-x\n+b\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Interdiff(tt.patchA, tt.patchB); err == nil {
+				t.Errorf("Interdiff(...) succeeded, want error")
+			}
+		})
+	}
+}
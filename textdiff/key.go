@@ -0,0 +1,86 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"znkr.io/diff/internal/byteview"
+	"znkr.io/diff/internal/config"
+)
+
+// HunksKey is like [Hunks], but compares the keys returned by key instead of the lines themselves.
+// Two lines match if their keys are equal, e.g., if they only differ in a trailing comment that key
+// strips. The output always contains the original lines, for matching lines the line from x.
+//
+// The line passed to key includes its newline, if any, and must not be modified. key must be
+// deterministic, i.e., return the same key for the same line every time it's called. Otherwise,
+// the result is undefined. Options that transform lines like [NormalizeUnicode] or [IgnoreMatching]
+// are applied to the key.
+//
+// The following options are supported: [diff.Context], [diff.Minimal], [diff.PreferLongMatches],
+// [diff.Fast], [diff.AutoFast], [diff.Parallel], [diff.PairedOrdering], [IndentHeuristic],
+// [NormalizeUnicode], [IgnoreMatching], [IgnoreComments], [IgnoreReorderedBlocks], [AnchorOn],
+// [FuzzyLines], [BlockContext], [FunctionContext], [MaxHunkLines], [MinHunkChanges]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func HunksKey[T string | []byte](x, y T, key func(line T) string, opts ...Option) []Hunk[T] {
+	cfg := config.FromOptions(opts, config.Algorithm|lineFlags|hunkFlags)
+	addKey(&cfg, key)
+	return hunks[T](diffLines(x, y, cfg), cfg)
+}
+
+// EditsKey is like [Edits], but compares lines by the keys returned by key, see [HunksKey].
+//
+// The following options are supported: [diff.Minimal], [diff.PreferLongMatches], [diff.Fast],
+// [diff.AutoFast], [diff.Parallel], [IndentHeuristic], [NormalizeUnicode], [IgnoreMatching],
+// [IgnoreComments], [IgnoreReorderedBlocks], [AnchorOn], [FuzzyLines]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func EditsKey[T string | []byte](x, y T, key func(line T) string, opts ...Option) []Edit[T] {
+	cfg := config.FromOptions(opts, config.Algorithm|lineFlags)
+	addKey(&cfg, key)
+	d := diffLines(x, y, cfg)
+	return edits[T](d.x, d.y, d.rx, d.ry, d.normalized)
+}
+
+// UnifiedKey is like [Unified], but compares lines by the keys returned by key, see [HunksKey].
+//
+// The following options are supported: [diff.Context], [diff.Minimal], [diff.PreferLongMatches],
+// [diff.Fast], [diff.AutoFast], [diff.Parallel], [diff.PairedOrdering], [IndentHeuristic],
+// [NormalizeUnicode], [IgnoreMatching], [IgnoreComments], [IgnoreReorderedBlocks], [AnchorOn],
+// [FuzzyLines], [BlockContext], [FunctionContext], [MaxHunkLines], [MinHunkChanges],
+// [MaxLineWidth], [SanitizeInvalidUTF8], [ShowWhitespace], [TerminalColors], [OutputNewline],
+// [Labels], [IndexHeader], [HunkStatsInHeader]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func UnifiedKey[T string | []byte](x, y T, key func(line T) string, opts ...Option) T {
+	cfg := config.FromOptions(opts, config.Algorithm|lineFlags|hunkFlags|renderFlags)
+	addKey(&cfg, key)
+	return unified[T](diffLines(x, y, cfg), cfg)
+}
+
+// addKey makes key the first transformation of the line key in cfg, so that key always receives
+// the original line.
+func addKey[T string | []byte](cfg *config.Config, key func(line T) string) {
+	rest := cfg.LineKey
+	cfg.LineKey = func(line string) string {
+		return key(byteview.UnsafeAs[T](byteview.From(line)))
+	}
+	if rest != nil {
+		cfg.AddLineKey(rest)
+	}
+}
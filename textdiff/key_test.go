@@ -0,0 +1,85 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"znkr.io/diff"
+)
+
+// stripComment removes a trailing "//" comment and the whitespace before it.
+func stripComment(line string) string {
+	if i := strings.Index(line, "//"); i >= 0 {
+		return strings.TrimRight(line[:i], " \t")
+	}
+	return strings.TrimRight(line, "\n")
+}
+
+func TestKey(t *testing.T) {
+	x := "a := 1 // one\nb := 2\nc := 3\n"
+	y := "a := 1 // uno\nb := 4\nc := 3 // three\n"
+
+	t.Run("Edits", func(t *testing.T) {
+		want := []Edit[string]{
+			{diff.Match, 0, 0, "a := 1 // one\n", "a := 1 // uno\n", false},
+			{diff.Delete, 1, -1, "b := 2\n", "", false},
+			{diff.Insert, -1, 1, "b := 4\n", "", false},
+			{diff.Match, 2, 2, "c := 3\n", "c := 3 // three\n", false},
+		}
+		got := EditsKey(x, y, stripComment)
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("EditsKey(...) result is different [-want,+got]:\n%s", diff)
+		}
+	})
+
+	t.Run("Unified", func(t *testing.T) {
+		want := "@@ -1,3 +1,3 @@\n a := 1 // one\n-b := 2\n+b := 4\n c := 3\n"
+		got := UnifiedKey([]byte(x), []byte(y), func(line []byte) string { return stripComment(string(line)) })
+		if diff := cmp.Diff(want, string(got)); diff != "" {
+			t.Errorf("UnifiedKey(...) result is different [-want,+got]:\n%s", diff)
+		}
+	})
+
+	t.Run("Hunks", func(t *testing.T) {
+		got := HunksKey(x, y, stripComment, diff.Context(0))
+		if len(got) != 1 || got[0].LineNoX != 1 || got[0].EndLineNoX != 2 {
+			t.Errorf("HunksKey(...) = %v, want a single hunk for line 1", got)
+		}
+	})
+
+	t.Run("KeyBeforeOptions", func(t *testing.T) {
+		// The key must receive the original line, the normalization is applied to its result.
+		var seen []string
+		key := func(line string) string {
+			seen = append(seen, line)
+			return stripComment(line)
+		}
+		var keys []string
+		ignore := func(line string) bool {
+			keys = append(keys, line)
+			return false
+		}
+		EditsKey("a // x\n", "b\n", key, IgnoreMatching(ignore))
+		if want := []string{"a // x\n", "b\n"}; !cmp.Equal(want, seen) {
+			t.Errorf("key called with %q, want %q", seen, want)
+		}
+		if want := []string{"a", "b"}; !cmp.Equal(want, keys) {
+			t.Errorf("IgnoreMatching predicate called with %q, want %q", keys, want)
+		}
+	})
+}
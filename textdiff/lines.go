@@ -0,0 +1,90 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"znkr.io/diff/internal/byteview"
+	"znkr.io/diff/internal/config"
+)
+
+// The functions in this file compare inputs that have already been split into lines. Every element
+// is treated as one line, including its newline character if it has one. The inputs are not split
+// again and no "\ No newline at end of file" markers are produced, it's up to the caller to handle
+// missing newlines.
+
+// HunksLines is like [Hunks], but compares x and y which are already split into lines.
+//
+// The following options are supported: [diff.Context], [diff.Minimal], [diff.PreferLongMatches],
+// [diff.Fast], [diff.AutoFast], [diff.Parallel], [diff.PairedOrdering], [IndentHeuristic],
+// [NormalizeUnicode], [IgnoreMatching], [IgnoreComments], [IgnoreReorderedBlocks], [AnchorOn],
+// [FuzzyLines], [BlockContext], [FunctionContext], [MaxHunkLines], [MinHunkChanges]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func HunksLines(x, y []string, opts ...Option) []Hunk[string] {
+	cfg := config.FromOptions(opts, config.Algorithm|lineFlags|hunkFlags)
+	d := diffSplitLines(x, y, cfg)
+	return hunks[string](d, cfg)
+}
+
+// EditsLines is like [Edits], but compares x and y which are already split into lines.
+//
+// The following options are supported: [diff.Minimal], [diff.PreferLongMatches], [diff.Fast],
+// [diff.AutoFast], [diff.Parallel], [IndentHeuristic], [NormalizeUnicode], [IgnoreMatching],
+// [IgnoreComments], [IgnoreReorderedBlocks], [AnchorOn], [FuzzyLines]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func EditsLines(x, y []string, opts ...Option) []Edit[string] {
+	cfg := config.FromOptions(opts, config.Algorithm|lineFlags)
+	d := diffSplitLines(x, y, cfg)
+	return edits[string](d.x, d.y, d.rx, d.ry, d.normalized)
+}
+
+// UnifiedLines is like [Unified], but compares x and y which are already split into lines.
+//
+// The following options are supported: [diff.Context], [diff.Minimal], [diff.PreferLongMatches],
+// [diff.Fast], [diff.AutoFast], [diff.Parallel], [diff.PairedOrdering], [IndentHeuristic],
+// [NormalizeUnicode], [IgnoreMatching], [IgnoreComments], [IgnoreReorderedBlocks], [AnchorOn],
+// [FuzzyLines], [BlockContext], [FunctionContext], [MaxHunkLines], [MinHunkChanges],
+// [MaxLineWidth], [SanitizeInvalidUTF8], [ShowWhitespace], [TerminalColors], [OutputNewline],
+// [Labels], [IndexHeader], [HunkStatsInHeader]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func UnifiedLines(x, y []string, opts ...Option) string {
+	cfg := config.FromOptions(opts, config.Algorithm|lineFlags|hunkFlags|renderFlags)
+	return unified[string](diffSplitLines(x, y, cfg), cfg)
+}
+
+// diffSplitLines compares lines that are already split.
+func diffSplitLines(x, y []string, cfg config.Config) lineDiff {
+	d := lineDiff{
+		x:               views(x),
+		y:               views(y),
+		xMissingNewline: -1,
+		yMissingNewline: -1,
+	}
+	d.compare(cfg)
+	return d
+}
+
+func views(lines []string) []byteview.ByteView {
+	out := make([]byteview.ByteView, len(lines))
+	for i, line := range lines {
+		out[i] = byteview.From(line)
+	}
+	return out
+}
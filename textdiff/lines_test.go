@@ -0,0 +1,62 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLines(t *testing.T) {
+	for _, tt := range parseTests(t) {
+		if !bytes.HasSuffix(tt.x, []byte{'\n'}) || !bytes.HasSuffix(tt.y, []byte{'\n'}) {
+			continue // missing newlines are handled differently
+		}
+		t.Run(tt.name, func(t *testing.T) {
+			x, y := string(tt.x), string(tt.y)
+			xlines := slices.Collect(strings.Lines(x))
+			ylines := slices.Collect(strings.Lines(y))
+
+			for _, st := range tt.subtests {
+				if st.render != nil {
+					continue // only Unified output is supported
+				}
+				t.Run(st.name, func(t *testing.T) {
+					if got := UnifiedLines(xlines, ylines, st.opts...); got != string(st.want) {
+						t.Errorf("UnifiedLines(...) result is different from Unified(...) [-want,+got]:\n%s", cmp.Diff(string(st.want), got))
+					}
+				})
+			}
+
+			// cmp.Diff is slow on large inputs, only use it to report differences.
+			if want, got := Hunks(x, y), HunksLines(xlines, ylines); !slices.EqualFunc(want, got, equalHunks) {
+				t.Errorf("HunksLines(...) result is different from Hunks(...) [-want,+got]:\n%s", cmp.Diff(want, got))
+			}
+			if want, got := Edits(x, y), EditsLines(xlines, ylines); !slices.Equal(want, got) {
+				t.Errorf("EditsLines(...) result is different from Edits(...) [-want,+got]:\n%s", cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func equalHunks(a, b Hunk[string]) bool {
+	return a.LineNoX == b.LineNoX && a.EndLineNoX == b.EndLineNoX &&
+		a.LineNoY == b.LineNoY && a.EndLineNoY == b.EndLineNoY &&
+		slices.Equal(a.Edits, b.Edits)
+}
@@ -0,0 +1,209 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"iter"
+	"slices"
+
+	"znkr.io/diff/internal/byteview"
+	"znkr.io/diff/internal/config"
+)
+
+// Merge3 merges the changes from base to x and from base to y line by line.
+//
+// Changes that don't overlap are merged cleanly. If both x and y change the same region of base
+// differently (this includes one side deleting a region that the other side modifies), the
+// region is marked with conflict markers:
+//
+//	<<<<<<< x-label
+//	lines from x
+//	=======
+//	lines from y
+//	>>>>>>> y-label
+//
+// The labels are empty by default and can be configured using [ConflictLabels]. If both x and y
+// make the same change, it's merged without a conflict.
+//
+// The following options are supported: [diff.Minimal], [diff.PreferLongMatches], [diff.Fast],
+// [diff.AutoFast], [diff.Parallel], [ConflictLabels]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func Merge3[T string | []byte](base, x, y T, opts ...Option) (merged T, hadConflict bool) {
+	cfg := config.FromOptions(opts, config.Algorithm|config.ConflictLabels)
+	return merge3(base, x, y, cfg, false)
+}
+
+// Diff3 is like [Merge3], but includes the lines from base in every conflict, like "diff3 -m" or
+// git's "diff3" conflict style:
+//
+//	<<<<<<< x-label
+//	lines from x
+//	|||||||
+//	lines from base
+//	=======
+//	lines from y
+//	>>>>>>> y-label
+//
+// Like [Merge3] and unlike "diff3 -m", identical changes in x and y are merged without a conflict.
+//
+// The following options are supported: [diff.Minimal], [diff.PreferLongMatches], [diff.Fast],
+// [diff.AutoFast], [diff.Parallel], [ConflictLabels]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func Diff3[T string | []byte](base, x, y T, opts ...Option) (merged T, hadConflict bool) {
+	cfg := config.FromOptions(opts, config.Algorithm|config.ConflictLabels)
+	return merge3(base, x, y, cfg, true)
+}
+
+// merge3 implements [Merge3] and [Diff3]. If withBase is set, conflicts include the lines from
+// base.
+func merge3[T string | []byte](base, x, y T, cfg config.Config, withBase bool) (merged T, hadConflict bool) {
+	dx := diffLines(base, x, cfg)
+	dy := diffLines(base, y, cfg)
+	blines, xlines, ylines := dx.x, dx.y, dy.y
+
+	var b byteview.Builder[T]
+	for c := range merge3Chunks(dx, dy) {
+		bc, xc, yc := blines[c.o0:c.o1], xlines[c.a0:c.a1], ylines[c.b0:c.b1]
+		switch {
+		case c.stable:
+			writeLines(&b, bc)
+		case slices.Equal(xc, bc):
+			writeLines(&b, yc) // only y changed
+		case slices.Equal(yc, bc):
+			writeLines(&b, xc) // only x changed
+		case slices.Equal(xc, yc):
+			writeLines(&b, xc) // both made the same change
+		default:
+			hadConflict = true
+			writeMarker(&b, "<<<<<<<", cfg.ConflictLabelX)
+			writeConflictLines(&b, xc)
+			if withBase {
+				writeMarker(&b, "|||||||", "")
+				writeConflictLines(&b, bc)
+			}
+			writeMarker(&b, "=======", "")
+			writeConflictLines(&b, yc)
+			writeMarker(&b, ">>>>>>>", cfg.ConflictLabelY)
+		}
+	}
+	return b.Build(), hadConflict
+}
+
+// merge3Chunk describes a region base[o0:o1], x[a0:a1], and y[b0:b1]. A chunk is stable if all
+// three regions match.
+type merge3Chunk struct {
+	o0, o1 int
+	a0, a1 int
+	b0, b1 int
+	stable bool
+}
+
+// merge3Chunks splits the inputs of a three-way merge into stable and unstable chunks.
+//
+// This follows the diff3 algorithm as described in Sanjeev Khanna, Keshav Kunal, and Benjamin C.
+// Pierce, "A Formal Investigation of Diff3": A stable chunk consists of lines in base that are
+// matched in both x and y, an unstable chunk covers everything between two stable chunks.
+func merge3Chunks(dx, dy lineDiff) iter.Seq[merge3Chunk] {
+	return func(yield func(merge3Chunk) bool) {
+		mx, my := matches(dx), matches(dy)
+		n, nx, ny := len(dx.x), len(dx.y), len(dy.y)
+		o, a, b := 0, 0, 0
+		for o < n || a < nx || b < ny {
+			// Stable chunk.
+			c := merge3Chunk{o0: o, a0: a, b0: b, stable: true}
+			for o < n && mx[o] == a && my[o] == b {
+				o++
+				a++
+				b++
+			}
+			if o > c.o0 {
+				c.o1, c.a1, c.b1 = o, a, b
+				if !yield(c) {
+					return
+				}
+			}
+
+			// Unstable chunk, it ends at the next line in base that's matched in both x and y.
+			c = merge3Chunk{o0: o, a0: a, b0: b}
+			c.o1, c.a1, c.b1 = n, nx, ny
+			for i := o; i < n; i++ {
+				if mx[i] >= 0 && my[i] >= 0 {
+					c.o1, c.a1, c.b1 = i, mx[i], my[i]
+					break
+				}
+			}
+			if c.o1 > c.o0 || c.a1 > c.a0 || c.b1 > c.b0 {
+				if !yield(c) {
+					return
+				}
+			}
+			o, a, b = c.o1, c.a1, c.b1
+		}
+	}
+}
+
+// matches returns the index of the matching line in d.y for every line in d.x or -1 if there is
+// no matching line.
+func matches(d lineDiff) []int {
+	m := make([]int, len(d.x))
+	n, k := len(d.x), len(d.y)
+	for s, t := 0, 0; s < n || t < k; {
+		switch {
+		case s < n && d.rx[s]:
+			m[s] = -1
+			s++
+		case t < k && d.ry[t]:
+			t++
+		default:
+			m[s] = t
+			s++
+			t++
+		}
+	}
+	return m
+}
+
+func writeLines[T string | []byte](b *byteview.Builder[T], lines []byteview.ByteView) {
+	for _, line := range lines {
+		b.WriteByteView(line)
+	}
+}
+
+// writeConflictLines writes lines to b, making sure that the last line ends in a newline so that
+// the following conflict marker is on its own line.
+func writeConflictLines[T string | []byte](b *byteview.Builder[T], lines []byteview.ByteView) {
+	writeLines(b, lines)
+	if len(lines) > 0 && !hasNewline(lines[len(lines)-1]) {
+		b.WriteString("\n")
+	}
+}
+
+func writeMarker[T string | []byte](b *byteview.Builder[T], marker, label string) {
+	b.WriteString(marker)
+	if label != "" {
+		b.WriteString(" ")
+		b.WriteString(label)
+	}
+	b.WriteString("\n")
+}
+
+func hasNewline(line byteview.ByteView) bool {
+	s := byteview.UnsafeAs[string](line)
+	return len(s) > 0 && s[len(s)-1] == '\n'
+}
@@ -0,0 +1,193 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"znkr.io/diff"
+)
+
+func TestMerge3(t *testing.T) {
+	tests := []struct {
+		name         string
+		base, x, y   string
+		opts         []diff.Option
+		want         string
+		wantConflict bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "unchanged",
+			base: "a\nb\nc\n",
+			x:    "a\nb\nc\n",
+			y:    "a\nb\nc\n",
+			want: "a\nb\nc\n",
+		},
+		{
+			name: "only-x-changed",
+			base: "a\nb\nc\n",
+			x:    "a\nB\nc\n",
+			y:    "a\nb\nc\n",
+			want: "a\nB\nc\n",
+		},
+		{
+			name: "non-overlapping",
+			base: "a\nb\nc\nd\ne\n",
+			x:    "A\nb\nc\nd\ne\n",
+			y:    "a\nb\nc\nd\nE\nf\n",
+			want: "A\nb\nc\nd\nE\nf\n",
+		},
+		{
+			name: "same-change",
+			base: "a\nb\nc\n",
+			x:    "a\nB\nc\n",
+			y:    "a\nB\nc\n",
+			want: "a\nB\nc\n",
+		},
+		{
+			name:         "conflict",
+			base:         "a\nb\nc\n",
+			x:            "a\nx\nc\n",
+			y:            "a\ny\nc\n",
+			want:         "a\n<<<<<<<\nx\n=======\ny\n>>>>>>>\nc\n",
+			wantConflict: true,
+		},
+		{
+			name:         "delete-modify",
+			base:         "a\nb\nc\n",
+			x:            "a\nc\n",
+			y:            "a\nB\nc\n",
+			want:         "a\n<<<<<<<\n=======\nB\n>>>>>>>\nc\n",
+			wantConflict: true,
+		},
+		{
+			name:         "labels",
+			base:         "a\nb\nc\n",
+			x:            "a\nx\nc\n",
+			y:            "a\ny\nc\n",
+			opts:         []diff.Option{ConflictLabels("ours", "theirs")},
+			want:         "a\n<<<<<<< ours\nx\n=======\ny\n>>>>>>> theirs\nc\n",
+			wantConflict: true,
+		},
+		{
+			name:         "missing-newline",
+			base:         "a\nb",
+			x:            "a\nx",
+			y:            "a\ny",
+			want:         "a\n<<<<<<<\nx\n=======\ny\n>>>>>>>\n",
+			wantConflict: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotConflict := Merge3(tt.base, tt.x, tt.y, tt.opts...)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Merge3(...) result is different:\ngot:\n%s\nwant:\n%s\ndiff [-want,+got]:\n%s", got, tt.want, diff)
+			}
+			if gotConflict != tt.wantConflict {
+				t.Errorf("Merge3(...) conflict = %v, want %v", gotConflict, tt.wantConflict)
+			}
+
+			gotBytes, _ := Merge3([]byte(tt.base), []byte(tt.x), []byte(tt.y), tt.opts...)
+			if string(gotBytes) != got {
+				t.Errorf("Merge3[[]byte](...) = %q, want %q", gotBytes, got)
+			}
+		})
+	}
+}
+
+func TestDiff3(t *testing.T) {
+	// The expected results match the output of "diff3 -m x base y" up to the labels. Unlike diff3,
+	// a missing newline at the end of a conflict never merges a line with the following marker.
+	tests := []struct {
+		name         string
+		base, x, y   string
+		opts         []diff.Option
+		want         string
+		wantConflict bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "non-overlapping",
+			base: "a\nb\nc\nd\ne\n",
+			x:    "A\nb\nc\nd\ne\n",
+			y:    "a\nb\nc\nd\nE\nf\n",
+			want: "A\nb\nc\nd\nE\nf\n",
+		},
+		{
+			name:         "conflict",
+			base:         "a\nb\nc\n",
+			x:            "a\nx\nc\n",
+			y:            "a\ny\nc\n",
+			want:         "a\n<<<<<<<\nx\n|||||||\nb\n=======\ny\n>>>>>>>\nc\n",
+			wantConflict: true,
+		},
+		{
+			name:         "delete-modify",
+			base:         "a\nb\nc\n",
+			x:            "a\nc\n",
+			y:            "a\nB\nc\n",
+			want:         "a\n<<<<<<<\n|||||||\nb\n=======\nB\n>>>>>>>\nc\n",
+			wantConflict: true,
+		},
+		{
+			name:         "insert-insert",
+			base:         "a\nc\n",
+			x:            "a\nx\nc\n",
+			y:            "a\ny\nc\n",
+			want:         "a\n<<<<<<<\nx\n|||||||\n=======\ny\n>>>>>>>\nc\n",
+			wantConflict: true,
+		},
+		{
+			name:         "labels",
+			base:         "a\nb\nc\n",
+			x:            "a\nx\nc\n",
+			y:            "a\ny\nc\n",
+			opts:         []diff.Option{ConflictLabels("ours", "theirs")},
+			want:         "a\n<<<<<<< ours\nx\n|||||||\nb\n=======\ny\n>>>>>>> theirs\nc\n",
+			wantConflict: true,
+		},
+		{
+			name:         "missing-newline",
+			base:         "a\nb",
+			x:            "a\nx",
+			y:            "a\ny",
+			want:         "a\n<<<<<<<\nx\n|||||||\nb\n=======\ny\n>>>>>>>\n",
+			wantConflict: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotConflict := Diff3(tt.base, tt.x, tt.y, tt.opts...)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Diff3(...) result is different:\ngot:\n%s\nwant:\n%s\ndiff [-want,+got]:\n%s", got, tt.want, diff)
+			}
+			if gotConflict != tt.wantConflict {
+				t.Errorf("Diff3(...) conflict = %v, want %v", gotConflict, tt.wantConflict)
+			}
+
+			gotBytes, _ := Diff3([]byte(tt.base), []byte(tt.x), []byte(tt.y), tt.opts...)
+			if string(gotBytes) != got {
+				t.Errorf("Diff3[[]byte](...) = %q, want %q", gotBytes, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"fmt"
+
+	"znkr.io/diff/internal/byteview"
+	"znkr.io/diff/internal/config"
+	"znkr.io/diff/internal/rvecs"
+)
+
+// UnifiedMinimal is like [Unified] without context lines and with a compact range token instead of
+// a hunk header for every change. The output is meant for tools that only care about the changed
+// lines:
+//
+//	-8,1+8,1
+//	-line 8
+//	+line 8 changed
+//	-51,0+51,1
+//	+inserted
+//
+// A range token "-l,s+l,s" describes the lines in x and y that are replaced by the change. The
+// position l is the one-based number of the first line in the range. For an empty range, it's the
+// number the first line would have, i.e., the lines are inserted before line l.
+//
+// Note: The output is not a valid patch and can't be applied with patch.
+//
+// The following options are supported: [diff.Minimal], [diff.PreferLongMatches], [diff.Fast],
+// [diff.AutoFast], [diff.Parallel], [diff.PairedOrdering], [IndentHeuristic], [NormalizeUnicode],
+// [IgnoreMatching], [IgnoreComments], [IgnoreReorderedBlocks], [AnchorOn], [FuzzyLines]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func UnifiedMinimal[T string | []byte](x, y T, opts ...Option) T {
+	cfg := config.FromOptions(opts, config.Algorithm|lineFlags|config.PairedOrdering)
+	cfg.Context = 0
+	d := diffLines(x, y, cfg)
+	rx, ry := d.rx, d.ry
+
+	var b byteview.Builder[T]
+	for h := range d.hunkSeq(cfg) {
+		fmt.Fprintf(&b, "-%d,%d+%d,%d\n", h.S0+1, h.S1-h.S0, h.T0+1, h.T1-h.T0)
+		write := func(prefix string, line byteview.ByteView, missing bool) {
+			b.WriteString(prefix)
+			writeLine(&b, line, &cfg)
+			if missing {
+				writeMissingNewline(&b, "\n")
+			}
+		}
+		for r := range rvecs.Runs(rx, ry, h, cfg.PairedOrdering) {
+			for i := range r.N {
+				s, t := r.S+i, r.T+i
+				switch r.Op {
+				case rvecs.Delete:
+					write(prefixDelete, d.x[s], s == d.xMissingNewline)
+				case rvecs.Insert:
+					write(prefixInsert, d.y[t], t == d.yMissingNewline)
+				}
+			}
+		}
+	}
+	return b.Build()
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"fmt"
+	"io"
+
+	"znkr.io/diff"
+	"znkr.io/diff/internal/byteview"
+	"znkr.io/diff/internal/config"
+)
+
+// UnifiedNoContextCopy is like [Unified], but doesn't copy the context lines into the output.
+// Instead, every run of context lines is replaced by a range marker "=l,s" that refers to s lines
+// in x starting at the one-based line number l:
+//
+//	@@ -5,7 +5,7 @@
+//	=5,3
+//	-line 8
+//	+line 8 changed
+//	=9,3
+//
+// This reduces the output size considerably for inputs with long lines or a large context. The
+// context lines can be reconstructed from x.
+//
+// Note: The output is not a valid patch and can't be applied with patch.
+//
+// The following options are supported: [diff.Context], [diff.Minimal], [diff.PreferLongMatches],
+// [diff.Fast], [diff.AutoFast], [diff.Parallel], [diff.PairedOrdering], [IndentHeuristic],
+// [NormalizeUnicode], [IgnoreMatching], [IgnoreComments], [IgnoreReorderedBlocks], [AnchorOn],
+// [FuzzyLines], [BlockContext], [FunctionContext], [MaxHunkLines], [MinHunkChanges]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func UnifiedNoContextCopy[T string | []byte](x, y T, opts ...Option) T {
+	cfg := config.FromOptions(opts, config.Algorithm|lineFlags|hunkFlags)
+	return format(diffLines(x, y, cfg), cfg, noContextFormatter[T]{})
+}
+
+// noContextFormatter formats hunks for [UnifiedNoContextCopy].
+type noContextFormatter[T string | []byte] struct{}
+
+func (noContextFormatter[T]) FormatHunk(w io.Writer, _ int, h Hunk[T]) {
+	fmt.Fprintf(w, "@@ -%d,%d +%d,%d @@\n", h.LineNoX+1, h.EndLineNoX-h.LineNoX, h.LineNoY+1, h.EndLineNoY-h.LineNoY)
+	for i := 0; i < len(h.Edits); {
+		e := h.Edits[i]
+		if e.Op == diff.Match {
+			n := matchRunLen(h.Edits[i:])
+			fmt.Fprintf(w, "=%d,%d\n", e.LineNoX+1, n)
+			i += n
+			continue
+		}
+		io.WriteString(w, e.Op.Prefix())
+		io.WriteString(w, byteview.UnsafeAs[string](byteview.From(e.Line)))
+		if h.missingNewline(e) {
+			writeMissingNewline(w, "\n")
+		}
+		i++
+	}
+}
+
+func (noContextFormatter[T]) size(_ int, h Hunk[T]) int {
+	n := len("@@ -, +, @@\n")
+	n += numDigits(h.LineNoX+1) + numDigits(h.EndLineNoX-h.LineNoX) + numDigits(h.LineNoY+1) + numDigits(h.EndLineNoY-h.LineNoY)
+	for i := 0; i < len(h.Edits); {
+		e := h.Edits[i]
+		if e.Op == diff.Match {
+			k := matchRunLen(h.Edits[i:])
+			n += len("=,\n") + numDigits(e.LineNoX+1) + numDigits(k)
+			i += k
+			continue
+		}
+		n += len(e.Op.Prefix()) + len(e.Line)
+		if h.missingNewline(e) {
+			n += missingNewlineLen("\n")
+		}
+		i++
+	}
+	return n
+}
+
+// matchRunLen returns the number of matches at the start of edits.
+func matchRunLen[T string | []byte](edits []Edit[T]) int {
+	n := 0
+	for n < len(edits) && edits[n].Op == diff.Match {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"fmt"
+
+	"znkr.io/diff"
+	"znkr.io/diff/internal/byteview"
+	"znkr.io/diff/internal/config"
+)
+
+// UnifiedNumbered is like [Unified], but prefixes every line with its line numbers in x and y,
+// similar to the gutter in tools like bat or delta:
+//
+//	@@ -9,3 +9,3 @@
+//	 9  9  unchanged
+//	10    -deleted
+//	   10 +inserted
+//
+// Line numbers are one-based and right-aligned, the column width is derived from the largest line
+// number in the output. The line number of the side that doesn't apply is left blank.
+//
+// Note: The output is for display only, it's not a valid patch and can't be applied with patch.
+//
+// The following options are supported: [diff.Context], [diff.Minimal], [diff.PreferLongMatches],
+// [diff.Fast], [diff.AutoFast], [diff.Parallel], [diff.PairedOrdering], [IndentHeuristic],
+// [NormalizeUnicode], [IgnoreMatching], [IgnoreComments], [IgnoreReorderedBlocks], [AnchorOn],
+// [FuzzyLines], [BlockContext], [FunctionContext], [MaxHunkLines], [MinHunkChanges],
+// [MaxLineWidth], [SanitizeInvalidUTF8], [ShowWhitespace]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func UnifiedNumbered[T string | []byte](x, y T, opts ...Option) T {
+	cfg := config.FromOptions(opts, config.Algorithm|lineFlags|hunkFlags|config.MaxLineWidth|config.SanitizeInvalidUTF8|config.ShowWhitespace)
+	d := diffLines(x, y, cfg)
+	hunks := hunks[T](d, cfg)
+	if len(hunks) == 0 {
+		var zero T
+		return zero
+	}
+
+	// The last hunk contains the largest line numbers.
+	last := hunks[len(hunks)-1]
+	wx, wy := numDigits(last.EndLineNoX), numDigits(last.EndLineNoY)
+
+	var b byteview.Builder[T]
+	for _, h := range hunks {
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", h.LineNoX+1, h.EndLineNoX-h.LineNoX, h.LineNoY+1, h.EndLineNoY-h.LineNoY)
+		for _, e := range h.Edits {
+			prefix := prefixMatch
+			switch e.Op {
+			case diff.Match:
+				fmt.Fprintf(&b, "%*d %*d ", wx, e.LineNoX+1, wy, e.LineNoY+1)
+			case diff.Delete:
+				fmt.Fprintf(&b, "%*d %*s ", wx, e.LineNoX+1, wy, "")
+				prefix = prefixDelete
+			case diff.Insert:
+				fmt.Fprintf(&b, "%*s %*d ", wx, "", wy, e.LineNoY+1)
+				prefix = prefixInsert
+			}
+			b.WriteString(prefix)
+			writeLine(&b, byteview.From(e.Line), &cfg)
+			if h.missingNewline(e) {
+				writeMissingNewline(&b, "\n")
+			}
+		}
+	}
+	return b.Build()
+}
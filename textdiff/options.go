@@ -15,7 +15,12 @@
 package textdiff
 
 import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
 	"znkr.io/diff/internal/config"
+	"znkr.io/diff/internal/indentheuristic"
 	"znkr.io/diff/textdiff/color"
 )
 
@@ -27,6 +32,19 @@ import (
 // [options defined in the diff package]: https://pkg.go.dev/znkr.io/diff#Option
 type Option = config.Option
 
+// Sets of options supported by the comparison functions in this package, in addition to
+// [config.Algorithm].
+const (
+	// lineFlags are the options that control which lines are compared and how.
+	lineFlags = config.IndentHeuristic | config.NormalizeUnicode | config.IgnoreMatching | config.IgnoreComments | config.IgnoreReorderedBlocks | config.AnchorOn | config.FuzzyLines
+
+	// hunkFlags are the options that control how hunks are formed.
+	hunkFlags = config.Context | config.PairedOrdering | config.BlockContext | config.FunctionContext | config.MaxHunkLines | config.MinHunkChanges
+
+	// renderFlags are the options that control how unified diffs are rendered.
+	renderFlags = config.MaxLineWidth | config.SanitizeInvalidUTF8 | config.ShowWhitespace | config.TerminalColors | config.OutputNewline | config.Labels | config.IndexHeader | config.HunkStatsInHeader
+)
+
 // IndentHeuristic applies a heuristic to make diffs easier to read by improving the placement of
 // edit boundaries.
 //
@@ -40,6 +58,397 @@ func IndentHeuristic() Option {
 	}
 }
 
+// IndentHeuristicWeights are the weights that the indent heuristic uses to score the possible
+// positions of a group of changes. Lower scores are better. See [IndentHeuristicTuning].
+type IndentHeuristicWeights = indentheuristic.Weights
+
+// DefaultIndentHeuristicWeights returns the weights used by [IndentHeuristic]. They are the same
+// weights that git uses.
+func DefaultIndentHeuristicWeights() IndentHeuristicWeights {
+	return indentheuristic.DefaultWeights
+}
+
+// IndentHeuristicTuning is like [IndentHeuristic], but uses the provided weights instead of the
+// default weights. This can improve the placement of edit boundaries for languages where the
+// defaults, which are tuned for typical source code, don't work well.
+//
+// Start from [DefaultIndentHeuristicWeights] and adjust individual weights.
+func IndentHeuristicTuning(w IndentHeuristicWeights) Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.IndentHeuristic = true
+		cfg.IndentHeuristicWeights = &w
+		return config.IndentHeuristic
+	}
+}
+
+// IndentHeuristicExcept is like [IndentHeuristic], but doesn't use indentation to place groups of
+// changed lines that all satisfy pred. This is useful for inputs that mix code with prose, e.g.
+// Markdown, where the heuristic helps with the code but can pick odd boundaries in paragraphs.
+// Excluded groups are shifted down as far as possible instead, like git does without the indent
+// heuristic.
+//
+// It can be combined with [IndentHeuristicTuning]. The line passed to pred includes its newline,
+// if any.
+func IndentHeuristicExcept(pred func(line string) bool) Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.IndentHeuristic = true
+		cfg.IndentHeuristicExcept = pred
+		return config.IndentHeuristic
+	}
+}
+
+// NormalizeUnicode compares lines after normalizing them to the given Unicode normalization form.
+//
+// Canonically equivalent text can be encoded in different ways, e.g. "é" can be a single code point
+// (NFC) or an "e" followed by a combining accent (NFD). Without normalization, such lines are
+// reported as changed. The normalization only affects the comparison, the output always contains
+// the original lines. For matching lines, the line from x is used.
+//
+// Note: This option uses [golang.org/x/text/unicode/norm] for normalization.
+func NormalizeUnicode(form norm.Form) Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.AddLineKey(form.String)
+		return config.NormalizeUnicode
+	}
+}
+
+// IgnoreMatching treats all lines for which pred returns true as equal to each other, e.g. lines
+// with generated timestamps or build IDs. Such a line in x is reported as a match with such a line
+// in y, even if the two lines differ. Like all matching lines, these lines are rendered from x.
+//
+// The line passed to pred includes its newline, if any. If [NormalizeUnicode] is used before
+// IgnoreMatching, pred receives the normalized line.
+func IgnoreMatching(pred func(line string) bool) Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.AddLineKey(func(line string) string {
+			if pred(line) {
+				return ignoredLineKey
+			}
+			return line
+		})
+		return config.IgnoreMatching
+	}
+}
+
+// ignoredLineKey is the line key for all lines ignored by [IgnoreMatching]. It contains a newline
+// in the middle, so it can't be confused with a real line.
+const ignoredLineKey = "\n<ignored>\n"
+
+// IgnoreComments compares lines without their comments, e.g. to ignore changed comments in
+// configuration files. Everything from the first occurrence of prefix onward is removed from a line
+// before it's compared, as well as any spaces and tabs in front of the comment. The output always
+// contains the original lines. For matching lines, the line from x is used.
+//
+// Lines that only consist of a comment, possibly indented, are treated as equal to each other, but
+// not to empty lines. The prefix is searched for without taking the syntax of the input into
+// account, e.g., a prefix in a string literal also starts a comment. Use [IgnoreCommentsFunc] to
+// find comments differently.
+func IgnoreComments(prefix string) Option {
+	return IgnoreCommentsFunc(func(line string) int {
+		return strings.Index(line, prefix)
+	})
+}
+
+// IgnoreCommentsFunc is like [IgnoreComments], but uses start to find the comment in a line: start
+// returns the index of the first byte of the comment or -1 if the line doesn't contain a comment.
+//
+// The line passed to start includes its newline, if any. If [NormalizeUnicode] is used before
+// IgnoreCommentsFunc, start receives the normalized line.
+func IgnoreCommentsFunc(start func(line string) int) Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.AddLineKey(func(line string) string {
+			i := start(line)
+			if i < 0 {
+				return line
+			}
+			_, eol := splitLineEnding(line)
+			code := strings.TrimRight(line[:i], " \t")
+			if strings.TrimLeft(code, " \t") == "" {
+				return commentLineKey + eol
+			}
+			return code + eol
+		})
+		return config.IgnoreComments
+	}
+}
+
+// commentLineKey is the line key for all lines that only consist of a comment, see
+// [IgnoreComments]. Like [ignoredLineKey], it contains a newline in the middle, so it can't be
+// confused with a real line.
+const commentLineKey = "\n<comment>\n"
+
+// IgnoreReorderedBlocks treats blocks of lines that only differ in the order of their lines as
+// unchanged, e.g. import blocks that were reordered by a formatter. A block is a maximal run of
+// consecutive lines for which pred returns true. If a block in x and a block in y contain the same
+// lines, only in a different order, the lines of both blocks are reported as matches. Like all
+// matching lines, these lines are rendered from x.
+//
+// Blocks that don't contain exactly the same lines are compared as usual, i.e., adding a single
+// line to a reordered block reports the whole reordering. The line passed to pred includes its
+// newline, if any. If [NormalizeUnicode] or [IgnoreMatching] are used too, blocks are compared
+// after applying them.
+func IgnoreReorderedBlocks(pred func(line string) bool) Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.ReorderedBlock = pred
+		return config.IgnoreReorderedBlocks
+	}
+}
+
+// AnchorOn forces lines for which pred returns true to be treated as matching lines if they are
+// equal and unique in both inputs, e.g. function signatures. The lines between two anchors are
+// compared independently, that is, no change ever crosses an anchor.
+//
+// Lines selected by pred that aren't unique in x and y, e.g. because they are only present in one
+// of the inputs, are simply not used as anchors. If anchors appear in a different order in x and
+// y, the longest sequence of anchors that appears in the same order in both inputs is used.
+//
+// The line passed to pred includes its newline, if any. If [NormalizeUnicode] is used too, lines
+// are compared after normalization.
+func AnchorOn(pred func(line string) bool) Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.Anchor = pred
+		return config.AnchorOn
+	}
+}
+
+// FuzzyLines treats lines that are almost identical as matching lines. This is useful for inputs
+// where lines differ only in small details that are irrelevant for the comparison, e.g., timestamps
+// in log files. Matching lines are always rendered from x.
+//
+// After the regular line-by-line comparison, every deleted line is compared with the inserted line
+// at the same position in the same change, i.e., the first deleted line with the first inserted
+// line, and so on. If the similarity of both lines is at least threshold, the lines are treated as
+// matching lines. The similarity is 2*M/(N+K), where M is the number of matching runes and N and K
+// are the number of runes in the two lines. The threshold must be in the range (0, 1].
+//
+// Performance impact: Each comparison is a rune-by-rune diff of two lines. Because only paired
+// lines are compared, the number of comparisons is bounded by the number of changed lines.
+func FuzzyLines(threshold float64) Option {
+	if !(threshold > 0 && threshold <= 1) { // also rejects NaN
+		panic(fmt.Sprintf("textdiff.FuzzyLines: threshold %v is not in the range (0, 1]", threshold))
+	}
+	return func(cfg *config.Config) config.Flag {
+		cfg.FuzzyLinesThreshold = threshold
+		return config.FuzzyLines
+	}
+}
+
+// MarkReindent sets [Edit.Reindent] for changed lines that only differ in their leading
+// whitespace. This allows to hide changes that only reindent a block, e.g., in reformatting
+// changes.
+//
+// Deleted and inserted lines are paired like in [ClassifyEdits]: Within a run of deletions that is
+// directly followed by a run of insertions, the first deleted line is paired with the first
+// inserted line, the second with the second, and so on. If the lines of a pair only differ in
+// spaces and tabs at the beginning of the line, both edits are marked. The edits remain a
+// deletion and an insertion.
+func MarkReindent() Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.MarkReindent = true
+		return config.MarkReindent
+	}
+}
+
+// BlockContext expands the context of every hunk to the innermost block that encloses all its
+// changes, e.g. to always show a complete JSON object or YAML mapping around a change. This is a
+// presentation aid, the comparison isn't affected.
+//
+// openClose reports how a line changes the nesting depth: +1 if it opens a block (e.g. a line
+// ending in "{"), -1 if it closes a block (e.g. a line starting with "}"), and 0 otherwise. The
+// nesting depth is computed for the lines in x. A hunk is expanded to start at the line that opens
+// the block and end at the line that closes it. Changes at the top level, i.e. outside of any
+// block, use the regular context from [diff.Context]. Hunks that overlap after expansion are
+// merged.
+//
+// The line passed to openClose includes its newline, if any.
+func BlockContext(openClose func(line string) int) Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.BlockContext = openClose
+		return config.BlockContext
+	}
+}
+
+// FunctionContext expands the context of every hunk to the functions that enclose its changes,
+// like git diff --function-context. This is a presentation aid, the comparison isn't affected.
+//
+// isFuncStart reports whether a line starts a function, it's called for the lines in x. A hunk is
+// expanded backwards to the function start line in front of its first change and forwards to the
+// line before the next function start line after its last change, or the end of x. Changes in
+// front of the first function start line use the regular context from [diff.Context]. Hunks that
+// overlap after expansion are merged. If used together with [BlockContext], hunks are expanded to
+// cover both ranges.
+//
+// The line passed to isFuncStart includes its newline, if any.
+func FunctionContext(isFuncStart func(line string) bool) Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.FunctionContext = isFuncStart
+		return config.FunctionContext
+	}
+}
+
+// MaxHunkLines splits hunks with more than n edits into multiple consecutive hunks with at most n
+// edits each, e.g. to make a replaced file easier to review. Every edit, including every line of
+// context, counts towards n. Values < 1 are treated as 1.
+//
+// Hunks are preferably split right after a matching line, so that the next hunk starts with a
+// change. Hunks without such a line, e.g. a replaced file, are split after exactly n edits. The
+// resulting hunks have correct headers, but the hunks at a split don't have any context on the
+// side of the split and directly follow each other. Every hunk contains at least one change: The
+// leading and trailing context of a split hunk is kept together with the first and last change,
+// even if the hunk then has more than n edits. Since the context around a split is asymmetric,
+// patch tools may need to allow fuzz or reject the output, so this option is intended for
+// reviewing diffs rather than for patches.
+//
+// [diff.MaxHunks] limits the number of hunks after splitting.
+func MaxHunkLines(n int) Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.MaxHunkLines = max(1, n)
+		return config.MaxHunkLines
+	}
+}
+
+// MinHunkChanges omits hunks with fewer than n inserted and deleted lines in total, e.g. to
+// summarize a diff without trivial changes. Context lines don't count towards n. Values < 1 are
+// treated as 1, i.e., no hunk is omitted.
+//
+// Omitted hunks are left out of the output entirely, their changes are not shown as context in
+// neighboring hunks. Hunks are filtered before they are split with [MaxHunkLines] and before they
+// are limited with [diff.MaxHunks].
+func MinHunkChanges(n int) Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.MinHunkChanges = max(1, n)
+		return config.MinHunkChanges
+	}
+}
+
+// MaxLineWidth truncates lines in the output of [Unified] that are longer than n runes (not
+// counting the newline). Truncated lines end in "…" instead. Shorter lines are left untouched.
+//
+// This is useful to present diffs of inputs with very long lines, like minified code or base64
+// encoded data. The comparison always uses the full lines.
+//
+// Note: Truncated output is for display only, it can't be applied as a patch anymore.
+func MaxLineWidth(n int) Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.MaxLineWidth = max(1, n)
+		return config.MaxLineWidth
+	}
+}
+
+// SanitizeInvalidUTF8 replaces every invalid UTF-8 byte sequence in the output of [Unified] with
+// the replacement character U+FFFD. The comparison always uses the original bytes, so lines that
+// only differ in invalid bytes are still reported as changed.
+//
+// Note: Sanitized output is for display only, it can't be applied as a patch anymore.
+func SanitizeInvalidUTF8() Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.SanitizeInvalidUTF8 = true
+		return config.SanitizeInvalidUTF8
+	}
+}
+
+// ShowWhitespace makes whitespace changes visible in the output of [Unified]: Tabs in the
+// indentation and at the end of a line are rendered as "→" and spaces at the end of a line are
+// rendered as "·". Whitespace within the content of a line and spaces in the indentation are left
+// untouched. The comparison always uses the original lines.
+//
+// Note: Output with visible whitespace is for display only, it can't be applied as a patch anymore.
+func ShowWhitespace() Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.ShowWhitespace = true
+		return config.ShowWhitespace
+	}
+}
+
+// HunkStatsInHeader appends the number of inserted and deleted lines of a hunk to its header in
+// the output of [Unified], e.g. "@@ -1,4 +1,5 @@ (+2 -1)". This is useful for tools that only parse
+// the hunk headers, like CI annotations.
+//
+// Note: The extra text isn't compatible with git, which uses the space after the header for the
+// function name, and the output is for display only. Don't use it for patches.
+func HunkStatsInHeader() Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.HunkStatsInHeader = true
+		return config.HunkStatsInHeader
+	}
+}
+
+// HunkSeparator sets the line that [UnifiedCompact] prints between two hunks. The default is
+// "...".
+func HunkSeparator(sep string) Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.HunkSeparator = sep
+		return config.HunkSeparator
+	}
+}
+
+// BinaryDetection skips the line by line comparison if x or y looks binary, i.e., if it contains a
+// NUL byte in its first 8000 bytes. This is the same heuristic git uses. For binary inputs that
+// differ, [Unified] only prints the line "Binary files differ" (with [Labels]: "Binary files X and
+// Y differ") and [Hunks] returns a single hunk that covers both inputs, has no edits, and has
+// [Hunk.Binary] set. Binary inputs that are equal have no differences, like text inputs.
+func BinaryDetection() Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.BinaryDetection = true
+		return config.BinaryDetection
+	}
+}
+
+// OutputNewline sets the line separator that [Unified] uses for the lines it generates itself,
+// i.e., hunk headers and "\ No newline at end of file" markers. Lines from the input keep their own
+// line endings. nl must be either "\n" (the default) or "\r\n", any other value panics.
+func OutputNewline(nl string) Option {
+	if nl != "\n" && nl != "\r\n" {
+		panic(fmt.Sprintf("textdiff.OutputNewline: invalid newline %q", nl))
+	}
+	return func(cfg *config.Config) config.Flag {
+		cfg.OutputNewline = nl
+		return config.OutputNewline
+	}
+}
+
+// Labels makes [Unified] print a file header with the labels for x and y, e.g. "a/file.txt" and
+// "b/file.txt":
+//
+//	--- a/file.txt
+//	+++ b/file.txt
+//
+// The header is omitted if there are no differences.
+func Labels(x, y string) Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.LabelX = x
+		cfg.LabelY = y
+		return config.Labels
+	}
+}
+
+// IndexHeader makes [Unified] print a git style "index" line with the hashes of x and y and the
+// file mode in front of the file header (see [Labels]):
+//
+//	index abc1234..def5678 100644
+//
+// If mode is empty, it's omitted. Like the file header, the line is omitted if there are no
+// differences.
+func IndexHeader(oldHash, newHash, mode string) Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.IndexOld = oldHash
+		cfg.IndexNew = newHash
+		cfg.IndexMode = mode
+		return config.IndexHeader
+	}
+}
+
+// ConflictLabels sets the labels that [Merge3] uses for conflict markers, e.g. "ours" and
+// "theirs". The label for x is used for the "<<<<<<<" marker and the label for y for the
+// ">>>>>>>" marker.
+func ConflictLabels(x, y string) Option {
+	return func(cfg *config.Config) config.Flag {
+		cfg.ConflictLabelX = x
+		cfg.ConflictLabelY = y
+		return config.ConflictLabels
+	}
+}
+
 // TerminalColors uses ANSI escape codes to color the output of [Unified].
 //
 // By default, the colors try to emulate git's color scheme, but the colors can be overridden using
@@ -0,0 +1,110 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"fmt"
+
+	"znkr.io/diff/internal/config"
+)
+
+// OutlineEntry summarizes a contiguous changed region of a diff without the content of the lines.
+type OutlineEntry struct {
+	StartX, EndX int // Start and end line in x (zero-based).
+	StartY, EndY int // Start and end line in y (zero-based).
+	Insertions   int // Number of inserted lines.
+	Deletions    int // Number of deleted lines.
+}
+
+// Outline compares the lines in x and y and returns a summary of every changed region. This is
+// useful to get an overview of the changes between large inputs.
+//
+// Every entry corresponds to a hunk returned by [Hunks] with the same options: The positions are the
+// same and the counts correspond to the number of insertions and deletions in the hunk. Use
+// [diff.Context] to control how close changes must be to end up in the same region.
+//
+// The following options are supported: [diff.Context], [diff.Minimal], [diff.PreferLongMatches],
+// [diff.Fast], [diff.AutoFast], [diff.Parallel], [IndentHeuristic], [NormalizeUnicode],
+// [IgnoreMatching], [IgnoreComments], [IgnoreReorderedBlocks], [AnchorOn], [FuzzyLines],
+// [BlockContext], [FunctionContext], [MaxHunkLines], [MinHunkChanges], [diff.MaxHunks]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func Outline[T string | []byte](x, y T, opts ...Option) []OutlineEntry {
+	cfg := config.FromOptions(opts, config.Algorithm|lineFlags|hunkFlags&^config.PairedOrdering|config.MaxHunks)
+	d := diffLines(x, y, cfg)
+	var out []OutlineEntry
+	for hunk := range d.hunkSeq(cfg) {
+		out = append(out, OutlineEntry{
+			StartX:     hunk.S0,
+			EndX:       hunk.S1,
+			StartY:     hunk.T0,
+			EndY:       hunk.T1,
+			Insertions: countChanges(d.ry[hunk.T0:hunk.T1]),
+			Deletions:  countChanges(d.rx[hunk.S0:hunk.S1]),
+		})
+	}
+	return out
+}
+
+// countChanges returns the number of true values in r.
+func countChanges(r []bool) int {
+	n := 0
+	for _, v := range r {
+		if v {
+			n++
+		}
+	}
+	return n
+}
+
+// HunkHeader contains the ranges of a hunk as printed in the header of a unified diff, i.e.,
+// "@@ -StartX,LenX +StartY,LenY @@". The start lines are one-based.
+type HunkHeader struct {
+	StartX, LenX int
+	StartY, LenY int
+}
+
+// String formats h like the header of a hunk in a unified diff, without a trailing newline.
+func (h HunkHeader) String() string {
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.StartX, h.LenX, h.StartY, h.LenY)
+}
+
+// HunkHeaders compares the lines in x and y and returns the headers of all hunks, e.g., to build a
+// table of contents for a diff. The headers are the same as the ones printed by [Unified] with the
+// same options, but the content of the hunks is never built. This is cheaper than [Hunks] if only
+// the ranges are needed.
+//
+// The following options are supported: [diff.Context], [diff.Minimal], [diff.PreferLongMatches],
+// [diff.Fast], [diff.AutoFast], [diff.Parallel], [IndentHeuristic], [NormalizeUnicode],
+// [IgnoreMatching], [IgnoreComments], [IgnoreReorderedBlocks], [AnchorOn], [FuzzyLines],
+// [BlockContext], [FunctionContext], [MaxHunkLines], [MinHunkChanges], [diff.MaxHunks]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func HunkHeaders[T string | []byte](x, y T, opts ...Option) []HunkHeader {
+	cfg := config.FromOptions(opts, config.Algorithm|lineFlags|hunkFlags&^config.PairedOrdering|config.MaxHunks)
+	d := diffLines(x, y, cfg)
+	var out []HunkHeader
+	for hunk := range d.hunkSeq(cfg) {
+		out = append(out, HunkHeader{
+			StartX: hunk.S0 + 1,
+			LenX:   hunk.S1 - hunk.S0,
+			StartY: hunk.T0 + 1,
+			LenY:   hunk.T1 - hunk.T0,
+		})
+	}
+	return out
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"znkr.io/diff"
+)
+
+func TestOutline(t *testing.T) {
+	x := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\n"
+	y := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nl\nm\nn\n"
+	got := Outline(x, y, diff.Context(1))
+	want := []OutlineEntry{
+		{StartX: 0, EndX: 3, StartY: 0, EndY: 3, Insertions: 1, Deletions: 1},
+		{StartX: 9, EndX: 12, StartY: 9, EndY: 13, Insertions: 2, Deletions: 1},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Outline(...) result is different (-want, +got):\n%s", diff)
+	}
+	if got := Outline(x, x); got != nil {
+		t.Errorf("Outline(x, x) = %v, want nil", got)
+	}
+}
+
+func TestOutlineMatchesHunks(t *testing.T) {
+	for _, tt := range parseTests(t) {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			for _, opts := range [][]Option{
+				nil,
+				{diff.Context(0)},
+				{IndentHeuristic()},
+				{diff.MaxHunks(2)},
+			} {
+				var want []OutlineEntry
+				for _, h := range Hunks(tt.x, tt.y, opts...) {
+					e := OutlineEntry{
+						StartX: h.LineNoX,
+						EndX:   h.EndLineNoX,
+						StartY: h.LineNoY,
+						EndY:   h.EndLineNoY,
+					}
+					for _, edit := range h.Edits {
+						switch edit.Op {
+						case diff.Insert:
+							e.Insertions++
+						case diff.Delete:
+							e.Deletions++
+						}
+					}
+					want = append(want, e)
+				}
+				got := Outline(tt.x, tt.y, opts...)
+				if diff := cmp.Diff(want, got); diff != "" {
+					t.Errorf("Outline(...) with %d options doesn't match Hunks(...) (-want, +got):\n%s", len(opts), diff)
+				}
+			}
+		})
+	}
+}
+
+func TestHunkHeaders(t *testing.T) {
+	x := "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\n"
+	y := "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nl\nm\nn\n"
+	got := HunkHeaders(x, y, diff.Context(1))
+	want := []HunkHeader{
+		{StartX: 1, LenX: 3, StartY: 1, LenY: 3},
+		{StartX: 10, LenX: 3, StartY: 10, LenY: 4},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("HunkHeaders(...) result is different (-want, +got):\n%s", diff)
+	}
+	if got, want := got[1].String(), "@@ -10,3 +10,4 @@"; got != want {
+		t.Errorf("HunkHeader.String() = %q, want %q", got, want)
+	}
+	if got := HunkHeaders(x, x); got != nil {
+		t.Errorf("HunkHeaders(x, x) = %v, want nil", got)
+	}
+}
+
+func TestHunkHeadersMatchUnified(t *testing.T) {
+	for _, tt := range parseTests(t) {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			for _, opts := range [][]Option{
+				nil,
+				{diff.Context(0)},
+				{IndentHeuristic()},
+				{diff.Context(10)},
+			} {
+				var want []string
+				for _, line := range strings.SplitAfter(string(Unified(tt.x, tt.y, opts...)), "\n") {
+					if strings.HasPrefix(line, "@@ ") {
+						want = append(want, strings.TrimSuffix(line, "\n"))
+					}
+				}
+				var got []string
+				for _, h := range HunkHeaders(tt.x, tt.y, opts...) {
+					got = append(got, h.String())
+				}
+				if diff := cmp.Diff(want, got); diff != "" {
+					t.Errorf("HunkHeaders(...) with %d options doesn't match Unified(...) (-want, +got):\n%s", len(opts), diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"znkr.io/diff/internal/byteview"
+)
+
+// reorderedBlockKeys replaces the keys of blocks of lines that are reordered between x and y, see
+// [IgnoreReorderedBlocks]. The keys of every such block are sorted and tagged with the block's
+// contents, so they match the keys of every block with the same lines, but nothing else.
+//
+// xkeys and ykeys are not modified, if a key needs to change, a copy is returned instead.
+func reorderedBlockKeys(x, y, xkeys, ykeys []byteview.ByteView, pred func(line string) bool) ([]byteview.ByteView, []byteview.ByteView) {
+	xblocks := blocks(x, pred)
+	yblocks := blocks(y, pred)
+	if len(xblocks) == 0 || len(yblocks) == 0 {
+		return xkeys, ykeys
+	}
+
+	// Identify blocks by their sorted keys.
+	sorted := func(keys []byteview.ByteView, block [2]int) []string {
+		out := make([]string, 0, block[1]-block[0])
+		for _, k := range keys[block[0]:block[1]] {
+			out = append(out, byteview.UnsafeAs[string](k))
+		}
+		slices.Sort(out)
+		return out
+	}
+	id := func(sorted []string) string {
+		var b strings.Builder
+		for _, k := range sorted {
+			// Length prefixed, because keys can contain any byte.
+			b.WriteString(strconv.Itoa(len(k)))
+			b.WriteByte(':')
+			b.WriteString(k)
+		}
+		return b.String()
+	}
+	xids := make([]string, len(xblocks))
+	inX := make(map[string]bool)
+	for i, block := range xblocks {
+		xids[i] = id(sorted(xkeys, block))
+		inX[xids[i]] = true
+	}
+	yids := make([]string, len(yblocks))
+	inY := make(map[string]bool)
+	for i, block := range yblocks {
+		yids[i] = id(sorted(ykeys, block))
+		inY[yids[i]] = true
+	}
+
+	// Give every distinct block a number to tag its keys with.
+	tags := make(map[string]string)
+	replace := func(keys []byteview.ByteView, blocks [][2]int, ids []string, other map[string]bool) []byteview.ByteView {
+		out, cloned := keys, false
+		for i, block := range blocks {
+			if !other[ids[i]] {
+				continue
+			}
+			tag, ok := tags[ids[i]]
+			if !ok {
+				tag = reorderedLineKeyPrefix + strconv.Itoa(len(tags)) + "\n"
+				tags[ids[i]] = tag
+			}
+			if !cloned {
+				out, cloned = slices.Clone(keys), true
+			}
+			for j, k := range sorted(keys, block) {
+				out[block[0]+j] = byteview.From(tag + k)
+			}
+		}
+		return out
+	}
+	return replace(xkeys, xblocks, xids, inY), replace(ykeys, yblocks, yids, inX)
+}
+
+// reorderedLineKeyPrefix is the prefix of the line keys of reordered blocks. Like
+// [ignoredLineKey], it starts with a newline, so it can't be confused with a real line.
+const reorderedLineKeyPrefix = "\n<reordered>"
+
+// blocks returns the maximal runs of lines for which pred returns true as half-open intervals.
+func blocks(lines []byteview.ByteView, pred func(line string) bool) [][2]int {
+	var out [][2]int
+	for i := 0; i < len(lines); {
+		if !pred(byteview.UnsafeAs[string](lines[i])) {
+			i++
+			continue
+		}
+		start := i
+		for i < len(lines) && pred(byteview.UnsafeAs[string](lines[i])) {
+			i++
+		}
+		out = append(out, [2]int{start, i})
+	}
+	return out
+}
@@ -0,0 +1,51 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"strings"
+
+	"znkr.io/diff/internal/byteview"
+	"znkr.io/diff/internal/impl"
+)
+
+// SetDiffLines compares the lines in x and y as multisets, i.e., ignoring the order of lines. It
+// returns the lines that only occur in x, the lines that only occur in y, and the lines that occur
+// in both, see [diff.SetDiff] for details. This is useful to compare, e.g., configurations or
+// command output where the order of lines is irrelevant.
+//
+// Lines are compared without their newline character, a missing newline at the end of x or y
+// doesn't make a difference. The returned lines include their newline character, if they have one.
+// Common lines are taken from x.
+func SetDiffLines[T string | []byte](x, y T) (onlyX, onlyY, common []T) {
+	xlines, _ := byteview.SplitLines(byteview.From(x))
+	ylines, _ := byteview.SplitLines(byteview.From(y))
+	ox, oy, c := impl.SetDiff(xlines, ylines, func(line byteview.ByteView) string {
+		return strings.TrimSuffix(byteview.UnsafeAs[string](line), "\n")
+	})
+	return asLines[T](ox), asLines[T](oy), asLines[T](c)
+}
+
+// asLines converts views to lines of type T. It returns nil if views is empty.
+func asLines[T string | []byte](views []byteview.ByteView) []T {
+	if len(views) == 0 {
+		return nil
+	}
+	out := make([]T, len(views))
+	for i, v := range views {
+		out[i] = byteview.UnsafeAs[T](v)
+	}
+	return out
+}
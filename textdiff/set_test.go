@@ -0,0 +1,74 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSetDiffLines(t *testing.T) {
+	tests := []struct {
+		name                     string
+		x, y                     string
+		wantX, wantY, wantCommon []string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:       "reordered",
+			x:          "ok pkg/a\nok pkg/b\nFAIL pkg/c\n",
+			y:          "FAIL pkg/c\nok pkg/a\nok pkg/b\n",
+			wantCommon: []string{"ok pkg/a\n", "ok pkg/b\n", "FAIL pkg/c\n"},
+		},
+		{
+			name:  "disjoint",
+			x:     "a\nb\n",
+			y:     "c\nd\n",
+			wantX: []string{"a\n", "b\n"},
+			wantY: []string{"c\n", "d\n"},
+		},
+		{
+			name:       "duplicates",
+			x:          "a\na\nb\na\n",
+			y:          "b\na\nc\n",
+			wantX:      []string{"a\n", "a\n"},
+			wantY:      []string{"c\n"},
+			wantCommon: []string{"a\n", "b\n"},
+		},
+		{
+			name:       "missing-newline",
+			x:          "a\nb",
+			y:          "b\na\n",
+			wantCommon: []string{"a\n", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			onlyX, onlyY, common := SetDiffLines(tt.x, tt.y)
+			if diff := cmp.Diff(tt.wantX, onlyX); diff != "" {
+				t.Errorf("SetDiffLines(...) onlyX is different [-want,+got]:\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantY, onlyY); diff != "" {
+				t.Errorf("SetDiffLines(...) onlyY is different [-want,+got]:\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantCommon, common); diff != "" {
+				t.Errorf("SetDiffLines(...) common is different [-want,+got]:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"znkr.io/diff/internal/byteview"
+	"znkr.io/diff/internal/config"
+)
+
+// SideBySide compares the lines in x and y and lays them out in two columns, similar to the output
+// of "diff -y":
+//
+//	unchanged   unchanged
+//	deleted   <
+//	          > inserted
+//	before    | after
+//
+// Every line of both inputs is part of the output. The marker between the columns is "|" for a
+// deleted line that's paired with an inserted line, "<" for a deleted line without a partner, ">"
+// for an inserted line without a partner, and a space for matching lines. Deleted and inserted
+// lines are paired like in [ClassifyEdits].
+//
+// Both columns are width runes wide (values < 1 are treated as 1). Longer lines are truncated
+// without any marker, shorter lines in the left column are padded with spaces. Tabs aren't
+// expanded and count as a single rune. Line endings are removed from the lines, every row ends in
+// "\n", and rows don't have trailing spaces after the marker.
+//
+// Note: The output is for display only, it's not a valid patch and can't be applied with patch.
+//
+// The following options are supported: [diff.Minimal], [diff.PreferLongMatches], [diff.Fast],
+// [diff.AutoFast], [diff.Parallel], [IndentHeuristic], [NormalizeUnicode], [IgnoreMatching],
+// [IgnoreComments], [IgnoreReorderedBlocks], [AnchorOn], [FuzzyLines], [SanitizeInvalidUTF8],
+// [ShowWhitespace]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func SideBySide[T string | []byte](x, y T, width int, opts ...Option) T {
+	cfg := config.FromOptions(opts, config.Algorithm|lineFlags|config.SanitizeInvalidUTF8|config.ShowWhitespace)
+	d := diffLines(x, y, cfg)
+	width = max(1, width)
+	column := func(line byteview.ByteView) string {
+		content, _ := splitLineEnding(displayLine(line, &cfg))
+		head, _ := truncate(content, width)
+		return head
+	}
+
+	var b byteview.Builder[T]
+	row := func(left string, marker byte, right string) {
+		r := left + strings.Repeat(" ", width-utf8.RuneCountInString(left)) + " " + string(marker)
+		if right != "" {
+			r += " " + right
+		} else {
+			r = strings.TrimRight(r, " ")
+		}
+		b.WriteString(r)
+		b.WriteString("\n")
+	}
+
+	n, m := len(d.x), len(d.y)
+	for s, t := 0, 0; s < n || t < m; {
+		// Pair the runs of deletions and insertions.
+		ndel, nins := 0, 0
+		for s+ndel < n && d.rx[s+ndel] {
+			ndel++
+		}
+		for t+nins < m && d.ry[t+nins] {
+			nins++
+		}
+		for i := range max(ndel, nins) {
+			switch {
+			case i < ndel && i < nins:
+				row(column(d.x[s+i]), '|', column(d.y[t+i]))
+			case i < ndel:
+				row(column(d.x[s+i]), '<', "")
+			default:
+				row("", '>', column(d.y[t+i]))
+			}
+		}
+		s += ndel
+		t += nins
+		for s < n && t < m && !d.rx[s] && !d.ry[t] {
+			row(column(d.x[s]), ' ', column(d.y[t]))
+			s++
+			t++
+		}
+	}
+	return b.Build()
+}
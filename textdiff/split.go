@@ -0,0 +1,244 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"znkr.io/diff"
+	"znkr.io/diff/internal/byteview"
+)
+
+// FileDiff is the diff of a single file in a multi-file diff, see [SplitFiles].
+type FileDiff[T string | []byte] struct {
+	OldName, NewName string    // Names of the file before and after the change.
+	Binary           bool      // Set if the diff describes a binary file, Hunks is empty then.
+	Hunks            []Hunk[T] // Hunks of the diff.
+}
+
+// SplitFiles splits a diff of multiple files, e.g., the output of "git diff", into one [FileDiff]
+// for every file.
+//
+// A new file starts with a "diff --git" line or with a "---" and "+++" file header that follows
+// the hunks of a previous file. The names are taken from the file headers if present and from the
+// "diff --git" line otherwise. For git diffs, the "a/" and "b/" prefixes are removed from the names.
+// The name of a file that is created or deleted is "/dev/null" on the respective side. Binary files
+// are reported with the Binary flag set and without hunks. All other lines outside of hunks, e.g.,
+// "index" lines or commit messages, are ignored.
+//
+// Hunk headers are parsed like the ones written by diff and git: An empty range starts after the
+// given line. The lines in the returned hunks reference data.
+//
+// An error is returned if a hunk is malformed or if a hunk appears before the first file header.
+func SplitFiles[T string | []byte](data T) ([]FileDiff[T], error) {
+	lines := strings.SplitAfter(byteview.UnsafeAs[string](byteview.From(data)), "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	var out []FileDiff[T]
+	git := false       // Set if the current file started with "diff --git".
+	hasHeader := false // Set if the current file has a "---" and "+++" header.
+	for i := 0; i < len(lines); {
+		line := strings.TrimSuffix(lines[i], "\n")
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			oldName, newName := parseGitHeader(line)
+			out = append(out, FileDiff[T]{OldName: oldName, NewName: newName})
+			git, hasHeader = true, false
+			i++
+
+		case strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ "):
+			if len(out) == 0 || hasHeader || len(out[len(out)-1].Hunks) > 0 {
+				out = append(out, FileDiff[T]{})
+				git = false
+			}
+			f := &out[len(out)-1]
+			f.OldName = parseFileName(line[len("--- "):], git, "a/")
+			f.NewName = parseFileName(strings.TrimSuffix(lines[i+1], "\n")[len("+++ "):], git, "b/")
+			hasHeader = true
+			i += 2
+
+		case len(out) > 0 && strings.HasPrefix(line, "rename from "):
+			out[len(out)-1].OldName = parseFileName(line[len("rename from "):], false, "")
+			i++
+
+		case len(out) > 0 && strings.HasPrefix(line, "rename to "):
+			out[len(out)-1].NewName = parseFileName(line[len("rename to "):], false, "")
+			i++
+
+		case len(out) > 0 && (strings.HasPrefix(line, "Binary files ") || line == "GIT binary patch"):
+			out[len(out)-1].Binary = true
+			i++
+
+		case strings.HasPrefix(line, "@@ "):
+			if len(out) == 0 {
+				return nil, fmt.Errorf("hunk before the first file header at line %d", i+1)
+			}
+			h, n, err := parseHunk[T](lines[i:])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", i+1, err)
+			}
+			f := &out[len(out)-1]
+			f.Hunks = append(f.Hunks, h)
+			i += n
+
+		default:
+			i++ // skip everything else
+		}
+	}
+	return out, nil
+}
+
+// parseGitHeader returns the names in a "diff --git a/old b/new" line.
+func parseGitHeader(line string) (oldName, newName string) {
+	names := line[len("diff --git "):]
+	if strings.HasPrefix(names, `"`) {
+		// Quoted names, the header is ambiguous otherwise.
+		oldQ, err := strconv.QuotedPrefix(names)
+		if err != nil {
+			return "", ""
+		}
+		oldName = parseFileName(oldQ, true, "a/")
+		newName = parseFileName(strings.TrimPrefix(names[len(oldQ):], " "), true, "b/")
+		return oldName, newName
+	}
+	// Without quotes, names can contain spaces. Prefer splitting the line into two names of the
+	// same length, this is always correct if the file wasn't renamed.
+	sep := len(names) / 2
+	if len(names)%2 == 0 || names[sep] != ' ' || !strings.HasPrefix(names[sep+1:], "b/") {
+		sep = strings.Index(names, " b/")
+		if sep < 0 {
+			return "", ""
+		}
+	}
+	return parseFileName(names[:sep], true, "a/"), parseFileName(names[sep+1:], true, "b/")
+}
+
+// parseFileName parses a file name in a file header. It removes trailing timestamps, unquotes
+// quoted names and, for git diffs, removes prefix.
+func parseFileName(name string, git bool, prefix string) string {
+	if n, _, found := strings.Cut(name, "\t"); found {
+		name = n // strip timestamp
+	}
+	if strings.HasPrefix(name, `"`) {
+		if n, err := strconv.Unquote(name); err == nil {
+			name = n
+		}
+	}
+	if git && name != "/dev/null" {
+		name = strings.TrimPrefix(name, prefix)
+	}
+	return name
+}
+
+// parseHunk parses the hunk starting at lines[0] and returns it together with the number of lines
+// it spans.
+func parseHunk[T string | []byte](lines []string) (Hunk[T], int, error) {
+	posX, nold, posY, nnew, err := parseHunkHeader(lines[0])
+	if err != nil {
+		return Hunk[T]{}, 0, err
+	}
+	h := Hunk[T]{
+		LineNoX:     posX,
+		EndLineNoX:  posX + nold,
+		LineNoY:     posY,
+		EndLineNoY:  posY + nnew,
+		AtFileStart: posX == 0 && posY == 0,
+	}
+	s, t := posX, posY
+	i := 1
+	for s < h.EndLineNoX || t < h.EndLineNoY || i < len(lines) && strings.HasPrefix(lines[i], `\`) {
+		if i >= len(lines) {
+			return Hunk[T]{}, 0, fmt.Errorf("unexpected end of hunk")
+		}
+		line := lines[i]
+		if line == "\n" {
+			line = " \n" // some tools strip the trailing whitespace of empty context lines
+		}
+		text := byteview.UnsafeAs[T](byteview.From(line[1:]))
+		switch line[0] {
+		case ' ':
+			h.Edits = append(h.Edits, Edit[T]{Op: diff.Match, LineNoX: s, LineNoY: t, Line: text})
+			s++
+			t++
+		case '-':
+			h.Edits = append(h.Edits, Edit[T]{Op: diff.Delete, LineNoX: s, LineNoY: -1, Line: text})
+			s++
+		case '+':
+			h.Edits = append(h.Edits, Edit[T]{Op: diff.Insert, LineNoX: -1, LineNoY: t, Line: text})
+			t++
+		case '\\':
+			// The previous line is missing a newline.
+			if len(h.Edits) > 0 {
+				e := &h.Edits[len(h.Edits)-1]
+				if n := len(e.Line); n > 0 && e.Line[n-1] == '\n' {
+					e.Line = e.Line[:n-1]
+				}
+				h.MissingNewlineX = h.MissingNewlineX || e.Op != diff.Insert
+				h.MissingNewlineY = h.MissingNewlineY || e.Op != diff.Delete
+			}
+		default:
+			return Hunk[T]{}, 0, fmt.Errorf("invalid line in hunk: %q", line)
+		}
+		i++
+	}
+	if s != h.EndLineNoX || t != h.EndLineNoY {
+		return Hunk[T]{}, 0, fmt.Errorf("wrong number of lines in hunk")
+	}
+	return h, i, nil
+}
+
+// parseHunkHeader parses a hunk header of the form "@@ -l,s +l,s @@". The length s is optional and
+// defaults to 1. The returned positions are zero-based.
+func parseHunkHeader(line string) (posX, nold, posY, nnew int, err error) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 || fields[3] != "@@" || !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return 0, 0, 0, 0, fmt.Errorf("invalid hunk header: %q", line)
+	}
+	parseRange := func(r string) (start, n int, err error) {
+		s, l, found := strings.Cut(r, ",")
+		start, err = strconv.Atoi(s)
+		if err != nil {
+			return 0, 0, err
+		}
+		n = 1
+		if found {
+			n, err = strconv.Atoi(l)
+			if err != nil {
+				return 0, 0, err
+			}
+		}
+		return start, n, nil
+	}
+	startX, nold, err := parseRange(fields[1][1:])
+	if err != nil {
+		return 0, 0, 0, 0, fmt.Errorf("invalid hunk header: %q", line)
+	}
+	startY, nnew, err := parseRange(fields[2][1:])
+	if err != nil {
+		return 0, 0, 0, 0, fmt.Errorf("invalid hunk header: %q", line)
+	}
+	// An empty range starts after the given line, otherwise the given line is the first line.
+	pos := func(start, n int) int {
+		if n > 0 {
+			start--
+		}
+		return max(0, start)
+	}
+	return pos(startX, nold), nold, pos(startY, nnew), nnew, nil
+}
@@ -0,0 +1,170 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"znkr.io/diff"
+)
+
+func TestSplitFiles(t *testing.T) {
+	oldA, newA := "one\ntwo\nthree\nfour\n", "one\n2\nthree\nfour\n"
+	oldB, newB := "alpha\nbeta", "alpha\nbeta\ngamma\n"
+	patch := "diff --git a/a.txt b/a.txt\n" +
+		"index 5f5fbe7..c3b3b1c 100644\n" +
+		"--- a/a.txt\n" +
+		"+++ b/a.txt\n" +
+		Unified(oldA, newA) +
+		"diff --git a/my logo.png b/my logo.png\n" +
+		"index 1e3f0a5..9c2b4d1 100644\n" +
+		"Binary files a/my logo.png and b/my logo.png differ\n" +
+		"diff --git a/b.txt b/dir/b.txt\n" +
+		"similarity index 60%\n" +
+		"rename from b.txt\n" +
+		"rename to dir/b.txt\n" +
+		"--- a/b.txt\n" +
+		"+++ b/dir/b.txt\n" +
+		Unified(oldB, newB)
+
+	got, err := SplitFiles(patch)
+	if err != nil {
+		t.Fatalf("SplitFiles(...) failed: %v", err)
+	}
+	want := []FileDiff[string]{
+		{OldName: "a.txt", NewName: "a.txt", Hunks: Hunks(oldA, newA)},
+		{OldName: "my logo.png", NewName: "my logo.png", Binary: true},
+		{OldName: "b.txt", NewName: "dir/b.txt", Hunks: Hunks(oldB, newB)},
+	}
+	for _, f := range want {
+		for i := range f.Hunks {
+			f.Hunks[i].AtFileEnd = false // the length of the files is unknown
+		}
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("SplitFiles(...) result is different (-want, +got):\n%s", diff)
+	}
+
+	// The lines reference the input for []byte.
+	gotBytes, err := SplitFiles([]byte(patch))
+	if err != nil {
+		t.Fatalf("SplitFiles([]byte(...)) failed: %v", err)
+	}
+	if len(gotBytes) != len(want) || len(gotBytes[0].Hunks) != 1 {
+		t.Fatalf("SplitFiles([]byte(...)) = %v, want %d files", gotBytes, len(want))
+	}
+	if got := string(gotBytes[0].Hunks[0].Edits[1].Line); got != "two\n" {
+		t.Errorf("SplitFiles([]byte(...)) line = %q, want %q", got, "two\n")
+	}
+}
+
+func TestSplitFilesPlain(t *testing.T) {
+	// Output of "diff -ruN" with timestamps, a created, and a deleted file.
+	patch := "Only in old: extra\n" +
+		"diff -ruN old/a.txt new/a.txt\n" +
+		"--- old/a.txt\t2025-01-01 12:00:00.000000000 +0100\n" +
+		"+++ new/a.txt\t2025-01-02 12:00:00.000000000 +0100\n" +
+		"@@ -1 +1 @@\n" +
+		"-foo\n" +
+		"+bar\n" +
+		"--- /dev/null\n" +
+		"+++ new/b.txt\n" +
+		"@@ -0,0 +1,2 @@\n" +
+		"+b\n" +
+		"+c\n" +
+		"\\ No newline at end of file\n" +
+		"--- old/c.txt\n" +
+		"+++ /dev/null\n" +
+		"@@ -1 +0,0 @@\n" +
+		"-c\n"
+
+	got, err := SplitFiles(patch)
+	if err != nil {
+		t.Fatalf("SplitFiles(...) failed: %v", err)
+	}
+	want := []FileDiff[string]{
+		{
+			OldName: "old/a.txt",
+			NewName: "new/a.txt",
+			Hunks: []Hunk[string]{{
+				LineNoX: 0, EndLineNoX: 1, LineNoY: 0, EndLineNoY: 1,
+				AtFileStart: true,
+				Edits: []Edit[string]{
+					{diff.Delete, 0, -1, "foo\n", "", false},
+					{diff.Insert, -1, 0, "bar\n", "", false},
+				},
+			}},
+		},
+		{
+			OldName: "/dev/null",
+			NewName: "new/b.txt",
+			Hunks: []Hunk[string]{{
+				LineNoX: 0, EndLineNoX: 0, LineNoY: 0, EndLineNoY: 2,
+				AtFileStart: true,
+				Edits: []Edit[string]{
+					{diff.Insert, -1, 0, "b\n", "", false},
+					{diff.Insert, -1, 1, "c", "", false},
+				},
+				MissingNewlineY: true,
+			}},
+		},
+		{
+			OldName: "old/c.txt",
+			NewName: "/dev/null",
+			Hunks: []Hunk[string]{{
+				LineNoX: 0, EndLineNoX: 1, LineNoY: 0, EndLineNoY: 0,
+				AtFileStart: true,
+				Edits: []Edit[string]{
+					{diff.Delete, 0, -1, "c\n", "", false},
+				},
+			}},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("SplitFiles(...) result is different (-want, +got):\n%s", diff)
+	}
+}
+
+func TestSplitFilesError(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+	}{
+		{
+			name:  "hunk-before-header",
+			patch: "@@ -1 +1 @@\n-a\n+b\n",
+		},
+		{
+			name:  "invalid-header",
+			patch: "--- a\n+++ b\n@@ -x +1 @@\n-a\n+b\n",
+		},
+		{
+			name:  "truncated",
+			patch: "--- a\n+++ b\n@@ -1,2 +1,2 @@\n-a\n+b\n",
+		},
+		{
+			name:  "invalid-line",
+			patch: "--- a\n+++ b\n@@ -1 +1 @@\n*a\n+b\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := SplitFiles(tt.patch); err == nil {
+				t.Errorf("SplitFiles(...) = %v, want error", got)
+			}
+		})
+	}
+}
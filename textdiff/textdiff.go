@@ -24,7 +24,12 @@ package textdiff
 
 import (
 	"fmt"
+	"hash/maphash"
+	"io"
+	"iter"
 	"slices"
+	"strings"
+	"unicode/utf8"
 
 	"znkr.io/diff"
 	"znkr.io/diff/internal/byteview"
@@ -36,23 +41,86 @@ import (
 
 // Edit describes a single edit of a line-by-line diff.
 //
-//   - For Match, Line contains the matching line. LineNoX and LineNoY contain the respective
-//     line numbers (zero-based) in the input.
+//   - For Match, Line contains the matching line from x. LineNoX and LineNoY contain the
+//     respective line numbers (zero-based) in the input. If the line in y differs from the line in
+//     x, which is only possible with options like [NormalizeUnicode], [IgnoreMatching], or
+//     [FuzzyLines] or with functions like [HunksKey], LineY contains the line from y. Otherwise,
+//     LineY is empty.
 //   - For Delete, Line contains the deleted line from x. LineNoX contains the line number in x
 //     and LineNoY is -1.
 //   - For Insert, Line contains the inserted line from y. LineNoY contains the line number in y
 //     and LineNoX is -1.
+//
+// Reindent is set for deletions and insertions that only change the indentation of a line, see
+// [MarkReindent].
 type Edit[T string | []byte] struct {
 	Op               diff.Op
 	LineNoX, LineNoY int
 	Line             T
+	LineY            T
+	Reindent         bool
+}
+
+// String formats e for debugging: The prefix of the op (see [diff.Op.Prefix]), or ">" if
+// [Edit.Reindent] is set, followed by the line without its newline.
+func (e Edit[T]) String() string {
+	prefix := e.Op.Prefix()
+	if e.Reindent {
+		prefix = ">"
+	}
+	return prefix + strings.TrimSuffix(string(e.Line), "\n")
 }
 
 // Hunk describes a sequence of consecutive edits.
+//
+// The edits are ordered by line: Every line in LineNoX..EndLineNoX and LineNoY..EndLineNoY is
+// covered by exactly one edit and the line numbers of these edits increase without gaps.
 type Hunk[T string | []byte] struct {
 	LineNoX, EndLineNoX int       // Start and end line in x (zero-based).
 	LineNoY, EndLineNoY int       // Start and end line in y (zero-based).
 	Edits               []Edit[T] // Edits to transform x lines LineNoX..EndLineNoX to y lines LineNoY..EndLineNoY
+
+	// MissingNewlineX and MissingNewlineY are set if the hunk contains the last line of x or y,
+	// respectively, and that line doesn't end in a newline. This corresponds to the
+	// "\ No newline at end of file" marker in the output of [Unified].
+	MissingNewlineX, MissingNewlineY bool
+
+	// AtFileStart and AtFileEnd are set if the hunk starts at the first line or ends after the last
+	// line of both x and y, respectively. A hunk at a file boundary has less leading or trailing
+	// context than requested with [diff.Context]. Hunks parsed with [SplitFiles] only report
+	// AtFileStart, because the length of the files is unknown.
+	AtFileStart, AtFileEnd bool
+
+	// Binary is set if x or y is binary, see [BinaryDetection]. A binary hunk covers both inputs
+	// completely and has no edits.
+	Binary bool
+}
+
+// String formats h like a hunk in the output of [Unified] for debugging.
+func (h Hunk[T]) String() string {
+	if h.Binary {
+		return "Binary files differ\n"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", h.LineNoX+1, h.EndLineNoX-h.LineNoX, h.LineNoY+1, h.EndLineNoY-h.LineNoY)
+	for _, e := range h.Edits {
+		b.WriteString(e.Op.Prefix())
+		b.WriteString(string(e.Line))
+		if h.missingNewline(e) {
+			writeMissingNewline(&b, "\n")
+		}
+	}
+	return b.String()
+}
+
+// missingNewline reports whether e is the last line of x or y in h and is missing its newline.
+// Deletions and matches refer to lines in x, insertions to lines in y.
+func (h Hunk[T]) missingNewline(e Edit[T]) bool {
+	line := byteview.From(e.Line)
+	if e.Op == diff.Insert {
+		return h.MissingNewlineY && !hasNewline(line)
+	}
+	return h.MissingNewlineX && !hasNewline(line)
 }
 
 // Hunks compares the lines in x and y and returns the changes necessary to convert from one to the
@@ -64,27 +132,233 @@ type Hunk[T string | []byte] struct {
 //
 // If x and y are identical, the output has length zero.
 //
-// The following options are supported: [diff.Context], [diff.Minimal], [diff.Fast],
-// [IndentHeuristic]
+// The following options are supported: [diff.Context], [diff.Minimal], [diff.PreferLongMatches],
+// [diff.Fast], [diff.AutoFast], [diff.Parallel], [diff.PairedOrdering], [IndentHeuristic],
+// [NormalizeUnicode], [IgnoreMatching], [IgnoreComments], [IgnoreReorderedBlocks], [AnchorOn],
+// [FuzzyLines], [BlockContext], [FunctionContext], [MaxHunkLines], [MinHunkChanges],
+// [MarkReindent], [diff.MaxHunks], [BinaryDetection]
 //
 // Important: The output is not guaranteed to be stable and may change with minor version upgrades.
 // DO NOT rely on the output being stable.
 func Hunks[T string | []byte](x, y T, opts ...Option) []Hunk[T] {
-	cfg := config.FromOptions(opts, config.Context|config.Minimal|config.Fast|config.IndentHeuristic)
-	xlines, _ := byteview.SplitLines(byteview.From(x))
-	ylines, _ := byteview.SplitLines(byteview.From(y))
-	rx, ry := impl.Diff(xlines, ylines, cfg)
+	cfg := config.FromOptions(opts, config.Algorithm|lineFlags|hunkFlags|config.MarkReindent|config.MaxHunks|config.BinaryDetection)
+	if binaryInputs(x, y, &cfg) {
+		return binaryHunks(x, y)
+	}
+	return markedHunks[T](diffLines(x, y, cfg), cfg)
+}
+
+// HunksTruncated is like [Hunks], but additionally returns the number of hunks that were omitted
+// because of [diff.MaxHunks].
+//
+// The following options are supported: [diff.Context], [diff.Minimal], [diff.PreferLongMatches],
+// [diff.Fast], [diff.AutoFast], [diff.Parallel], [diff.PairedOrdering], [IndentHeuristic],
+// [NormalizeUnicode], [IgnoreMatching], [IgnoreComments], [IgnoreReorderedBlocks], [AnchorOn],
+// [FuzzyLines], [BlockContext], [FunctionContext], [MaxHunkLines], [MinHunkChanges],
+// [MarkReindent], [diff.MaxHunks], [BinaryDetection]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func HunksTruncated[T string | []byte](x, y T, opts ...Option) ([]Hunk[T], int) {
+	cfg := config.FromOptions(opts, config.Algorithm|lineFlags|hunkFlags|config.MarkReindent|config.MaxHunks|config.BinaryDetection)
+	if binaryInputs(x, y, &cfg) {
+		return binaryHunks(x, y), 0
+	}
+	d := diffLines(x, y, cfg)
+	hout := markedHunks[T](d, cfg)
+	if cfg.MaxHunks == 0 || len(hout) < cfg.MaxHunks {
+		return hout, 0
+	}
+	// Count all hunks, without computing their edits.
+	cfg.MaxHunks = 0
+	total := 0
+	for range d.hunkSeq(cfg) {
+		total++
+	}
+	return hout, total - len(hout)
+}
+
+// HunksSeq is like [Hunks], but returns an iterator that computes the diff when it's used and
+// yields the hunks one by one. This avoids allocating all hunks at once if the hunks are processed
+// incrementally. The yielded hunks are the same as the ones returned by [Hunks].
+//
+// The following options are supported: [diff.Context], [diff.Minimal], [diff.PreferLongMatches],
+// [diff.Fast], [diff.AutoFast], [diff.Parallel], [diff.PairedOrdering], [IndentHeuristic],
+// [NormalizeUnicode], [IgnoreMatching], [IgnoreComments], [IgnoreReorderedBlocks], [AnchorOn],
+// [FuzzyLines], [BlockContext], [FunctionContext], [MaxHunkLines], [MinHunkChanges],
+// [MarkReindent], [diff.MaxHunks], [BinaryDetection]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func HunksSeq[T string | []byte](x, y T, opts ...Option) iter.Seq[Hunk[T]] {
+	cfg := config.FromOptions(opts, config.Algorithm|lineFlags|hunkFlags|config.MarkReindent|config.MaxHunks|config.BinaryDetection)
+	return func(yield func(Hunk[T]) bool) {
+		if binaryInputs(x, y, &cfg) {
+			for _, h := range binaryHunks(x, y) {
+				if !yield(h) {
+					return
+				}
+			}
+			return
+		}
+		d := diffLines(x, y, cfg)
+		for hunk := range d.hunkSeq(cfg) {
+			edits := appendEdits(make([]Edit[T], 0, hunk.Edits), d, hunk, cfg)
+			if cfg.MarkReindent {
+				markReindent(edits)
+			}
+			if !yield(makeHunk(d, hunk, edits)) {
+				return
+			}
+		}
+	}
+}
+
+// markedHunks is like hunks, but additionally applies [MarkReindent].
+func markedHunks[T string | []byte](d lineDiff, cfg config.Config) []Hunk[T] {
+	hout := hunks[T](d, cfg)
+	if cfg.MarkReindent {
+		for _, h := range hout {
+			markReindent(h.Edits)
+		}
+	}
+	return hout
+}
+
+// lineDiff is the result of comparing two inputs line by line.
+type lineDiff struct {
+	x, y                             []byteview.ByteView // Lines of the inputs.
+	xMissingNewline, yMissingNewline int                 // Index of the last line if it's missing a newline or -1.
+	rx, ry                           []bool              // Result vectors.
+	normalized                       bool                // Set if matching lines can differ.
+	depth                            []int               // Nesting depth in x, see [BlockContext].
+	funcStart                        []bool              // Function start lines in x, see [FunctionContext].
+}
+
+// fingerprintMinLines is the minimum number of lines in both inputs for which lines are identified
+// by their fingerprints instead of using the lines as map keys. A map keyed by fingerprints uses
+// less memory per entry, but computing the fingerprints is slightly slower. This only pays off for
+// inputs with very many distinct lines. The result is the same, see [impl.DiffFingerprint].
+const fingerprintMinLines = 1 << 20
+
+// diffLines splits x and y into lines and compares them.
+func diffLines[T string | []byte](x, y T, cfg config.Config) lineDiff {
+	var d lineDiff
+	d.x, d.xMissingNewline = byteview.SplitLines(byteview.From(x))
+	d.y, d.yMissingNewline = byteview.SplitLines(byteview.From(y))
+	d.compare(cfg)
+	return d
+}
+
+// compare compares the lines in d and sets the result vectors.
+func (d *lineDiff) compare(cfg config.Config) {
+	d.normalized = cfg.LineKey != nil || cfg.ReorderedBlock != nil || cfg.FuzzyLinesThreshold > 0
+	xkeys, ykeys := d.x, d.y
+	if cfg.LineKey != nil {
+		xkeys = lineKeys(d.x, cfg.LineKey)
+		ykeys = lineKeys(d.y, cfg.LineKey)
+	}
+	if cfg.ReorderedBlock != nil {
+		xkeys, ykeys = reorderedBlockKeys(d.x, d.y, xkeys, ykeys, cfg.ReorderedBlock)
+	}
+	switch {
+	case cfg.Anchor != nil:
+		d.rx, d.ry = diffAnchored(d.x, d.y, xkeys, ykeys, cfg)
+	case cfg.ForceFingerprintLines || len(xkeys)+len(ykeys) >= fingerprintMinLines:
+		seed := maphash.MakeSeed()
+		fingerprint := func(line byteview.ByteView) uint64 {
+			return maphash.String(seed, byteview.UnsafeAs[string](line))
+		}
+		d.rx, d.ry = impl.DiffFingerprint(xkeys, ykeys, fingerprint, cfg)
+	default:
+		d.rx, d.ry = impl.Diff(xkeys, ykeys, cfg)
+	}
+	if cfg.BlockContext != nil {
+		d.depth = nestingDepth(d.x, cfg.BlockContext)
+	}
+	if cfg.FunctionContext != nil {
+		d.funcStart = matchLines(d.x, cfg.FunctionContext)
+	}
 	if cfg.IndentHeuristic {
-		indentheuristic.Apply(xlines, ylines, rx, ry)
+		w := cfg.IndentHeuristicWeights
+		if w == nil {
+			w = &indentheuristic.DefaultWeights
+		}
+		var skipx, skipy []bool
+		if cfg.IndentHeuristicExcept != nil {
+			skipx, skipy = matchLines(d.x, cfg.IndentHeuristicExcept), matchLines(d.y, cfg.IndentHeuristicExcept)
+		}
+		indentheuristic.ApplyWeightsExcept(xkeys, ykeys, d.rx, d.ry, w, skipx, skipy)
+	}
+	if cfg.FuzzyLinesThreshold > 0 {
+		matchFuzzyLines(d.x, d.y, d.rx, d.ry, cfg.FuzzyLinesThreshold)
+	}
+}
+
+// matchLines returns a slice that reports for every line whether it satisfies pred.
+func matchLines(lines []byteview.ByteView, pred func(line string) bool) []bool {
+	r := make([]bool, len(lines))
+	for i, line := range lines {
+		r[i] = pred(byteview.UnsafeAs[string](line))
+	}
+	return r
+}
+
+// matchFuzzyLines turns pairs of deleted and inserted lines into matches if they are similar
+// enough, see [FuzzyLines].
+func matchFuzzyLines(x, y []byteview.ByteView, rx, ry []bool, threshold float64) {
+	n, m := len(rx)-1, len(ry)-1
+	for s, t := 0, 0; s < n || t < m; {
+		s0, t0 := s, t
+		for s < n && rx[s] {
+			s++
+		}
+		for t < m && ry[t] {
+			t++
+		}
+		for i := range min(s-s0, t-t0) {
+			if lineSimilarity(x[s0+i], y[t0+i]) >= threshold {
+				rx[s0+i], ry[t0+i] = false, false
+			}
+		}
+		for s < n && t < m && !rx[s] && !ry[t] {
+			s++
+			t++
+		}
+	}
+}
+
+// lineSimilarity returns 2*M/(N+K) for the runes in a and b, ignoring the newline.
+func lineSimilarity(a, b byteview.ByteView) float64 {
+	ra := []rune(strings.TrimSuffix(byteview.UnsafeAs[string](a), "\n"))
+	rb := []rune(strings.TrimSuffix(byteview.UnsafeAs[string](b), "\n"))
+	if len(ra)+len(rb) == 0 {
+		return 1
+	}
+	rx, _ := impl.Diff(ra, rb, config.Default)
+	matches := 0
+	for _, r := range rx[:len(ra)] {
+		if !r {
+			matches++
+		}
 	}
-	return hunks[T](xlines, ylines, rx, ry, cfg)
+	return 2 * float64(matches) / float64(len(ra)+len(rb))
 }
 
-func hunks[T string | []byte](x, y []byteview.ByteView, rx, ry []bool, cfg config.Config) []Hunk[T] {
+// lineKeys returns the comparison keys for lines.
+func lineKeys(lines []byteview.ByteView, key func(line string) string) []byteview.ByteView {
+	keys := make([]byteview.ByteView, len(lines))
+	for i, line := range lines {
+		keys[i] = byteview.From(key(byteview.UnsafeAs[string](line)))
+	}
+	return keys
+}
+
+func hunks[T string | []byte](d lineDiff, cfg config.Config) []Hunk[T] {
 	// Compute the number of hunks and edits, this is relatively cheap and allows us to preallocate
 	// the return values.
 	var nhunks, nedits int
-	for hunk := range rvecs.Hunks(rx, ry, cfg) {
+	for hunk := range d.hunkSeq(cfg) {
 		nhunks++
 		nedits += hunk.Edits
 	}
@@ -94,45 +368,133 @@ func hunks[T string | []byte](x, y []byteview.ByteView, rx, ry []bool, cfg confi
 
 	eout := make([]Edit[T], 0, nedits)
 	hout := make([]Hunk[T], 0, nhunks)
-	for hunk := range rvecs.Hunks(rx, ry, cfg) {
-		for s, t := hunk.S0, hunk.T0; s < hunk.S1 || t < hunk.T1; {
-			for s < hunk.S1 && rx[s] {
+	for hunk := range d.hunkSeq(cfg) {
+		eout = appendEdits(eout, d, hunk, cfg)
+		hout = append(hout, makeHunk(d, hunk, slices.Clip(eout)))
+		eout = eout[len(eout):]
+	}
+	return hout
+}
+
+// appendEdits appends the edits of hunk to eout.
+func appendEdits[T string | []byte](eout []Edit[T], d lineDiff, hunk rvecs.Hunk, cfg config.Config) []Edit[T] {
+	x, y := d.x, d.y
+	for r := range rvecs.Runs(d.rx, d.ry, hunk, cfg.PairedOrdering) {
+		for i := range r.N {
+			s, t := r.S+i, r.T+i
+			switch r.Op {
+			case rvecs.Delete:
 				eout = append(eout, Edit[T]{
 					Op:      diff.Delete,
 					Line:    byteview.UnsafeAs[T](x[s]),
 					LineNoX: s,
 					LineNoY: -1,
 				})
-				s++
-			}
-			for t < hunk.T1 && ry[t] {
+			case rvecs.Insert:
 				eout = append(eout, Edit[T]{
 					Op:      diff.Insert,
 					Line:    byteview.UnsafeAs[T](y[t]),
 					LineNoX: -1,
 					LineNoY: t,
 				})
-				t++
+			case rvecs.Match:
+				eout = append(eout, matchEdit[T](x, y, s, t, d.normalized))
 			}
-			for s < hunk.S1 && t < hunk.T1 && !rx[s] && !ry[t] {
-				eout = append(eout, Edit[T]{
-					Op:      diff.Match,
-					Line:    byteview.UnsafeAs[T](x[s]),
-					LineNoX: s,
-					LineNoY: t,
-				})
-				s++
-				t++
+		}
+	}
+	return eout
+}
+
+// makeHunk creates the hunk for hunk with the given edits.
+func makeHunk[T string | []byte](d lineDiff, hunk rvecs.Hunk, edits []Edit[T]) Hunk[T] {
+	return Hunk[T]{
+		LineNoX:         hunk.S0,
+		EndLineNoX:      hunk.S1,
+		LineNoY:         hunk.T0,
+		EndLineNoY:      hunk.T1,
+		Edits:           edits,
+		MissingNewlineX: hunk.S0 <= d.xMissingNewline && d.xMissingNewline < hunk.S1,
+		MissingNewlineY: hunk.T0 <= d.yMissingNewline && d.yMissingNewline < hunk.T1,
+		AtFileStart:     hunk.S0 == 0 && hunk.T0 == 0,
+		AtFileEnd:       hunk.S1 == len(d.x) && hunk.T1 == len(d.y),
+	}
+}
+
+// HunkRanges returns the line ranges of all deletions in x and all insertions in y in hunk. Each
+// range is a half-open interval [start, end) of zero-based line numbers. Adjacent lines are merged
+// into a single range, even if deletions and insertions are interleaved (see
+// [diff.PairedOrdering]).
+func HunkRanges[T string | []byte](hunk Hunk[T]) (deletes, inserts [][2]int) {
+	for _, e := range hunk.Edits {
+		switch e.Op {
+		case diff.Delete:
+			deletes = extendRange(deletes, e.LineNoX)
+		case diff.Insert:
+			inserts = extendRange(inserts, e.LineNoY)
+		}
+	}
+	return deletes, inserts
+}
+
+// extendRange adds line to the last range in ranges if it's adjacent or starts a new range.
+func extendRange(ranges [][2]int, line int) [][2]int {
+	if n := len(ranges); n > 0 && ranges[n-1][1] == line {
+		ranges[n-1][1]++
+		return ranges
+	}
+	return append(ranges, [2]int{line, line + 1})
+}
+
+// ApplyIndentHeuristic applies the indentation heuristic (see [IndentHeuristic]) to already
+// computed hunks and returns the adjusted hunks. The input hunks are not modified.
+//
+// This allows to compute a diff once and to decide later, e.g. for display, whether to apply the
+// heuristic. The hunk boundaries are retained and the heuristic can only shift changes within a
+// hunk. The result can therefore differ from [Hunks] with [IndentHeuristic] if the best shift
+// extends beyond the context lines of a hunk, and the number of context lines before and after the
+// changes in a hunk can change. The marks set by [MarkReindent] are dropped.
+func ApplyIndentHeuristic[T string | []byte](hunks []Hunk[T]) []Hunk[T] {
+	if len(hunks) == 0 {
+		return nil
+	}
+
+	hout := make([]Hunk[T], 0, len(hunks))
+	for _, h := range hunks {
+		var x, y []byteview.ByteView
+		var rx, ry []bool
+		normalized := false
+		for _, edit := range h.Edits {
+			op := edit.Op
+			line := byteview.From(edit.Line)
+			if op != diff.Insert {
+				x = append(x, line)
+				rx = append(rx, op == diff.Delete)
+			}
+			if op == diff.Match && len(edit.LineY) > 0 {
+				line = byteview.From(edit.LineY)
+				normalized = true
+			}
+			if op != diff.Delete {
+				y = append(y, line)
+				ry = append(ry, op == diff.Insert)
 			}
 		}
-		hout = append(hout, Hunk[T]{
-			LineNoX:    hunk.S0,
-			EndLineNoX: hunk.S1,
-			LineNoY:    hunk.T0,
-			EndLineNoY: hunk.T1,
-			Edits:      slices.Clip(eout),
-		})
-		eout = eout[len(eout):]
+		// Sentinels, see rvecs.
+		rx = append(rx, false)
+		ry = append(ry, false)
+
+		indentheuristic.Apply(x, y, rx, ry)
+		eout := edits[T](x, y, rx, ry, normalized)
+		for i := range eout {
+			if eout[i].LineNoX >= 0 {
+				eout[i].LineNoX += h.LineNoX
+			}
+			if eout[i].LineNoY >= 0 {
+				eout[i].LineNoY += h.LineNoY
+			}
+		}
+		h.Edits = eout
+		hout = append(hout, h)
 	}
 	return hout
 }
@@ -143,22 +505,125 @@ func hunks[T string | []byte](x, y []byteview.ByteView, rx, ry []bool, cfg confi
 // Edits returns edits for every element in the input. If x and y are identical, the output will
 // consist of a match edit for every input element.
 //
-// The following options are supported: [diff.Minimal], [diff.Fast], [IndentHeuristic]
+// The following options are supported: [diff.Minimal], [diff.PreferLongMatches], [diff.Fast],
+// [diff.AutoFast], [diff.Parallel], [IndentHeuristic], [NormalizeUnicode], [IgnoreMatching],
+// [IgnoreComments], [IgnoreReorderedBlocks], [AnchorOn], [FuzzyLines], [MarkReindent]
 //
 // Important: The output is not guaranteed to be stable and may change with minor version upgrades.
 // DO NOT rely on the output being stable.
 func Edits[T string | []byte](x, y T, opts ...Option) []Edit[T] {
-	cfg := config.FromOptions(opts, config.Minimal|config.Fast|config.IndentHeuristic)
-	xlines, _ := byteview.SplitLines(byteview.From(x))
-	ylines, _ := byteview.SplitLines(byteview.From(y))
-	rx, ry := impl.Diff(xlines, ylines, cfg)
-	if cfg.IndentHeuristic {
-		indentheuristic.Apply(xlines, ylines, rx, ry)
+	cfg := config.FromOptions(opts, config.Algorithm|lineFlags|config.MarkReindent)
+	d := diffLines(x, y, cfg)
+	eout := edits[T](d.x, d.y, d.rx, d.ry, d.normalized)
+	if cfg.MarkReindent {
+		markReindent(eout)
+	}
+	return eout
+}
+
+// InsertedLines compares the lines in x and y and returns only the lines inserted into y, in the
+// order they appear in y. This is useful to extract what's new in y, e.g., for a changelog.
+//
+// Every line includes its newline character, except for the last line of y if it's missing the
+// newline. A line that only differs in its missing newline is reported as inserted.
+//
+// The following options are supported: [diff.Minimal], [diff.PreferLongMatches], [diff.Fast],
+// [diff.AutoFast], [diff.Parallel], [IndentHeuristic], [NormalizeUnicode], [IgnoreMatching],
+// [IgnoreComments], [IgnoreReorderedBlocks], [AnchorOn], [FuzzyLines]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func InsertedLines[T string | []byte](x, y T, opts ...Option) []T {
+	cfg := config.FromOptions(opts, config.Algorithm|lineFlags)
+	d := diffLines(x, y, cfg)
+	return changedLines[T](d.y, d.ry)
+}
+
+// DeletedLines is like [InsertedLines], but returns only the lines deleted from x, in the order
+// they appear in x.
+//
+// The following options are supported: [diff.Minimal], [diff.PreferLongMatches], [diff.Fast],
+// [diff.AutoFast], [diff.Parallel], [IndentHeuristic], [NormalizeUnicode], [IgnoreMatching],
+// [IgnoreComments], [IgnoreReorderedBlocks], [AnchorOn], [FuzzyLines]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func DeletedLines[T string | []byte](x, y T, opts ...Option) []T {
+	cfg := config.FromOptions(opts, config.Algorithm|lineFlags)
+	d := diffLines(x, y, cfg)
+	return changedLines[T](d.x, d.rx)
+}
+
+// changedLines returns all lines for which r is true.
+func changedLines[T string | []byte](lines []byteview.ByteView, r []bool) []T {
+	var out []T
+	for i, line := range lines {
+		if r[i] {
+			out = append(out, byteview.UnsafeAs[T](line))
+		}
+	}
+	return out
+}
+
+// ChangedLines compares the lines in x and y and returns the one-based line numbers of all inserted
+// lines in y, in increasing order. This is useful to restrict checks to the changed lines of a
+// file, e.g., to only lint changed lines in CI. Use [InsertedLines] to get the lines themselves and
+// [DeletedLineNumbers] to get the line numbers of the deleted lines in x.
+//
+// If no line was inserted, the output has length zero.
+//
+// The following options are supported: [diff.Minimal], [diff.PreferLongMatches], [diff.Fast],
+// [diff.AutoFast], [diff.Parallel], [IndentHeuristic], [NormalizeUnicode], [IgnoreMatching],
+// [IgnoreComments], [IgnoreReorderedBlocks], [AnchorOn], [FuzzyLines]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func ChangedLines[T string | []byte](x, y T, opts ...Option) []int {
+	cfg := config.FromOptions(opts, config.Algorithm|lineFlags)
+	d := diffLines(x, y, cfg)
+	return lineNumbers(d.ry)
+}
+
+// DeletedLineNumbers is like [ChangedLines], but returns the one-based line numbers of all deleted
+// lines in x.
+//
+// The following options are supported: [diff.Minimal], [diff.PreferLongMatches], [diff.Fast],
+// [diff.AutoFast], [diff.Parallel], [IndentHeuristic], [NormalizeUnicode], [IgnoreMatching],
+// [IgnoreComments], [IgnoreReorderedBlocks], [AnchorOn], [FuzzyLines]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func DeletedLineNumbers[T string | []byte](x, y T, opts ...Option) []int {
+	cfg := config.FromOptions(opts, config.Algorithm|lineFlags)
+	d := diffLines(x, y, cfg)
+	return lineNumbers(d.rx)
+}
+
+// lineNumbers returns the one-based line numbers of all changed lines in the result vector r.
+func lineNumbers(r []bool) []int {
+	var out []int
+	for i, changed := range r[:len(r)-1] {
+		if changed {
+			out = append(out, i+1)
+		}
 	}
-	return edits[T](xlines, ylines, rx, ry)
+	return out
+}
+
+// Runes compares x and y rune by rune and returns one edit for every rune in the input. This is
+// useful to highlight changes within a line. The inputs are interpreted as UTF-8 and PosX and PosY
+// of the returned edits are rune indices, not byte offsets.
+//
+// The following options are supported: [diff.Minimal], [diff.PreferLongMatches], [diff.Fast],
+// [diff.AutoFast], [diff.Parallel]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func Runes[T string | []byte](x, y T, opts ...Option) []diff.Edit[rune] {
+	return diff.Edits([]rune(string(x)), []rune(string(y)), opts...)
 }
 
-func edits[T string | []byte](x, y []byteview.ByteView, rx, ry []bool) []Edit[T] {
+func edits[T string | []byte](x, y []byteview.ByteView, rx, ry []bool, normalized bool) []Edit[T] {
 	// Compute the number of edits, this is relatively cheap and allows us to preallocate the return
 	// value.
 	n, m := len(rx)-1, len(ry)-1
@@ -203,12 +668,7 @@ func edits[T string | []byte](x, y []byteview.ByteView, rx, ry []bool) []Edit[T]
 			t++
 		}
 		for s < n && t < m && !rx[s] && !ry[t] {
-			eout = append(eout, Edit[T]{
-				Op:      diff.Match,
-				Line:    byteview.UnsafeAs[T](x[s]),
-				LineNoX: s,
-				LineNoY: t,
-			})
+			eout = append(eout, matchEdit[T](x, y, s, t, normalized))
 			s++
 			t++
 		}
@@ -216,123 +676,231 @@ func edits[T string | []byte](x, y []byteview.ByteView, rx, ry []bool) []Edit[T]
 	return eout
 }
 
+// matchEdit returns the match edit for x[s] and y[t]. If normalized is set, the lines can differ
+// and LineY is set if they do.
+func matchEdit[T string | []byte](x, y []byteview.ByteView, s, t int, normalized bool) Edit[T] {
+	e := Edit[T]{
+		Op:      diff.Match,
+		Line:    byteview.UnsafeAs[T](x[s]),
+		LineNoX: s,
+		LineNoY: t,
+	}
+	if normalized && x[s] != y[t] {
+		e.LineY = byteview.UnsafeAs[T](y[t])
+	}
+	return e
+}
+
 const (
 	prefixMatch  = " "
 	prefixDelete = "-"
 	prefixInsert = "+"
 )
 
-const missingNewline = "\n\\ No newline at end of file\n"
+// noNewlineMarker marks the last line of x or y if it doesn't end in a newline. All output formats
+// that can represent a missing newline follow the same policy: The marker is written on its own line
+// directly after the line that's missing its newline, no matter whether that line is deleted,
+// inserted, or matched. Use [Hunk.missingNewline] to decide if a line needs the marker and
+// [writeMissingNewline] to write it.
+const noNewlineMarker = "\\ No newline at end of file"
+
+// writeMissingNewline terminates a line that is missing its newline and writes the marker for it,
+// using nl as the line separator.
+func writeMissingNewline(w io.Writer, nl string) {
+	io.WriteString(w, nl)
+	io.WriteString(w, noNewlineMarker)
+	io.WriteString(w, nl)
+}
+
+// missingNewlineLen is the number of bytes written by writeMissingNewline.
+func missingNewlineLen(nl string) int {
+	return 2*len(nl) + len(noNewlineMarker)
+}
 
 // Unified compares the lines in x and y and returns the changes necessary to convert from one to
 // the other in unified format.
 //
-// The following options are supported: [diff.Context], [diff.Minimal], [diff.Fast],
-// [IndentHeuristic], [TerminalColors]
+// The following options are supported: [diff.Context], [diff.Minimal], [diff.PreferLongMatches],
+// [diff.Fast], [diff.AutoFast], [diff.Parallel], [diff.PairedOrdering], [IndentHeuristic],
+// [NormalizeUnicode], [IgnoreMatching], [IgnoreComments], [IgnoreReorderedBlocks], [AnchorOn],
+// [FuzzyLines], [BlockContext], [FunctionContext], [MaxHunkLines], [MinHunkChanges],
+// [MaxLineWidth], [SanitizeInvalidUTF8], [ShowWhitespace], [TerminalColors], [OutputNewline],
+// [Labels], [IndexHeader], [HunkStatsInHeader], [BinaryDetection]
 //
 // Important: The output is not guaranteed to be stable and may change with minor version upgrades.
 // DO NOT rely on the output being stable.
 func Unified[T string | []byte](x, y T, opts ...Option) T {
-	cfg := config.FromOptions(opts, config.Context|config.Minimal|config.Fast|config.IndentHeuristic|config.TerminalColors)
+	cfg := config.FromOptions(opts, config.Algorithm|lineFlags|hunkFlags|renderFlags|config.BinaryDetection)
+	if binaryInputs(x, y, &cfg) {
+		return binaryUnified(x, y, &cfg)
+	}
+	return unified[T](diffLines(x, y, cfg), cfg)
+}
 
-	xlines, xMissingNewline := byteview.SplitLines(byteview.From(x))
-	ylines, yMissingNewline := byteview.SplitLines(byteview.From(y))
+// UnifiedCompact is like [Unified], but only prints the header of the first hunk. All other hunk
+// headers are replaced with a separator line, "..." by default. This is useful to display many
+// small hunks compactly.
+//
+// Note: The output is for display only, it's not a valid patch and can't be applied with patch.
+//
+// The following options are supported: [diff.Context], [diff.Minimal], [diff.PreferLongMatches],
+// [diff.Fast], [diff.AutoFast], [diff.Parallel], [diff.PairedOrdering], [IndentHeuristic],
+// [NormalizeUnicode], [IgnoreMatching], [IgnoreComments], [IgnoreReorderedBlocks], [AnchorOn],
+// [FuzzyLines], [BlockContext], [FunctionContext], [MaxHunkLines], [MinHunkChanges],
+// [MaxLineWidth], [SanitizeInvalidUTF8], [ShowWhitespace], [TerminalColors], [OutputNewline],
+// [Labels], [IndexHeader], [HunkStatsInHeader], [HunkSeparator], [BinaryDetection]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func UnifiedCompact[T string | []byte](x, y T, opts ...Option) T {
+	cfg := config.FromOptions(opts, config.Algorithm|lineFlags|hunkFlags|renderFlags|config.HunkSeparator|config.BinaryDetection)
+	if binaryInputs(x, y, &cfg) {
+		return binaryUnified(x, y, &cfg)
+	}
+	if cfg.HunkSeparator == "" {
+		cfg.HunkSeparator = "..."
+	}
+	return unified[T](diffLines(x, y, cfg), cfg)
+}
 
-	rx, ry := impl.Diff(xlines, ylines, cfg)
+// UnifiedIfSimilar is like [Unified], but only returns the unified diff if x and y are similar
+// enough. Otherwise, it returns an empty result and false.
+//
+// The similarity of x and y is 2*M/(N+K), where M is the number of matching lines and N and K are
+// the number of lines in x and y. It's 1 for identical inputs and 0 if x and y have no line in
+// common. The diff is returned if the similarity is at least minRatio.
+//
+// The following options are supported: [diff.Context], [diff.Minimal], [diff.PreferLongMatches],
+// [diff.Fast], [diff.AutoFast], [diff.Parallel], [diff.PairedOrdering], [IndentHeuristic],
+// [NormalizeUnicode], [IgnoreMatching], [IgnoreComments], [IgnoreReorderedBlocks], [AnchorOn],
+// [FuzzyLines], [BlockContext], [FunctionContext], [MaxHunkLines], [MinHunkChanges],
+// [MaxLineWidth], [SanitizeInvalidUTF8], [ShowWhitespace], [TerminalColors], [OutputNewline],
+// [Labels], [IndexHeader], [HunkStatsInHeader]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func UnifiedIfSimilar[T string | []byte](x, y T, minRatio float64, opts ...Option) (T, bool) {
+	cfg := config.FromOptions(opts, config.Algorithm|lineFlags|hunkFlags|renderFlags)
+	d := diffLines(x, y, cfg)
+	if similarity(d) < minRatio {
+		var zero T
+		return zero, false
+	}
+	return unified[T](d, cfg), true
+}
 
-	if cfg.IndentHeuristic {
-		indentheuristic.Apply(xlines, ylines, rx, ry)
+// similarity returns 2*M/(N+K) for the line diff d, see [UnifiedIfSimilar].
+func similarity(d lineDiff) float64 {
+	n, k := len(d.x), len(d.y)
+	if n+k == 0 {
+		return 1
+	}
+	matches := 0
+	for _, r := range d.rx[:n] {
+		if !r {
+			matches++
+		}
 	}
+	return 2 * float64(matches) / float64(n+k)
+}
+
+const ellipsis = "…"
 
-	var colors config.ColorConfig
-	if cfg.Colors != nil {
-		colors = *cfg.Colors
+// displayLine returns line as it's written to the output, before truncation.
+func displayLine(line byteview.ByteView, cfg *config.Config) string {
+	s := byteview.UnsafeAs[string](line)
+	if cfg.SanitizeInvalidUTF8 && !utf8.ValidString(s) {
+		s = strings.ToValidUTF8(s, "\uFFFD")
 	}
+	if cfg.ShowWhitespace {
+		s = showWhitespace(s)
+	}
+	return s
+}
 
-	// Precompute output buffer size.
-	n := 0
-	for h := range rvecs.Hunks(rx, ry, cfg) {
-		n += len("@@ -, +, @@\n")
-		n += numDigits(h.S0+1) + numDigits(h.S1-h.S0) + numDigits(h.T0+1) + numDigits(h.T1-h.T0)
-		n += len(colors.HunkHeader) + len(colors.Reset)
-		for s, t := h.S0, h.T0; s < h.S1 || t < h.T1; {
-			if s < h.S1 && rx[s] {
-				n += len(colors.Delete) + len(colors.Reset)
-				for s < h.S1 && rx[s] {
-					n += 1 + xlines[s].Len()
-					s++
-				}
-			}
-			if t < h.T1 && ry[t] {
-				n += len(colors.Insert) + len(colors.Reset)
-				for t < h.T1 && ry[t] {
-					n += 1 + ylines[t].Len()
-					t++
-				}
-			}
-			if s < h.S1 && t < h.T1 && !rx[s] && !ry[t] {
-				n += len(colors.Match) + len(colors.Reset)
-				for s < h.S1 && t < h.T1 && !rx[s] && !ry[t] {
-					n += 1 + xlines[s].Len()
-					s++
-					t++
-				}
-			}
+// Glyphs for [ShowWhitespace].
+const (
+	tabGlyph   = "→"
+	spaceGlyph = "·"
+)
+
+// showWhitespace replaces tabs in the indentation and tabs and spaces at the end of the line with
+// visible glyphs.
+func showWhitespace(line string) string {
+	content, eol := splitLineEnding(line)
+	indent := len(content) - len(strings.TrimLeft(content, " \t"))
+	trailing := len(strings.TrimRight(content, " \t"))
+	if trailing == 0 {
+		// The line only consists of whitespace, all of it is trailing whitespace.
+		indent = 0
+	}
+	if !strings.Contains(content[:indent], "\t") && trailing == len(content) {
+		return line
+	}
+	var b strings.Builder
+	b.WriteString(strings.ReplaceAll(content[:indent], "\t", tabGlyph))
+	b.WriteString(content[indent:trailing])
+	for i := trailing; i < len(content); i++ {
+		if content[i] == '\t' {
+			b.WriteString(tabGlyph)
+		} else {
+			b.WriteString(spaceGlyph)
 		}
 	}
-	if xMissingNewline >= 0 {
-		n += len(missingNewline)
-	}
-	if yMissingNewline >= 0 {
-		n += len(missingNewline)
-	}
-
-	// Format output.
-	var b byteview.Builder[T]
-	b.Grow(n)
-	for h := range rvecs.Hunks(rx, ry, cfg) {
-		fmt.Fprintf(&b, "%s@@ -%d,%d +%d,%d @@%s\n", colors.HunkHeader, h.S0+1, h.S1-h.S0, h.T0+1, h.T1-h.T0, colors.Reset)
-		for s, t := h.S0, h.T0; s < h.S1 || t < h.T1; {
-			if s < h.S1 && rx[s] {
-				b.WriteString(colors.Delete)
-				for s < h.S1 && rx[s] {
-					b.WriteString(prefixDelete)
-					b.WriteByteView(xlines[s])
-					if s == xMissingNewline {
-						b.WriteString(missingNewline)
-					}
-					s++
-				}
-				b.WriteString(colors.Reset)
-			}
-			if t < h.T1 && ry[t] {
-				b.WriteString(colors.Insert)
-				for t < h.T1 && ry[t] {
-					b.WriteString(prefixInsert)
-					b.WriteByteView(ylines[t])
-					if t == yMissingNewline {
-						b.WriteString(missingNewline)
-					}
-					t++
-				}
-				b.WriteString(colors.Reset)
-			}
-			if s < h.S1 && t < h.T1 && !rx[s] && !ry[t] {
-				b.WriteString(colors.Match)
-				for s < h.S1 && t < h.T1 && !rx[s] && !ry[t] {
-					b.WriteString(prefixMatch)
-					b.WriteByteView(xlines[s])
-					if s == xMissingNewline {
-						b.WriteString(missingNewline)
-					}
-					s++
-					t++
-				}
-				b.WriteString(colors.Reset)
-			}
+	b.WriteString(eol)
+	return b.String()
+}
+
+// lineLen returns the length of line in the output.
+func lineLen(line byteview.ByteView, cfg *config.Config) int {
+	s := displayLine(line, cfg)
+	if cfg.MaxLineWidth == 0 {
+		return len(s)
+	}
+	head, rest := truncate(s, cfg.MaxLineWidth)
+	if len(rest) == 0 {
+		return len(s)
+	}
+	n := len(head) + len(ellipsis)
+	if strings.HasSuffix(rest, "\n") {
+		n++
+	}
+	return n
+}
+
+// writeLine writes line to w.
+func writeLine(w io.Writer, line byteview.ByteView, cfg *config.Config) {
+	s := displayLine(line, cfg)
+	if cfg.MaxLineWidth == 0 {
+		io.WriteString(w, s)
+		return
+	}
+	head, rest := truncate(s, cfg.MaxLineWidth)
+	io.WriteString(w, head)
+	if len(rest) == 0 {
+		return
+	}
+	io.WriteString(w, ellipsis)
+	if strings.HasSuffix(rest, "\n") {
+		io.WriteString(w, "\n")
+	}
+}
+
+// truncate splits line after width runes, not counting the newline character. If the line is
+// short enough, rest is empty.
+func truncate(line string, width int) (head, rest string) {
+	content := strings.TrimSuffix(line, "\n")
+	if len(content) <= width {
+		return line, "" // fast path: fewer bytes than runes
+	}
+	n := 0
+	for i := range content {
+		if n == width {
+			return line[:i], line[i:]
 		}
+		n++
 	}
-	return b.Build()
+	return line, ""
 }
 
 func numDigits(v int) (n int) {
@@ -354,3 +922,58 @@ func numDigits(v int) (n int) {
 		return n
 	}
 }
+
+// Equal reports whether x and y are equal line by line, i.e., whether [Unified] with the same
+// options returns an empty diff.
+//
+// Lines are compared including their newline characters, i.e. a line that's missing a newline
+// differs from the same line with a newline. Without options, this is the same as comparing x and
+// y directly. With options, the lines are compared after applying them. This is a lot cheaper than
+// computing a diff.
+//
+// The following options are supported: [NormalizeUnicode], [IgnoreMatching], [IgnoreComments]
+func Equal[T string | []byte](x, y T, opts ...Option) bool {
+	cfg := config.FromOptions(opts, config.NormalizeUnicode|config.IgnoreMatching|config.IgnoreComments)
+	sx := byteview.UnsafeAs[string](byteview.From(x))
+	sy := byteview.UnsafeAs[string](byteview.From(y))
+	if cfg.LineKey == nil || sx == sy {
+		return sx == sy
+	}
+	for {
+		if sx == "" || sy == "" {
+			return sx == sy
+		}
+		lx, ly := sx, sy
+		if i := strings.IndexByte(sx, '\n'); i >= 0 {
+			lx, sx = sx[:i+1], sx[i+1:]
+		} else {
+			sx = ""
+		}
+		if i := strings.IndexByte(sy, '\n'); i >= 0 {
+			ly, sy = sy[:i+1], sy[i+1:]
+		} else {
+			sy = ""
+		}
+		if cfg.LineKey(lx) != cfg.LineKey(ly) {
+			return false
+		}
+	}
+}
+
+// FirstDifferingLine returns the line number (one-based) of the first line where x and y differ. If
+// x and y are equal, it returns 0 and true.
+//
+// Lines are compared including their newline characters, i.e. a line that's missing a newline
+// differs from the same line with a newline. This is a lot cheaper than computing a diff.
+func FirstDifferingLine[T string | []byte](x, y T) (line int, equal bool) {
+	sx := byteview.UnsafeAs[string](byteview.From(x))
+	sy := byteview.UnsafeAs[string](byteview.From(y))
+	n := 0
+	for n < len(sx) && n < len(sy) && sx[n] == sy[n] {
+		n++
+	}
+	if n == len(sx) && n == len(sy) {
+		return 0, true
+	}
+	return strings.Count(sx[:n], "\n") + 1, false
+}
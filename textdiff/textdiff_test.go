@@ -17,13 +17,20 @@ package textdiff
 import (
 	"bytes"
 	"flag"
+	"fmt"
+	"math"
+	"math/rand/v2"
 	"os"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"golang.org/x/text/unicode/norm"
 	"golang.org/x/tools/txtar"
 	"znkr.io/diff"
 	"znkr.io/diff/internal/config"
@@ -43,11 +50,15 @@ func TestUnified(t *testing.T) {
 			for sti, st := range tt.subtests {
 				t.Run(st.name, func(t *testing.T) {
 					t.Parallel()
-					got := Unified(tt.x, tt.y, st.opts...)
+					render := Unified[[]byte]
+					if st.render != nil {
+						render = st.render
+					}
+					got := render(tt.x, tt.y, st.opts...)
 					if diff := cmp.Diff(st.want, got); diff != "" {
 						t.Errorf("Unified(...) result are different:\ngot:\n%s\nwant:\n%s\ndiff [-got,+want]:\n%s", got, st.want, diff)
 					}
-					if *validate && len(got) > 0 {
+					if *validate && !st.displayOnly && len(got) > 0 {
 						patched, err := unixpatch.Patch(string(tt.x), string(got))
 						if err != nil {
 							t.Fatalf("failed to run patch: %v", err)
@@ -326,6 +337,9 @@ func BenchmarkUnified(b *testing.B) {
 	for _, tt := range parseTests(b) {
 		b.Run(tt.name, func(b *testing.B) {
 			for _, st := range tt.subtests {
+				if st.render != nil {
+					continue
+				}
 				b.Run(st.name, func(b *testing.B) {
 					b.ReportAllocs()
 					for b.Loop() {
@@ -359,14 +373,16 @@ func TestHunks(t *testing.T) {
 			y:    "foo\nbar\nbaz\n",
 			want: []Hunk[string]{
 				{
-					LineNoX:    0,
-					LineNoY:    0,
-					EndLineNoX: 0,
-					EndLineNoY: 3,
+					LineNoX:     0,
+					LineNoY:     0,
+					EndLineNoX:  0,
+					EndLineNoY:  3,
+					AtFileStart: true,
+					AtFileEnd:   true,
 					Edits: []Edit[string]{
-						{diff.Insert, -1, 0, "foo\n"},
-						{diff.Insert, -1, 1, "bar\n"},
-						{diff.Insert, -1, 2, "baz\n"},
+						{diff.Insert, -1, 0, "foo\n", "", false},
+						{diff.Insert, -1, 1, "bar\n", "", false},
+						{diff.Insert, -1, 2, "baz\n", "", false},
 					},
 				},
 			},
@@ -376,14 +392,16 @@ func TestHunks(t *testing.T) {
 			x:    "foo\nbar\nbaz\n",
 			want: []Hunk[string]{
 				{
-					LineNoX:    0,
-					LineNoY:    0,
-					EndLineNoX: 3,
-					EndLineNoY: 0,
+					LineNoX:     0,
+					LineNoY:     0,
+					EndLineNoX:  3,
+					EndLineNoY:  0,
+					AtFileStart: true,
+					AtFileEnd:   true,
 					Edits: []Edit[string]{
-						{diff.Delete, 0, -1, "foo\n"},
-						{diff.Delete, 1, -1, "bar\n"},
-						{diff.Delete, 2, -1, "baz\n"},
+						{diff.Delete, 0, -1, "foo\n", "", false},
+						{diff.Delete, 1, -1, "bar\n", "", false},
+						{diff.Delete, 2, -1, "baz\n", "", false},
 					},
 				},
 			},
@@ -394,14 +412,16 @@ func TestHunks(t *testing.T) {
 			y:    "foo\nbaz\n",
 			want: []Hunk[string]{
 				{
-					LineNoX:    0,
-					EndLineNoX: 2,
-					LineNoY:    0,
-					EndLineNoY: 2,
+					LineNoX:     0,
+					EndLineNoX:  2,
+					LineNoY:     0,
+					EndLineNoY:  2,
+					AtFileStart: true,
+					AtFileEnd:   true,
 					Edits: []Edit[string]{
-						{diff.Match, 0, 0, "foo\n"},
-						{diff.Delete, 1, -1, "bar\n"},
-						{diff.Insert, -1, 1, "baz\n"},
+						{diff.Match, 0, 0, "foo\n", "", false},
+						{diff.Delete, 1, -1, "bar\n", "", false},
+						{diff.Insert, -1, 1, "baz\n", "", false},
 					},
 				},
 			},
@@ -412,14 +432,16 @@ func TestHunks(t *testing.T) {
 			y:    "loo\nbar\n",
 			want: []Hunk[string]{
 				{
-					LineNoX:    0,
-					EndLineNoX: 2,
-					LineNoY:    0,
-					EndLineNoY: 2,
+					LineNoX:     0,
+					EndLineNoX:  2,
+					LineNoY:     0,
+					EndLineNoY:  2,
+					AtFileStart: true,
+					AtFileEnd:   true,
 					Edits: []Edit[string]{
-						{diff.Delete, 0, -1, "foo\n"},
-						{diff.Insert, -1, 0, "loo\n"},
-						{diff.Match, 1, 1, "bar\n"},
+						{diff.Delete, 0, -1, "foo\n", "", false},
+						{diff.Insert, -1, 0, "loo\n", "", false},
+						{diff.Match, 1, 1, "bar\n", "", false},
 					},
 				},
 			},
@@ -430,20 +452,22 @@ func TestHunks(t *testing.T) {
 			y:    "C\nB\nA\nB\nA\nC\n",
 			want: []Hunk[string]{
 				{
-					LineNoX:    0,
-					LineNoY:    0,
-					EndLineNoX: 7,
-					EndLineNoY: 6,
+					LineNoX:     0,
+					LineNoY:     0,
+					EndLineNoX:  7,
+					EndLineNoY:  6,
+					AtFileStart: true,
+					AtFileEnd:   true,
 					Edits: []Edit[string]{
-						{diff.Delete, 0, -1, "A\n"},
-						{diff.Insert, -1, 0, "C\n"},
-						{diff.Match, 1, 1, "B\n"},
-						{diff.Delete, 2, -1, "C\n"},
-						{diff.Match, 3, 2, "A\n"},
-						{diff.Match, 4, 3, "B\n"},
-						{diff.Delete, 5, -1, "B\n"},
-						{diff.Match, 6, 4, "A\n"},
-						{diff.Insert, -1, 5, "C\n"},
+						{diff.Delete, 0, -1, "A\n", "", false},
+						{diff.Insert, -1, 0, "C\n", "", false},
+						{diff.Match, 1, 1, "B\n", "", false},
+						{diff.Delete, 2, -1, "C\n", "", false},
+						{diff.Match, 3, 2, "A\n", "", false},
+						{diff.Match, 4, 3, "B\n", "", false},
+						{diff.Delete, 5, -1, "B\n", "", false},
+						{diff.Match, 6, 4, "A\n", "", false},
+						{diff.Insert, -1, 5, "C\n", "", false},
 					},
 				},
 			},
@@ -455,13 +479,14 @@ func TestHunks(t *testing.T) {
 			opts: []diff.Option{diff.Context(0)},
 			want: []Hunk[string]{
 				{
-					LineNoX:    0,
-					LineNoY:    0,
-					EndLineNoX: 1,
-					EndLineNoY: 1,
+					LineNoX:     0,
+					LineNoY:     0,
+					EndLineNoX:  1,
+					EndLineNoY:  1,
+					AtFileStart: true,
 					Edits: []Edit[string]{
-						{diff.Delete, 0, -1, "A\n"},
-						{diff.Insert, -1, 0, "C\n"},
+						{diff.Delete, 0, -1, "A\n", "", false},
+						{diff.Insert, -1, 0, "C\n", "", false},
 					},
 				},
 				{
@@ -470,7 +495,7 @@ func TestHunks(t *testing.T) {
 					EndLineNoX: 3,
 					EndLineNoY: 2,
 					Edits: []Edit[string]{
-						{diff.Delete, 2, -1, "C\n"},
+						{diff.Delete, 2, -1, "C\n", "", false},
 					},
 				},
 				{
@@ -479,7 +504,7 @@ func TestHunks(t *testing.T) {
 					EndLineNoX: 6,
 					EndLineNoY: 4,
 					Edits: []Edit[string]{
-						{diff.Delete, 5, -1, "B\n"},
+						{diff.Delete, 5, -1, "B\n", "", false},
 					},
 				},
 				{
@@ -487,8 +512,9 @@ func TestHunks(t *testing.T) {
 					LineNoY:    5,
 					EndLineNoX: 7,
 					EndLineNoY: 6,
+					AtFileEnd:  true,
 					Edits: []Edit[string]{
-						{diff.Insert, -1, 5, "C\n"},
+						{diff.Insert, -1, 5, "C\n", "", false},
 					},
 				},
 			},
@@ -520,17 +546,18 @@ new hunk
 `,
 			want: []Hunk[string]{
 				{
-					LineNoX:    0,
-					EndLineNoX: 3,
-					LineNoY:    0,
-					EndLineNoY: 6,
+					LineNoX:     0,
+					EndLineNoX:  3,
+					LineNoY:     0,
+					EndLineNoY:  6,
+					AtFileStart: true,
 					Edits: []Edit[string]{
-						{diff.Insert, -1, 0, "this is a new paragraph\n"},
-						{diff.Insert, -1, 1, "that is inserted at the top\n"},
-						{diff.Insert, -1, 2, "\n"},
-						{diff.Match, 0, 3, "this paragraph\n"},
-						{diff.Match, 1, 4, "is not\n"},
-						{diff.Match, 2, 5, "changed and\n"},
+						{diff.Insert, -1, 0, "this is a new paragraph\n", "", false},
+						{diff.Insert, -1, 1, "that is inserted at the top\n", "", false},
+						{diff.Insert, -1, 2, "\n", "", false},
+						{diff.Match, 0, 3, "this paragraph\n", "", false},
+						{diff.Match, 1, 4, "is not\n", "", false},
+						{diff.Match, 2, 5, "changed and\n", "", false},
 					},
 				},
 				{
@@ -538,14 +565,15 @@ new hunk
 					EndLineNoX: 11,
 					LineNoY:    7,
 					EndLineNoY: 10,
+					AtFileEnd:  true,
 					Edits: []Edit[string]{
-						{diff.Match, 4, 7, "enough to\n"},
-						{diff.Match, 5, 8, "create a\n"},
-						{diff.Match, 6, 9, "new hunk\n"},
-						{diff.Delete, 7, -1, "\n"},
-						{diff.Delete, 8, -1, "this paragraph\n"},
-						{diff.Delete, 9, -1, "is going to be\n"},
-						{diff.Delete, 10, -1, "removed\n"},
+						{diff.Match, 4, 7, "enough to\n", "", false},
+						{diff.Match, 5, 8, "create a\n", "", false},
+						{diff.Match, 6, 9, "new hunk\n", "", false},
+						{diff.Delete, 7, -1, "\n", "", false},
+						{diff.Delete, 8, -1, "this paragraph\n", "", false},
+						{diff.Delete, 9, -1, "is going to be\n", "", false},
+						{diff.Delete, 10, -1, "removed\n", "", false},
 					},
 				},
 			},
@@ -573,23 +601,25 @@ new hunk
 `,
 			want: []Hunk[string]{
 				{
-					LineNoX:    0,
-					EndLineNoX: 9,
-					LineNoY:    0,
-					EndLineNoY: 8,
+					LineNoX:     0,
+					EndLineNoX:  9,
+					LineNoY:     0,
+					EndLineNoY:  8,
+					AtFileStart: true,
+					AtFileEnd:   true,
 					Edits: []Edit[string]{
-						{diff.Insert, -1, 0, "this is a new paragraph\n"},
-						{diff.Insert, -1, 1, "that is inserted at the top\n"},
-						{diff.Insert, -1, 2, "\n"},
-						{diff.Match, 0, 3, "this paragraph\n"},
-						{diff.Match, 1, 4, "stays but is\n"},
-						{diff.Match, 2, 5, "not long enough\n"},
-						{diff.Match, 3, 6, "to create a\n"},
-						{diff.Match, 4, 7, "new hunk\n"},
-						{diff.Delete, 5, -1, "\n"},
-						{diff.Delete, 6, -1, "this paragraph\n"},
-						{diff.Delete, 7, -1, "is going to be\n"},
-						{diff.Delete, 8, -1, "removed\n"},
+						{diff.Insert, -1, 0, "this is a new paragraph\n", "", false},
+						{diff.Insert, -1, 1, "that is inserted at the top\n", "", false},
+						{diff.Insert, -1, 2, "\n", "", false},
+						{diff.Match, 0, 3, "this paragraph\n", "", false},
+						{diff.Match, 1, 4, "stays but is\n", "", false},
+						{diff.Match, 2, 5, "not long enough\n", "", false},
+						{diff.Match, 3, 6, "to create a\n", "", false},
+						{diff.Match, 4, 7, "new hunk\n", "", false},
+						{diff.Delete, 5, -1, "\n", "", false},
+						{diff.Delete, 6, -1, "this paragraph\n", "", false},
+						{diff.Delete, 7, -1, "is going to be\n", "", false},
+						{diff.Delete, 8, -1, "removed\n", "", false},
 					},
 				},
 			},
@@ -611,18 +641,20 @@ end
 			opts: []diff.Option{IndentHeuristic()},
 			want: []Hunk[string]{
 				{
-					LineNoX:    0,
-					EndLineNoX: 3,
-					LineNoY:    0,
-					EndLineNoY: 7,
+					LineNoX:     0,
+					EndLineNoX:  3,
+					LineNoY:     0,
+					EndLineNoY:  7,
+					AtFileStart: true,
+					AtFileEnd:   true,
 					Edits: []Edit[string]{
-						{diff.Insert, -1, 0, `["foo", "bar", "baz"].map do |i|` + "\n"},
-						{diff.Insert, -1, 1, `  i` + "\n"},
-						{diff.Insert, -1, 2, `end` + "\n"},
-						{diff.Insert, -1, 3, "\n"},
-						{diff.Match, 0, 4, `["foo", "bar", "baz"].map do |i|` + "\n"},
-						{diff.Match, 1, 5, `  i.upcase` + "\n"},
-						{diff.Match, 2, 6, `end` + "\n"},
+						{diff.Insert, -1, 0, `["foo", "bar", "baz"].map do |i|` + "\n", "", false},
+						{diff.Insert, -1, 1, `  i` + "\n", "", false},
+						{diff.Insert, -1, 2, `end` + "\n", "", false},
+						{diff.Insert, -1, 3, "\n", "", false},
+						{diff.Match, 0, 4, `["foo", "bar", "baz"].map do |i|` + "\n", "", false},
+						{diff.Match, 1, 5, `  i.upcase` + "\n", "", false},
+						{diff.Match, 2, 6, `end` + "\n", "", false},
 					},
 				},
 			},
@@ -651,9 +683,9 @@ func TestEdits(t *testing.T) {
 			x:    "foo\nbar\nbaz\n",
 			y:    "foo\nbar\nbaz\n",
 			want: []Edit[string]{
-				{diff.Match, 0, 0, "foo\n"},
-				{diff.Match, 1, 1, "bar\n"},
-				{diff.Match, 2, 2, "baz\n"},
+				{diff.Match, 0, 0, "foo\n", "", false},
+				{diff.Match, 1, 1, "bar\n", "", false},
+				{diff.Match, 2, 2, "baz\n", "", false},
 			},
 		},
 		{
@@ -663,18 +695,18 @@ func TestEdits(t *testing.T) {
 			name: "x-empty",
 			y:    "foo\nbar\nbaz\n",
 			want: []Edit[string]{
-				{diff.Insert, -1, 0, "foo\n"},
-				{diff.Insert, -1, 1, "bar\n"},
-				{diff.Insert, -1, 2, "baz\n"},
+				{diff.Insert, -1, 0, "foo\n", "", false},
+				{diff.Insert, -1, 1, "bar\n", "", false},
+				{diff.Insert, -1, 2, "baz\n", "", false},
 			},
 		},
 		{
 			name: "y-empty",
 			x:    "foo\nbar\nbaz\n",
 			want: []Edit[string]{
-				{diff.Delete, 0, -1, "foo\n"},
-				{diff.Delete, 1, -1, "bar\n"},
-				{diff.Delete, 2, -1, "baz\n"},
+				{diff.Delete, 0, -1, "foo\n", "", false},
+				{diff.Delete, 1, -1, "bar\n", "", false},
+				{diff.Delete, 2, -1, "baz\n", "", false},
 			},
 		},
 		{
@@ -682,15 +714,15 @@ func TestEdits(t *testing.T) {
 			x:    "A\nB\nC\nA\nB\nB\nA\n",
 			y:    "C\nB\nA\nB\nA\nC\n",
 			want: []Edit[string]{
-				{diff.Delete, 0, -1, "A\n"},
-				{diff.Insert, -1, 0, "C\n"},
-				{diff.Match, 1, 1, "B\n"},
-				{diff.Delete, 2, -1, "C\n"},
-				{diff.Match, 3, 2, "A\n"},
-				{diff.Match, 4, 3, "B\n"},
-				{diff.Delete, 5, -1, "B\n"},
-				{diff.Match, 6, 4, "A\n"},
-				{diff.Insert, -1, 5, "C\n"},
+				{diff.Delete, 0, -1, "A\n", "", false},
+				{diff.Insert, -1, 0, "C\n", "", false},
+				{diff.Match, 1, 1, "B\n", "", false},
+				{diff.Delete, 2, -1, "C\n", "", false},
+				{diff.Match, 3, 2, "A\n", "", false},
+				{diff.Match, 4, 3, "B\n", "", false},
+				{diff.Delete, 5, -1, "B\n", "", false},
+				{diff.Match, 6, 4, "A\n", "", false},
+				{diff.Insert, -1, 5, "C\n", "", false},
 			},
 		},
 		{
@@ -698,9 +730,9 @@ func TestEdits(t *testing.T) {
 			x:    "foo\nbar\n",
 			y:    "foo\nbaz\n",
 			want: []Edit[string]{
-				{diff.Match, 0, 0, "foo\n"},
-				{diff.Delete, 1, -1, "bar\n"},
-				{diff.Insert, -1, 1, "baz\n"},
+				{diff.Match, 0, 0, "foo\n", "", false},
+				{diff.Delete, 1, -1, "bar\n", "", false},
+				{diff.Insert, -1, 1, "baz\n", "", false},
 			},
 		},
 		{
@@ -708,9 +740,9 @@ func TestEdits(t *testing.T) {
 			x:    "foo\nbar\n",
 			y:    "loo\nbar\n",
 			want: []Edit[string]{
-				{diff.Delete, 0, -1, "foo\n"},
-				{diff.Insert, -1, 0, "loo\n"},
-				{diff.Match, 1, 1, "bar\n"},
+				{diff.Delete, 0, -1, "foo\n", "", false},
+				{diff.Insert, -1, 0, "loo\n", "", false},
+				{diff.Match, 1, 1, "bar\n", "", false},
 			},
 		},
 		{
@@ -729,13 +761,13 @@ end
 `,
 			opts: []diff.Option{IndentHeuristic()},
 			want: []Edit[string]{
-				{diff.Insert, -1, 0, `["foo", "bar", "baz"].map do |i|` + "\n"},
-				{diff.Insert, -1, 1, `  i` + "\n"},
-				{diff.Insert, -1, 2, `end` + "\n"},
-				{diff.Insert, -1, 3, "\n"},
-				{diff.Match, 0, 4, `["foo", "bar", "baz"].map do |i|` + "\n"},
-				{diff.Match, 1, 5, `  i.upcase` + "\n"},
-				{diff.Match, 2, 6, `end` + "\n"},
+				{diff.Insert, -1, 0, `["foo", "bar", "baz"].map do |i|` + "\n", "", false},
+				{diff.Insert, -1, 1, `  i` + "\n", "", false},
+				{diff.Insert, -1, 2, `end` + "\n", "", false},
+				{diff.Insert, -1, 3, "\n", "", false},
+				{diff.Match, 0, 4, `["foo", "bar", "baz"].map do |i|` + "\n", "", false},
+				{diff.Match, 1, 5, `  i.upcase` + "\n", "", false},
+				{diff.Match, 2, 6, `end` + "\n", "", false},
 			},
 		},
 	}
@@ -750,123 +782,1727 @@ end
 	}
 }
 
-type test struct {
-	name     string
-	filename string
-	comment  []byte
-	x, y     []byte
-	subtests []subtest
+func TestNormalizeUnicode(t *testing.T) {
+	nfc := "caf\u00e9\nna\u00efve\nr\u00e9sum\u00e9\n"
+	nfd := "cafe\u0301\nnai\u0308ve\nre\u0301sume\u0301\n"
+	changed := "cafe\u0301\nnaive\nre\u0301sume\u0301\n"
+	tests := []struct {
+		name string
+		x, y string
+		opts []diff.Option
+		want string
+	}{
+		{
+			name: "without-normalization",
+			x:    nfc,
+			y:    nfd,
+			want: "@@ -1,3 +1,3 @@\n-caf\u00e9\n-na\u00efve\n-r\u00e9sum\u00e9\n+cafe\u0301\n+nai\u0308ve\n+re\u0301sume\u0301\n",
+		},
+		{
+			name: "nfc",
+			x:    nfc,
+			y:    nfd,
+			opts: []diff.Option{NormalizeUnicode(norm.NFC)},
+			want: "",
+		},
+		{
+			name: "nfd",
+			x:    nfc,
+			y:    nfd,
+			opts: []diff.Option{NormalizeUnicode(norm.NFD)},
+			want: "",
+		},
+		{
+			name: "real-change",
+			x:    nfc,
+			y:    changed,
+			opts: []diff.Option{NormalizeUnicode(norm.NFC)},
+			want: "@@ -1,3 +1,3 @@\n caf\u00e9\n-na\u00efve\n+naive\n r\u00e9sum\u00e9\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unified(tt.x, tt.y, tt.opts...)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Unified(...) result are different:\ngot:\n%s\nwant:\n%s\ndiff [-want,+got]:\n%s", got, tt.want, diff)
+			}
+		})
+	}
 }
 
-type subtest struct {
-	name    string
-	opts    []config.Option
-	pragmas []byte
-	want    []byte
+func TestSanitizeInvalidUTF8(t *testing.T) {
+	x := "a\n\xffb\nc\xe2\n"
+	y := "a\n\xfeb\nc\xe2\n"
+
+	// Both lines render the same, but the diff is still computed on the original bytes.
+	want := "@@ -1,3 +1,3 @@\n a\n-\ufffdb\n+\ufffdb\n c\ufffd\n"
+	got := Unified(x, y, SanitizeInvalidUTF8())
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unified(...) result are different [-want,+got]:\n%s", diff)
+	}
+
+	// Without the option, the original bytes are retained.
+	want = "@@ -1,3 +1,3 @@\n a\n-\xffb\n+\xfeb\n c\xe2\n"
+	got = Unified(x, y)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unified(...) result are different [-want,+got]:\n%s", diff)
+	}
 }
 
-func parseTests(t testing.TB) []test {
-	t.Helper()
-	testFiles, err := filepath.Glob("testdata/*.test")
-	if err != nil {
-		t.Fatalf("Failed to read testdata: %v", err)
+func TestShowWhitespace(t *testing.T) {
+	// Trailing whitespace before a CRLF line ending and a missing newline at the end.
+	x := "a\r\n\tb\r\nc"
+	y := "a\r\n\tb \r\nc\t"
+	want := "@@ -1,3 +1,3 @@\n a\r\n-→b\r\n-c\n\\ No newline at end of file\n+→b·\r\n+c→\n\\ No newline at end of file\n"
+	got := Unified(x, y, ShowWhitespace())
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unified(...) result are different [-want,+got]:\n%s", diff)
 	}
-	var tests []test
-	for _, filename := range testFiles {
-		ar, err := txtar.ParseFile(filename)
-		if err != nil {
-			t.Fatalf("failed to parse test case: %v", err)
-		}
-		name := strings.TrimPrefix(filename, "testdata/")
-		test := test{
-			name:     name,
-			filename: filename,
-			comment:  ar.Comment,
-		}
 
-		for _, f := range ar.Files {
-			switch f.Name {
-			case "x":
-				test.x = f.Data
-			case "y":
-				test.y = f.Data
-			case "diff":
-				data := f.Data
-				var st subtest
-				var name []string
-				i := 0
-				for ; i < len(data); i++ {
-					if data[i] != '#' {
-						break
-					}
-					i++
-					eol := i + bytes.IndexByte(data[i:], '\n')
-					if eol < i {
-						t.Fatal("failed to parse test case: missing newline after pragma line")
-					}
-					k, v, found := bytes.Cut(data[i:eol], []byte{':'})
-					if !found {
-						t.Fatal("failed to parse test case: missing ':' in pragma line")
-					}
-					switch k, v := strings.TrimSpace(string(k)), strings.TrimSpace(string(v)); k {
-					case "indent-heuristic":
-						switch v {
-						case "true":
-							st.opts = append(st.opts, IndentHeuristic())
-						case "false":
-							// do nothing
-						default:
-							t.Fatalf("invalid value for indent-heuristic: %q", v)
-						}
-						name = append(name, k)
-					case "force-anchoring-heuristic":
-						switch v {
-						case "true":
-							// The inline function definition is necessary, because the anchoring
-							// heuristic is not exported as an option.
-							st.opts = append(st.opts, func(cfg *config.Config) config.Flag {
-								cfg.ForceAnchoringHeuristic = true
-								return 0
-							})
-						case "false":
-							// do nothing
-						default:
-							t.Fatalf("invalid value for force-anchoring-heuristic: %q", v)
-						}
-						name = append(name, k)
-					case "fast":
-						switch v {
-						case "true":
-							st.opts = append(st.opts, diff.Fast())
-						case "false":
-							// do nothing
-						default:
-							t.Fatalf("invalid value for fast: %q", v)
-						}
-					case "context":
-						n, err := strconv.ParseInt(v, 10, 64)
-						if err != nil {
-							t.Fatalf("invalid value for context: %v", err.Error())
-						}
-						st.opts = append(st.opts, diff.Context(int(n)))
-						name = append(name, k+"="+v)
-					default:
-						t.Fatalf("unknown option: %q", k)
-					}
-					i = eol
+	var w strings.Builder
+	if err := WriteUnified(&w, x, y, ShowWhitespace()); err != nil {
+		t.Fatalf("WriteUnified(...) failed: %v", err)
+	}
+	if diff := cmp.Diff(want, w.String()); diff != "" {
+		t.Errorf("WriteUnified(...) result are different [-want,+got]:\n%s", diff)
+	}
+}
+
+func TestOutputNewlineMissingNewline(t *testing.T) {
+	x := "a\r\nb"
+	y := "a\r\nc"
+	want := "@@ -1,2 +1,2 @@\r\n a\r\n-b\r\n\\ No newline at end of file\r\n+c\r\n\\ No newline at end of file\r\n"
+	got := Unified(x, y, OutputNewline("\r\n"))
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Unified(...) result are different [-want,+got]:\n%s", diff)
+	}
+
+	var w strings.Builder
+	if err := WriteUnified(&w, x, y, OutputNewline("\r\n")); err != nil {
+		t.Fatalf("WriteUnified(...) failed: %v", err)
+	}
+	if diff := cmp.Diff(want, w.String()); diff != "" {
+		t.Errorf("WriteUnified(...) result are different [-want,+got]:\n%s", diff)
+	}
+}
+
+func TestFileHeaderEmptyDiff(t *testing.T) {
+	opts := []Option{Labels("a/file", "b/file"), IndexHeader("1234567", "abcdef0", "100644")}
+	if got := Unified("a\nb\n", "a\nb\n", opts...); got != "" {
+		t.Errorf("Unified(...) = %q, want empty output", got)
+	}
+	var w strings.Builder
+	if err := WriteUnified(&w, "a\nb\n", "a\nb\n", opts...); err != nil {
+		t.Fatalf("WriteUnified(...) failed: %v", err)
+	}
+	if got := w.String(); got != "" {
+		t.Errorf("WriteUnified(...) = %q, want empty output", got)
+	}
+}
+
+func TestOutputNewlineInvalid(t *testing.T) {
+	for _, nl := range []string{"", "\r", "\n\r", "x"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("OutputNewline(%q) didn't panic", nl)
 				}
-				if len(name) == 0 {
-					name = append(name, "default")
+			}()
+			OutputNewline(nl)
+		}()
+	}
+}
+
+func TestIgnoreMatching(t *testing.T) {
+	generated := func(line string) bool { return strings.HasPrefix(line, "Generated: ") }
+	x := "// Code generated by gen.\nGenerated: 2025-01-01\n\nfoo\nbar\n"
+	tests := []struct {
+		name string
+		y    string
+		opts []diff.Option
+		want string
+	}{
+		{
+			name: "without-ignore",
+			y:    "// Code generated by gen.\nGenerated: 2025-02-01\n\nfoo\nbar\n",
+			want: "@@ -1,5 +1,5 @@\n // Code generated by gen.\n-Generated: 2025-01-01\n+Generated: 2025-02-01\n \n foo\n bar\n",
+		},
+		{
+			name: "ignored",
+			y:    "// Code generated by gen.\nGenerated: 2025-02-01\n\nfoo\nbar\n",
+			opts: []diff.Option{IgnoreMatching(generated)},
+			want: "",
+		},
+		{
+			name: "rendered-from-x",
+			y:    "// Code generated by gen.\nGenerated: 2025-02-01\n\nfoo\nbaz\n",
+			opts: []diff.Option{IgnoreMatching(generated)},
+			want: "@@ -2,4 +2,4 @@\n Generated: 2025-01-01\n \n foo\n-bar\n+baz\n",
+		},
+		{
+			name: "only-in-x",
+			y:    "// Code generated by gen.\n\nfoo\nbar\n",
+			opts: []diff.Option{IgnoreMatching(generated)},
+			want: "@@ -1,5 +1,4 @@\n // Code generated by gen.\n-Generated: 2025-01-01\n \n foo\n bar\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unified(x, tt.y, tt.opts...)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Unified(...) result is different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIgnoreComments(t *testing.T) {
+	x := "# Server configuration\nhost = example.com\nport = 8080  # default port\n\n# Logging\nlevel = info\n"
+	tests := []struct {
+		name string
+		y    string
+		opts []diff.Option
+		want string
+	}{
+		{
+			name: "without-ignore",
+			y:    "# Server configuration\nhost = example.com\nport = 8080 # the port\n\n# Logging\nlevel = info\n",
+			want: "@@ -1,6 +1,6 @@\n # Server configuration\n host = example.com\n-port = 8080  # default port\n+port = 8080 # the port\n \n # Logging\n level = info\n",
+		},
+		{
+			name: "trailing-comment",
+			y:    "# Server configuration\nhost = example.com\nport = 8080 # the port\n\n# Logging\nlevel = info\n",
+			opts: []diff.Option{IgnoreComments("#")},
+			want: "",
+		},
+		{
+			name: "comment-only-lines",
+			y:    "# Configuration of the server\nhost = example.com\nport = 8080\n\n  # Log settings\nlevel = info\n",
+			opts: []diff.Option{IgnoreComments("#")},
+			want: "",
+		},
+		{
+			name: "comment-is-not-empty-line",
+			y:    "# Server configuration\nhost = example.com\nport = 8080  # default port\n# Logging\n\nlevel = info\n",
+			opts: []diff.Option{IgnoreComments("#")},
+			want: "@@ -1,6 +1,6 @@\n # Server configuration\n host = example.com\n port = 8080  # default port\n+# Logging\n \n-# Logging\n level = info\n",
+		},
+		{
+			name: "code-change",
+			y:    "# Server configuration\nhost = example.com\nport = 9090  # default port\n\n# Logging\nlevel = debug # more output\n",
+			opts: []diff.Option{IgnoreComments("#")},
+			want: "@@ -1,6 +1,6 @@\n # Server configuration\n host = example.com\n-port = 8080  # default port\n+port = 9090  # default port\n \n # Logging\n-level = info\n+level = debug # more output\n",
+		},
+		{
+			name: "func",
+			y:    "// Server configuration\nhost = example.com\nport = 8080  // default port\n\n# Logging\nlevel = info\n",
+			opts: []diff.Option{IgnoreCommentsFunc(func(line string) int {
+				if i := strings.Index(line, "#"); i >= 0 {
+					return i
 				}
-				st.name = strings.Join(name, ":")
-				st.pragmas = data[:i]
-				st.want = data[i:]
-				test.subtests = append(test.subtests, st)
-			default:
-				t.Fatalf("unknown file in archive: %v", f)
+				return strings.Index(line, "//")
+			})},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unified(x, tt.y, tt.opts...)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Unified(...) result is different (-want, +got):\n%s", diff)
 			}
+		})
+	}
+}
+
+func TestMaxHunkLines(t *testing.T) {
+	// A replaced file: a single hunk with 50 deletions and 50 insertions.
+	var x, y strings.Builder
+	for i := range 50 {
+		fmt.Fprintf(&x, "old %d\n", i)
+		fmt.Fprintf(&y, "new %d\n", i)
+	}
+	hunks := Hunks(x.String(), y.String(), MaxHunkLines(40))
+	type header struct{ x0, x1, y0, y1, edits int }
+	var got []header
+	for _, h := range hunks {
+		got = append(got, header{h.LineNoX, h.EndLineNoX, h.LineNoY, h.EndLineNoY, len(h.Edits)})
+	}
+	want := []header{
+		{0, 40, 0, 0, 40},
+		{40, 50, 0, 30, 40},
+		{50, 50, 30, 50, 20},
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(header{})); diff != "" {
+		t.Errorf("Hunks(...) result is different (-want, +got):\n%s", diff)
+	}
+
+	// MaxHunks limits the number of hunks after splitting.
+	hunks, omitted := HunksTruncated(x.String(), y.String(), MaxHunkLines(40), diff.MaxHunks(1))
+	got = got[:0]
+	for _, h := range hunks {
+		got = append(got, header{h.LineNoX, h.EndLineNoX, h.LineNoY, h.EndLineNoY, len(h.Edits)})
+	}
+	if diff := cmp.Diff(want[:1], got, cmp.AllowUnexported(header{})); diff != "" {
+		t.Errorf("HunksTruncated(..., MaxHunks(1)) result is different (-want, +got):\n%s", diff)
+	}
+	if omitted != 2 {
+		t.Errorf("HunksTruncated(..., MaxHunks(1)) omitted %d hunks, want 2", omitted)
+	}
+
+	// Hunks are split after a matching line, if possible.
+	unified := Unified("a\nb\nc\nd\ne\n", "A\nB\nc\nD\nE\n", MaxHunkLines(6))
+	wantUnified := "@@ -1,3 +1,3 @@\n-a\n-b\n+A\n+B\n c\n@@ -4,2 +4,2 @@\n-d\n-e\n+D\n+E\n"
+	if diff := cmp.Diff(wantUnified, unified); diff != "" {
+		t.Errorf("Unified(...) result is different (-want, +got):\n%s", diff)
+	}
+
+	// With context, the leading and trailing context never end up in a hunk of their own.
+	x.Reset()
+	y.Reset()
+	for i := range 50 {
+		fmt.Fprintf(&x, "line %d\n", i)
+		if i >= 20 && i < 30 {
+			fmt.Fprintf(&y, "new %d\n", i)
+		} else {
+			fmt.Fprintf(&y, "line %d\n", i)
 		}
-		tests = append(tests, test)
 	}
-	return tests
+	hunks = Hunks(x.String(), y.String(), MaxHunkLines(10))
+	got = got[:0]
+	for _, h := range hunks {
+		got = append(got, header{h.LineNoX, h.EndLineNoX, h.LineNoY, h.EndLineNoY, len(h.Edits)})
+	}
+	want = []header{
+		{17, 27, 17, 20, 10},
+		{27, 30, 20, 27, 10},
+		{30, 33, 27, 33, 6},
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(header{})); diff != "" {
+		t.Errorf("Hunks(...) with context result is different (-want, +got):\n%s", diff)
+	}
+	for _, h := range hunks {
+		if deletes, inserts := HunkRanges(h); len(deletes) == 0 && len(inserts) == 0 {
+			t.Errorf("Hunks(...) returned a hunk without changes: %v", h)
+		}
+	}
+}
+
+func TestMinHunkChanges(t *testing.T) {
+	// Three hunks with 2, 6, and 1 changes respectively.
+	var x, y strings.Builder
+	for i := range 20 {
+		fmt.Fprintf(&x, "line %d\n", i)
+		switch {
+		case i == 1 || i >= 9 && i <= 11:
+			fmt.Fprintf(&y, "LINE %d\n", i)
+		case i == 17:
+			// deleted
+		default:
+			fmt.Fprintf(&y, "line %d\n", i)
+		}
+	}
+	type header struct{ x0, x1, y0, y1 int }
+	tests := []struct {
+		name string
+		opts []diff.Option
+		want []header
+	}{
+		{
+			name: "none",
+			opts: []diff.Option{diff.Context(1)},
+			want: []header{{0, 3, 0, 3}, {8, 13, 8, 13}, {16, 19, 16, 18}},
+		},
+		{
+			name: "min-1",
+			opts: []diff.Option{diff.Context(1), MinHunkChanges(1)},
+			want: []header{{0, 3, 0, 3}, {8, 13, 8, 13}, {16, 19, 16, 18}},
+		},
+		{
+			name: "min-2",
+			opts: []diff.Option{diff.Context(1), MinHunkChanges(2)},
+			want: []header{{0, 3, 0, 3}, {8, 13, 8, 13}},
+		},
+		{
+			name: "min-3",
+			opts: []diff.Option{diff.Context(1), MinHunkChanges(3)},
+			want: []header{{8, 13, 8, 13}},
+		},
+		{
+			name: "min-7",
+			opts: []diff.Option{diff.Context(1), MinHunkChanges(7)},
+		},
+		{
+			name: "min-3-max-hunks",
+			opts: []diff.Option{diff.Context(1), MinHunkChanges(3), diff.MaxHunks(1)},
+			want: []header{{8, 13, 8, 13}},
+		},
+		{
+			name: "min-3-max-hunk-lines",
+			// Parts of a split hunk are kept, even if they have fewer changes.
+			opts: []diff.Option{diff.Context(1), MinHunkChanges(3), MaxHunkLines(5)},
+			want: []header{{8, 12, 8, 10}, {12, 13, 10, 13}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []header
+			for _, h := range Hunks(x.String(), y.String(), tt.opts...) {
+				got = append(got, header{h.LineNoX, h.EndLineNoX, h.LineNoY, h.EndLineNoY})
+			}
+			if diff := cmp.Diff(tt.want, got, cmp.AllowUnexported(header{})); diff != "" {
+				t.Errorf("Hunks(...) result is different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+
+	unified := Unified(x.String(), y.String(), diff.Context(1), MinHunkChanges(3))
+	want := "@@ -9,5 +9,5 @@\n line 8\n-line 9\n-line 10\n-line 11\n+LINE 9\n+LINE 10\n+LINE 11\n line 12\n"
+	if diff := cmp.Diff(want, unified); diff != "" {
+		t.Errorf("Unified(...) result is different (-want, +got):\n%s", diff)
+	}
+}
+
+func TestIgnoreReorderedBlocks(t *testing.T) {
+	imports := func(line string) bool { return strings.HasPrefix(line, "\t\"") }
+	x := "package main\n\nimport (\n\t\"fmt\"\n\t\"os\"\n\t\"strings\"\n)\n\nfunc main() {}\n"
+	tests := []struct {
+		name string
+		y    string
+		opts []diff.Option
+		want string
+	}{
+		{
+			name: "without-ignore",
+			y:    "package main\n\nimport (\n\t\"os\"\n\t\"strings\"\n\t\"fmt\"\n)\n\nfunc main() {}\n",
+			want: "@@ -1,9 +1,9 @@\n package main\n \n import (\n-\t\"fmt\"\n \t\"os\"\n \t\"strings\"\n+\t\"fmt\"\n )\n \n func main() {}\n",
+		},
+		{
+			name: "reordered",
+			y:    "package main\n\nimport (\n\t\"os\"\n\t\"strings\"\n\t\"fmt\"\n)\n\nfunc main() {}\n",
+			opts: []diff.Option{IgnoreReorderedBlocks(imports)},
+			want: "",
+		},
+		{
+			name: "reordered-and-changed",
+			y:    "package main\n\nimport (\n\t\"strings\"\n\t\"os\"\n\t\"fmt\"\n)\n\nfunc main() {\n}\n",
+			opts: []diff.Option{IgnoreReorderedBlocks(imports)},
+			want: "@@ -6,4 +6,5 @@\n \t\"strings\"\n )\n \n-func main() {}\n+func main() {\n+}\n",
+		},
+		{
+			name: "added-import",
+			y:    "package main\n\nimport (\n\t\"os\"\n\t\"fmt\"\n\t\"slices\"\n\t\"strings\"\n)\n\nfunc main() {}\n",
+			opts: []diff.Option{IgnoreReorderedBlocks(imports)},
+			want: "@@ -1,8 +1,9 @@\n package main\n \n import (\n+\t\"os\"\n \t\"fmt\"\n-\t\"os\"\n+\t\"slices\"\n \t\"strings\"\n )\n \n",
+		},
+		{
+			name: "block-moved",
+			y:    "package main\n\nfunc main() {}\n\nimport (\n\t\"strings\"\n\t\"fmt\"\n\t\"os\"\n)\n",
+			opts: []diff.Option{IgnoreReorderedBlocks(imports)},
+			want: "@@ -1,9 +1,9 @@\n package main\n \n+func main() {}\n+\n import (\n \t\"fmt\"\n \t\"os\"\n \t\"strings\"\n )\n-\n-func main() {}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unified(x, tt.y, tt.opts...)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Unified(...) result is different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMatchLineY(t *testing.T) {
+	generated := func(line string) bool { return strings.HasPrefix(line, "Generated: ") }
+	tests := []struct {
+		name string
+		x, y string
+		opts []diff.Option
+		want []Edit[string]
+	}{
+		{
+			name: "byte-equality",
+			x:    "foo\nbar\n",
+			y:    "foo\nbaz\n",
+			want: []Edit[string]{
+				{diff.Match, 0, 0, "foo\n", "", false},
+				{diff.Delete, 1, -1, "bar\n", "", false},
+				{diff.Insert, -1, 1, "baz\n", "", false},
+			},
+		},
+		{
+			name: "ignore-matching",
+			x:    "Generated: 2025-01-01\nfoo\n",
+			y:    "Generated: 2025-02-01\nfoo\n",
+			opts: []diff.Option{IgnoreMatching(generated)},
+			want: []Edit[string]{
+				{diff.Match, 0, 0, "Generated: 2025-01-01\n", "Generated: 2025-02-01\n", false},
+				{diff.Match, 1, 1, "foo\n", "", false},
+			},
+		},
+		{
+			name: "normalize-unicode",
+			x:    "caf\u00e9\nfoo\n",
+			y:    "cafe\u0301\nfoo\n",
+			opts: []diff.Option{NormalizeUnicode(norm.NFC)},
+			want: []Edit[string]{
+				{diff.Match, 0, 0, "caf\u00e9\n", "cafe\u0301\n", false},
+				{diff.Match, 1, 1, "foo\n", "", false},
+			},
+		},
+		{
+			name: "fuzzy-lines",
+			x:    "2025-01-01 12:00:00 start\n",
+			y:    "2025-01-01 12:00:01 start\n",
+			opts: []diff.Option{FuzzyLines(0.9)},
+			want: []Edit[string]{
+				{diff.Match, 0, 0, "2025-01-01 12:00:00 start\n", "2025-01-01 12:00:01 start\n", false},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Edits(tt.x, tt.y, tt.opts...)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Edits(...) result is different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+
+	// Hunks and ApplyIndentHeuristic populate LineY too.
+	x := "Generated: 2025-01-01\nfoo\n"
+	y := "Generated: 2025-02-01\nbar\n"
+	hunks := Hunks(x, y, IgnoreMatching(generated))
+	want := []Edit[string]{
+		{diff.Match, 0, 0, "Generated: 2025-01-01\n", "Generated: 2025-02-01\n", false},
+		{diff.Delete, 1, -1, "foo\n", "", false},
+		{diff.Insert, -1, 1, "bar\n", "", false},
+	}
+	if len(hunks) != 1 {
+		t.Fatalf("Hunks(...) returned %d hunks, want 1", len(hunks))
+	}
+	if diff := cmp.Diff(want, hunks[0].Edits); diff != "" {
+		t.Errorf("Hunks(...) result is different (-want, +got):\n%s", diff)
+	}
+	got := ApplyIndentHeuristic(hunks)
+	if diff := cmp.Diff(hunks, got); diff != "" {
+		t.Errorf("ApplyIndentHeuristic(...) result is different (-want, +got):\n%s", diff)
+	}
+}
+
+func TestUnifiedNumberedMissingNewline(t *testing.T) {
+	got := UnifiedNumbered("a\nb", "a\nc")
+	want := "@@ -1,2 +1,2 @@\n" +
+		"1 1  a\n" +
+		"2   -b\n\\ No newline at end of file\n" +
+		"  2 +c\n\\ No newline at end of file\n"
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("UnifiedNumbered(...) result is different (-want, +got):\n%s", diff)
+	}
+}
+
+func TestUnifiedMinimalMissingNewline(t *testing.T) {
+	got := UnifiedMinimal("a\nb", "a\nc")
+	want := "-2,1+2,1\n" +
+		"-b\n\\ No newline at end of file\n" +
+		"+c\n\\ No newline at end of file\n"
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("UnifiedMinimal(...) result is different (-want, +got):\n%s", diff)
+	}
+}
+
+func TestUnifiedNoContextCopy(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y string
+		want string
+	}{
+		{
+			name: "identical",
+			x:    "a\nb\n",
+			y:    "a\nb\n",
+			want: "",
+		},
+		{
+			name: "context",
+			x:    "a\nb\nc\nd\ne\nf\ng\n",
+			y:    "a\nb\nc\nD\ne\nf\ng\n",
+			want: "@@ -1,7 +1,7 @@\n" +
+				"=1,3\n" +
+				"-d\n" +
+				"+D\n" +
+				"=5,3\n",
+		},
+		{
+			name: "missing-newline",
+			x:    "a\nb",
+			y:    "a\nc",
+			want: "@@ -1,2 +1,2 @@\n" +
+				"=1,1\n" +
+				"-b\n\\ No newline at end of file\n" +
+				"+c\n\\ No newline at end of file\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := UnifiedNoContextCopy(tt.x, tt.y)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("UnifiedNoContextCopy(...) result is different (-want, +got):\n%s", diff)
+			}
+			if got, gotBytes := got, UnifiedNoContextCopy([]byte(tt.x), []byte(tt.y)); got != string(gotBytes) {
+				t.Errorf("UnifiedNoContextCopy(...) differs for string and []byte: %q != %q", got, gotBytes)
+			}
+		})
+	}
+}
+
+func TestUnifiedNoContextCopySize(t *testing.T) {
+	// Long context lines around a small change are where omitting the context pays off.
+	var x, y strings.Builder
+	for i := range 20 {
+		line := fmt.Sprintf("%d: %s\n", i, strings.Repeat("context ", 10))
+		x.WriteString(line)
+		if i == 10 {
+			line = "changed\n"
+		}
+		y.WriteString(line)
+	}
+	for _, context := range []int{3, 10} {
+		unified := Unified(x.String(), y.String(), diff.Context(context))
+		nocontext := UnifiedNoContextCopy(x.String(), y.String(), diff.Context(context))
+		if len(nocontext) >= len(unified)/4 {
+			t.Errorf("Context(%d): len(UnifiedNoContextCopy(...)) = %d, want less than a quarter of len(Unified(...)) = %d", context, len(nocontext), len(unified))
+		}
+	}
+}
+
+func TestMissingNewlineFormats(t *testing.T) {
+	// All formats follow the policy of Unified: The marker directly follows every line that's
+	// missing its newline, see TestUnifiedEdgeCases.
+	formats := map[string]func(x, y string) string{
+		"Unified":         func(x, y string) string { return Unified(x, y) },
+		"UnifiedCompact":  func(x, y string) string { return UnifiedCompact(x, y) },
+		"UnifiedNumbered": func(x, y string) string { return UnifiedNumbered(x, y) },
+		"UnifiedMinimal":  func(x, y string) string { return UnifiedMinimal(x, y) },
+		"WriteUnified": func(x, y string) string {
+			var b strings.Builder
+			if err := WriteUnified(&b, x, y); err != nil {
+				t.Fatalf("WriteUnified(...) failed: %v", err)
+			}
+			return b.String()
+		},
+		"Hunk.String": func(x, y string) string {
+			var b strings.Builder
+			for _, h := range Hunks(x, y) {
+				b.WriteString(h.String())
+			}
+			return b.String()
+		},
+		"Interdiff": func(x, y string) string {
+			// The interdiff of two patches for the same file is the diff of the patched files.
+			patchX := Unified("a\n", x)
+			patchY := Unified("a\n", y)
+			got, err := Interdiff("--- a\n+++ b\n"+patchX, "--- a\n+++ b\n"+patchY)
+			if err != nil {
+				t.Fatalf("Interdiff(...) failed: %v", err)
+			}
+			return got
+		},
+	}
+	tests := []struct {
+		name string
+		x, y string
+		want []string // Lines that must be followed by a marker.
+	}{
+		{
+			name: "missing-newline-x",
+			x:    "a\nb",
+			y:    "a\nb\n",
+			want: []string{"-b"},
+		},
+		{
+			name: "missing-newline-y",
+			x:    "a\nb\n",
+			y:    "a\nb",
+			want: []string{"+b"},
+		},
+		{
+			name: "missing-newline-both",
+			x:    "a\nb",
+			y:    "a\nc",
+			want: []string{"-b", "+c"},
+		},
+	}
+	for name, format := range formats {
+		for _, tt := range tests {
+			t.Run(name+"/"+tt.name, func(t *testing.T) {
+				out := format(tt.x, tt.y)
+				lines := strings.Split(out, "\n")
+				var got []string
+				for i, line := range lines {
+					if line == noNewlineMarker && i > 0 {
+						// Only keep the prefix and the content, numbered lines start with the
+						// line numbers.
+						prev := lines[i-1]
+						got = append(got, prev[max(0, len(prev)-2):])
+					}
+				}
+				if diff := cmp.Diff(tt.want, got); diff != "" {
+					t.Errorf("lines followed by a missing newline marker are different [-want,+got]:\n%s\noutput:\n%s", diff, out)
+				}
+			})
+		}
+	}
+
+	// An ed script can't represent a missing newline, every line ends in a newline instead.
+	for _, tt := range tests {
+		out := Ed(tt.x, tt.y)
+		if strings.Contains(out, noNewlineMarker) || !strings.HasSuffix(out, ".\n") {
+			t.Errorf("Ed(%q, %q) = %q, want script without missing newline markers", tt.x, tt.y, out)
+		}
+	}
+}
+
+func TestHunksOrdered(t *testing.T) {
+	// Within a hunk, the edits cover the lines LineNoX..EndLineNoX and LineNoY..EndLineNoY in order,
+	// even if anchors or heuristics moved the edits around. Hunks are ordered too.
+	lines := []string{"func a() {\n", "\tx++\n", "\ty++\n", "}\n", "\n", "// note\n"}
+	for _, tt := range []struct {
+		name string
+		opts []diff.Option
+	}{
+		{"default", nil},
+		{"context-0", []diff.Option{diff.Context(0)}},
+		{"paired-ordering", []diff.Option{diff.PairedOrdering()}},
+		{"indent-heuristic", []diff.Option{IndentHeuristic()}},
+		{"anchor-on", []diff.Option{AnchorOn(func(line string) bool { return strings.HasPrefix(line, "func") })}},
+		{"ignore-reordered-blocks", []diff.Option{IgnoreReorderedBlocks(func(line string) bool { return strings.HasPrefix(line, "\t") })}},
+		{"fuzzy-lines", []diff.Option{FuzzyLines(0.5)}},
+		{"mark-reindent", []diff.Option{MarkReindent()}},
+		{"block-context", []diff.Option{BlockContext(func(line string) int { return strings.Count(line, "{") - strings.Count(line, "}") })}},
+		{"max-hunk-lines", []diff.Option{MaxHunkLines(4)}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			rng := rand.New(rand.NewPCG(1, 2))
+			for i := range 300 {
+				var x, y strings.Builder
+				for range rng.IntN(30) {
+					x.WriteString(lines[rng.IntN(len(lines))])
+				}
+				for range rng.IntN(30) {
+					y.WriteString(lines[rng.IntN(len(lines))])
+				}
+				endX, endY := 0, 0
+				for _, h := range Hunks(x.String(), y.String(), tt.opts...) {
+					if h.LineNoX < endX || h.LineNoY < endY {
+						t.Fatalf("%d: Hunks(%q, %q) has overlapping or unordered hunks:\n%v", i, x.String(), y.String(), h)
+					}
+					nextX, nextY := h.LineNoX, h.LineNoY
+					for _, e := range h.Edits {
+						if e.LineNoX >= 0 {
+							if e.LineNoX != nextX {
+								t.Fatalf("%d: Hunks(%q, %q) has an edit at line %d in x, want %d:\n%v", i, x.String(), y.String(), e.LineNoX, nextX, h)
+							}
+							nextX++
+						}
+						if e.LineNoY >= 0 {
+							if e.LineNoY != nextY {
+								t.Fatalf("%d: Hunks(%q, %q) has an edit at line %d in y, want %d:\n%v", i, x.String(), y.String(), e.LineNoY, nextY, h)
+							}
+							nextY++
+						}
+					}
+					if nextX != h.EndLineNoX || nextY != h.EndLineNoY {
+						t.Fatalf("%d: Hunks(%q, %q) edits end at lines %d, %d, want %d, %d:\n%v", i, x.String(), y.String(), nextX, nextY, h.EndLineNoX, h.EndLineNoY, h)
+					}
+					endX, endY = h.EndLineNoX, h.EndLineNoY
+				}
+			}
+		})
+	}
+}
+
+func TestHunksTruncated(t *testing.T) {
+	var x, y strings.Builder
+	for i := range 100 {
+		fmt.Fprintf(&x, "line %d\n", i)
+		if i%10 == 5 {
+			fmt.Fprintf(&y, "changed %d\n", i)
+		} else {
+			fmt.Fprintf(&y, "line %d\n", i)
+		}
+	}
+	all := Hunks(x.String(), y.String())
+	if len(all) != 10 {
+		t.Fatalf("Hunks(x, y) returned %d hunks, want 10", len(all))
+	}
+	got, omitted := HunksTruncated(x.String(), y.String(), diff.MaxHunks(4))
+	if diff := cmp.Diff(all[:4], got); diff != "" {
+		t.Errorf("HunksTruncated(...) result is different [-want,+got]:\n%s", diff)
+	}
+	if omitted != 6 {
+		t.Errorf("HunksTruncated(...) omitted %d hunks, want 6", omitted)
+	}
+}
+
+func TestHunksSeq(t *testing.T) {
+	for _, tt := range parseTests(t) {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			for _, opts := range [][]Option{
+				nil,
+				{diff.Context(0)},
+				{diff.PairedOrdering()},
+				{IndentHeuristic(), MarkReindent()},
+				{diff.MaxHunks(2)},
+			} {
+				want := Hunks(tt.x, tt.y, opts...)
+				got := slices.Collect(HunksSeq(tt.x, tt.y, opts...))
+				// cmp.Diff is slow for large inputs, only use it to report differences.
+				if !reflect.DeepEqual(want, got) {
+					t.Errorf("HunksSeq(...) with %d options is different from Hunks(...) [-want,+got]:\n%s", len(opts), cmp.Diff(want, got))
+				}
+			}
+		})
+	}
+}
+
+func TestHunksSeqBreak(t *testing.T) {
+	var x, y strings.Builder
+	for i := range 100 {
+		fmt.Fprintf(&x, "line %d\n", i)
+		if i%10 == 5 {
+			fmt.Fprintf(&y, "changed %d\n", i)
+		} else {
+			fmt.Fprintf(&y, "line %d\n", i)
+		}
+	}
+	want := Hunks(x.String(), y.String())
+	var got []Hunk[string]
+	for h := range HunksSeq(x.String(), y.String()) {
+		got = append(got, h)
+		if len(got) == 3 {
+			break
+		}
+	}
+	if diff := cmp.Diff(want[:3], got); diff != "" {
+		t.Errorf("HunksSeq(...) result is different [-want,+got]:\n%s", diff)
+	}
+}
+
+func TestFingerprintLines(t *testing.T) {
+	// The inline function definition is necessary, because fingerprinting is not exported as an
+	// option.
+	force := func(cfg *config.Config) config.Flag {
+		cfg.ForceFingerprintLines = true
+		return 0
+	}
+	for _, tt := range parseTests(t) {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			for _, opts := range [][]Option{nil, {IndentHeuristic()}} {
+				want := Unified(tt.x, tt.y, opts...)
+				got := Unified(tt.x, tt.y, append(opts, force)...)
+				if !bytes.Equal(want, got) {
+					t.Errorf("Unified(...) with fingerprints is different [-want,+got]:\n%s", cmp.Diff(want, got))
+				}
+			}
+		})
+	}
+}
+
+func TestHunkString(t *testing.T) {
+	for _, tt := range parseTests(t) {
+		t.Run(tt.name, func(t *testing.T) {
+			var got strings.Builder
+			for _, h := range Hunks(tt.x, tt.y) {
+				got.WriteString(h.String())
+			}
+			if want := Unified(string(tt.x), string(tt.y)); got.String() != want {
+				t.Errorf("Hunk.String() is different from Unified(...) [-want,+got]:\n%s", cmp.Diff(want, got.String()))
+			}
+		})
+	}
+}
+
+func TestHunkRanges(t *testing.T) {
+	tests := []struct {
+		name        string
+		x, y        string
+		opts        []diff.Option
+		wantDeletes [][2]int
+		wantInserts [][2]int
+	}{
+		{
+			name:        "delete",
+			x:           "a\nb\nc\n",
+			y:           "a\nc\n",
+			wantDeletes: [][2]int{{1, 2}},
+		},
+		{
+			name:        "insert",
+			x:           "a\nc\n",
+			y:           "a\nb\nc\n",
+			wantInserts: [][2]int{{1, 2}},
+		},
+		{
+			name:        "multiple-runs",
+			x:           "a\nb\nc\nd\ne\nf\ng\n",
+			y:           "a\nB\nC\nd\ne\nF\ng\nh\n",
+			wantDeletes: [][2]int{{1, 3}, {5, 6}},
+			wantInserts: [][2]int{{1, 3}, {5, 6}, {7, 8}},
+		},
+		{
+			name:        "paired-ordering",
+			x:           "a\nb\nc\nd\n",
+			y:           "a\nB\nC\nd\n",
+			opts:        []diff.Option{diff.PairedOrdering()},
+			wantDeletes: [][2]int{{1, 3}},
+			wantInserts: [][2]int{{1, 3}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hunks := Hunks(tt.x, tt.y, tt.opts...)
+			if len(hunks) != 1 {
+				t.Fatalf("Hunks(...) returned %d hunks, want 1", len(hunks))
+			}
+			deletes, inserts := HunkRanges(hunks[0])
+			if diff := cmp.Diff(tt.wantDeletes, deletes); diff != "" {
+				t.Errorf("HunkRanges(...) deletes are different (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantInserts, inserts); diff != "" {
+				t.Errorf("HunkRanges(...) inserts are different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestHunksMissingNewline(t *testing.T) {
+	type missing struct{ x, y bool }
+	tests := []struct {
+		name string
+		x, y string
+		want []missing // per hunk
+	}{
+		{
+			name: "none",
+			x:    "a\nb\n",
+			y:    "a\nc\n",
+			want: []missing{{false, false}},
+		},
+		{
+			name: "x-missing",
+			x:    "a\nb",
+			y:    "a\nb\n",
+			want: []missing{{true, false}},
+		},
+		{
+			name: "y-missing",
+			x:    "a\nb\n",
+			y:    "a\nc",
+			want: []missing{{false, true}},
+		},
+		{
+			name: "both-missing",
+			x:    "a\nb",
+			y:    "a\nc",
+			want: []missing{{true, true}},
+		},
+		{
+			name: "last-hunk-only",
+			x:    "a\n1\n2\n3\n4\n5\n6\n7\n8\nb",
+			y:    "A\n1\n2\n3\n4\n5\n6\n7\n8\nB",
+			want: []missing{{false, false}, {true, true}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []missing
+			for _, h := range Hunks(tt.x, tt.y) {
+				got = append(got, missing{h.MissingNewlineX, h.MissingNewlineY})
+			}
+			if diff := cmp.Diff(tt.want, got, cmp.AllowUnexported(missing{})); diff != "" {
+				t.Errorf("Hunks(...) missing newlines are different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIndentHeuristicTuning(t *testing.T) {
+	x := `["foo", "bar", "baz"].map do |i|
+  i.upcase
+end
+`
+	y := `["foo", "bar", "baz"].map do |i|
+  i
+end
+
+["foo", "bar", "baz"].map do |i|
+  i.upcase
+end
+`
+	tuned := DefaultIndentHeuristicWeights()
+	tuned.RelativeIndentPenalty = -100 // strongly prefer groups that start with an indented line
+
+	tests := []struct {
+		name string
+		opts []diff.Option
+		want string
+	}{
+		{
+			name: "default-weights",
+			opts: []diff.Option{IndentHeuristicTuning(DefaultIndentHeuristicWeights())},
+			want: Unified(x, y, IndentHeuristic()),
+		},
+		{
+			name: "tuned-weights",
+			opts: []diff.Option{IndentHeuristicTuning(tuned)},
+			want: `@@ -1,3 +1,7 @@
+ ["foo", "bar", "baz"].map do |i|
++  i
++end
++
++["foo", "bar", "baz"].map do |i|
+   i.upcase
+ end
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unified(x, y, tt.opts...)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Unified(...) result is different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFuzzyLines(t *testing.T) {
+	x := `2025-01-01T10:00:00 starting server
+2025-01-01T10:00:01 listening on :8080
+2025-01-01T10:00:02 ready
+`
+	y := `2025-01-02T11:30:00 starting server
+2025-01-02T11:30:01 listening on :9090
+2025-01-02T11:30:02 shutting down
+`
+	tests := []struct {
+		name string
+		opts []diff.Option
+		want string
+	}{
+		{
+			name: "default",
+			want: `@@ -1,3 +1,3 @@
+-2025-01-01T10:00:00 starting server
+-2025-01-01T10:00:01 listening on :8080
+-2025-01-01T10:00:02 ready
++2025-01-02T11:30:00 starting server
++2025-01-02T11:30:01 listening on :9090
++2025-01-02T11:30:02 shutting down
+`,
+		},
+		{
+			name: "threshold-0.8",
+			opts: []diff.Option{FuzzyLines(0.8)},
+			want: `@@ -1,3 +1,3 @@
+ 2025-01-01T10:00:00 starting server
+ 2025-01-01T10:00:01 listening on :8080
+-2025-01-01T10:00:02 ready
++2025-01-02T11:30:02 shutting down
+`,
+		},
+		{
+			name: "threshold-0.9",
+			opts: []diff.Option{FuzzyLines(0.9)},
+			want: `@@ -1,3 +1,3 @@
+ 2025-01-01T10:00:00 starting server
+-2025-01-01T10:00:01 listening on :8080
+-2025-01-01T10:00:02 ready
++2025-01-02T11:30:01 listening on :9090
++2025-01-02T11:30:02 shutting down
+`,
+		},
+		{
+			name: "threshold-1",
+			opts: []diff.Option{FuzzyLines(1)},
+			want: Unified(x, y),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unified(x, y, tt.opts...)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Unified(...) result is different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFuzzyLinesInvalid(t *testing.T) {
+	for _, threshold := range []float64{-1, 0, 1.5, math.NaN()} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("FuzzyLines(%v) didn't panic", threshold)
+				}
+			}()
+			FuzzyLines(threshold)
+		}()
+	}
+}
+
+func TestUnifiedIfSimilar(t *testing.T) {
+	// 3 of 4 lines match, the similarity is 2*3/(4+4) = 0.75.
+	x := "a\nb\nc\nd\n"
+	y := "a\nb\nc\nD\n"
+	tests := []struct {
+		name     string
+		x, y     string
+		minRatio float64
+		want     string
+		wantOK   bool
+	}{
+		{
+			name:     "empty",
+			minRatio: 1,
+			wantOK:   true,
+		},
+		{
+			name:     "identical",
+			x:        x,
+			y:        x,
+			minRatio: 1,
+			wantOK:   true,
+		},
+		{
+			name:     "below-threshold",
+			x:        x,
+			y:        y,
+			minRatio: 0.76,
+		},
+		{
+			name:     "at-threshold",
+			x:        x,
+			y:        y,
+			minRatio: 0.75,
+			want:     "@@ -1,4 +1,4 @@\n a\n b\n c\n-d\n+D\n",
+			wantOK:   true,
+		},
+		{
+			name:     "above-threshold",
+			x:        x,
+			y:        y,
+			minRatio: 0.74,
+			want:     "@@ -1,4 +1,4 @@\n a\n b\n c\n-d\n+D\n",
+			wantOK:   true,
+		},
+		{
+			name:     "disjoint",
+			x:        "a\n",
+			y:        "b\n",
+			minRatio: 0.01,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotOK := UnifiedIfSimilar(tt.x, tt.y, tt.minRatio)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("UnifiedIfSimilar(...) result is different (-want, +got):\n%s", diff)
+			}
+			if gotOK != tt.wantOK {
+				t.Errorf("UnifiedIfSimilar(...) ok = %v, want %v", gotOK, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestChangedLines(t *testing.T) {
+	for _, tt := range parseTests(t) {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, st := range tt.subtests {
+				if st.name != "default" {
+					continue
+				}
+				// Collect the changed lines from the expected unified diff.
+				var wantIns, wantDel []string
+				for line := range strings.Lines(string(st.want)) {
+					switch line[0] {
+					case '+':
+						wantIns = append(wantIns, line[1:])
+					case '-':
+						wantDel = append(wantDel, line[1:])
+					}
+				}
+				if diff := cmp.Diff(wantIns, InsertedLines(string(tt.x), string(tt.y), st.opts...)); diff != "" {
+					t.Errorf("InsertedLines(...) result is different [-want,+got]:\n%s", diff)
+				}
+				if diff := cmp.Diff(wantDel, DeletedLines(string(tt.x), string(tt.y), st.opts...)); diff != "" {
+					t.Errorf("DeletedLines(...) result is different [-want,+got]:\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestChangedLineNumbers(t *testing.T) {
+	tests := []struct {
+		name        string
+		x, y        string
+		wantChanged []int
+		wantDeleted []int
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "identical",
+			x:    "a\nb\n",
+			y:    "a\nb\n",
+		},
+		{
+			name:        "from-empty",
+			y:           "a\nb\n",
+			wantChanged: []int{1, 2},
+		},
+		{
+			name:        "to-empty",
+			x:           "a\nb\n",
+			wantDeleted: []int{1, 2},
+		},
+		{
+			name:        "multiple-hunks",
+			x:           "a\nb\nc\nd\ne\nf\ng\nh\ni\nj\nk\nl\n",
+			y:           "a\nB\nc\nd\ne\nf\ng\nh\ni\nj\nl\nm\nn\n",
+			wantChanged: []int{2, 12, 13},
+			wantDeleted: []int{2, 11},
+		},
+		{
+			name:        "missing-newline",
+			x:           "a\nb",
+			y:           "a\nb\n",
+			wantChanged: []int{2},
+			wantDeleted: []int{2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.wantChanged, ChangedLines(tt.x, tt.y)); diff != "" {
+				t.Errorf("ChangedLines(...) result is different (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantDeleted, DeletedLineNumbers(tt.x, tt.y)); diff != "" {
+				t.Errorf("DeletedLineNumbers(...) result is different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestChangedLineNumbersMatchEdits(t *testing.T) {
+	for _, tt := range parseTests(t) {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var wantChanged, wantDeleted []int
+			for _, e := range Edits(tt.x, tt.y) {
+				switch e.Op {
+				case diff.Insert:
+					wantChanged = append(wantChanged, e.LineNoY+1)
+				case diff.Delete:
+					wantDeleted = append(wantDeleted, e.LineNoX+1)
+				}
+			}
+			if diff := cmp.Diff(wantChanged, ChangedLines(tt.x, tt.y)); diff != "" {
+				t.Errorf("ChangedLines(...) doesn't match Edits(...) (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(wantDeleted, DeletedLineNumbers(tt.x, tt.y)); diff != "" {
+				t.Errorf("DeletedLineNumbers(...) doesn't match Edits(...) (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestChangedLinesMissingNewline(t *testing.T) {
+	tests := []struct {
+		name     string
+		x, y     string
+		ins, del []string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "identical",
+			x:    "a\nb",
+			y:    "a\nb",
+		},
+		{
+			name: "add-newline",
+			x:    "a\nb",
+			y:    "a\nb\n",
+			ins:  []string{"b\n"},
+			del:  []string{"b"},
+		},
+		{
+			name: "remove-newline",
+			x:    "a\nb\n",
+			y:    "a\nb",
+			ins:  []string{"b"},
+			del:  []string{"b\n"},
+		},
+		{
+			name: "append",
+			x:    "a",
+			y:    "a\nb",
+			ins:  []string{"a\n", "b"},
+			del:  []string{"a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.ins, InsertedLines(tt.x, tt.y)); diff != "" {
+				t.Errorf("InsertedLines(...) result is different [-want,+got]:\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.del, DeletedLines(tt.x, tt.y)); diff != "" {
+				t.Errorf("DeletedLines(...) result is different [-want,+got]:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRunes(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y string
+		want []diff.Edit[rune]
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "identical",
+			x:    "aé",
+			y:    "aé",
+			want: []diff.Edit[rune]{
+				{Op: diff.Match, X: 'a', Y: 'a', PosX: 0, PosY: 0},
+				{Op: diff.Match, X: 'é', Y: 'é', PosX: 1, PosY: 1},
+			},
+		},
+		{
+			name: "multibyte",
+			x:    "Grüße, Welt",
+			y:    "Grüße, 世界",
+			want: []diff.Edit[rune]{
+				{Op: diff.Match, X: 'G', Y: 'G', PosX: 0, PosY: 0},
+				{Op: diff.Match, X: 'r', Y: 'r', PosX: 1, PosY: 1},
+				{Op: diff.Match, X: 'ü', Y: 'ü', PosX: 2, PosY: 2},
+				{Op: diff.Match, X: 'ß', Y: 'ß', PosX: 3, PosY: 3},
+				{Op: diff.Match, X: 'e', Y: 'e', PosX: 4, PosY: 4},
+				{Op: diff.Match, X: ',', Y: ',', PosX: 5, PosY: 5},
+				{Op: diff.Match, X: ' ', Y: ' ', PosX: 6, PosY: 6},
+				{Op: diff.Delete, X: 'W', PosX: 7, PosY: -1},
+				{Op: diff.Delete, X: 'e', PosX: 8, PosY: -1},
+				{Op: diff.Delete, X: 'l', PosX: 9, PosY: -1},
+				{Op: diff.Delete, X: 't', PosX: 10, PosY: -1},
+				{Op: diff.Insert, Y: '世', PosX: -1, PosY: 7},
+				{Op: diff.Insert, Y: '界', PosX: -1, PosY: 8},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Runes(tt.x, tt.y)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Runes(...) result is different (-want, +got):\n%s", diff)
+			}
+
+			got = Runes([]byte(tt.x), []byte(tt.y))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Runes[[]byte](...) result is different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEqual(t *testing.T) {
+	ignoreComments := IgnoreMatching(func(line string) bool { return strings.HasPrefix(line, "#") })
+	tests := []struct {
+		name string
+		x, y string
+		opts []diff.Option
+		want bool
+	}{
+		{name: "empty", want: true},
+		{name: "identical", x: "first line\n", y: "first line\n", want: true},
+		{name: "new-lines-only", x: "\n", y: "\n", want: true},
+		{name: "x-empty", x: "", y: "one-line\n"},
+		{name: "y-empty", x: "one-line\n", y: ""},
+		{name: "missing-newline-x", x: "first line", y: "first line\n"},
+		{name: "missing-newline-y", x: "first line\n", y: "first line"},
+		{name: "missing-newline-both", x: "a\nsecond line", y: "b\nsecond line"},
+		{name: "missing-newline-empty-x", x: "", y: "\n"},
+		{name: "missing-newline-empty-y", x: "\n", y: ""},
+		{name: "normalize-unicode", x: "caf\u00e9\n", y: "cafe\u0301\n", opts: []diff.Option{NormalizeUnicode(norm.NFC)}, want: true},
+		{name: "ignore-matching", x: "# a\nb\n", y: "# c\nb\n", opts: []diff.Option{ignoreComments}, want: true},
+		{name: "ignore-matching-extra-line", x: "# a\nb\n", y: "# c\nb\nc\n", opts: []diff.Option{ignoreComments}},
+		{name: "ignore-matching-missing-newline", x: "b\n# a", y: "b\n# c\n", opts: []diff.Option{ignoreComments}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Equal(tt.x, tt.y, tt.opts...); got != tt.want {
+				t.Errorf("Equal(%q, %q) = %v, want %v", tt.x, tt.y, got, tt.want)
+			}
+			if got, want := Equal(tt.x, tt.y, tt.opts...), Unified(tt.x, tt.y, tt.opts...) == ""; got != want {
+				t.Errorf("Equal(%q, %q) = %v, but Unified(...) is empty = %v", tt.x, tt.y, got, want)
+			}
+			if got := Equal([]byte(tt.x), []byte(tt.y), tt.opts...); got != tt.want {
+				t.Errorf("Equal[[]byte](%q, %q) = %v, want %v", tt.x, tt.y, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFirstDifferingLine(t *testing.T) {
+	tests := []struct {
+		name      string
+		x, y      string
+		wantLine  int
+		wantEqual bool
+	}{
+		{
+			name:      "empty",
+			wantLine:  0,
+			wantEqual: true,
+		},
+		{
+			name:      "identical",
+			x:         "foo\nbar\n",
+			y:         "foo\nbar\n",
+			wantLine:  0,
+			wantEqual: true,
+		},
+		{
+			name:     "x-prefix-of-y",
+			x:        "foo\nbar\n",
+			y:        "foo\nbar\nbaz\n",
+			wantLine: 3,
+		},
+		{
+			name:     "disjoint",
+			x:        "foo\nbar\n",
+			y:        "baz\nqux\n",
+			wantLine: 1,
+		},
+		{
+			name:     "same-prefix",
+			x:        "foo\nbar\nbaz\n",
+			y:        "foo\nbaz\nbaz\n",
+			wantLine: 2,
+		},
+		{
+			name:     "missing-newline",
+			x:        "foo\nbar",
+			y:        "foo\nbar\n",
+			wantLine: 2,
+		},
+		{
+			name:     "new-lines-only",
+			x:        "\n",
+			y:        "\n\n",
+			wantLine: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line, equal := FirstDifferingLine(tt.x, tt.y)
+			if line != tt.wantLine || equal != tt.wantEqual {
+				t.Errorf("FirstDifferingLine(...) = %v, %v, want %v, %v", line, equal, tt.wantLine, tt.wantEqual)
+			}
+		})
+	}
+}
+
+type test struct {
+	name     string
+	filename string
+	comment  []byte
+	x, y     []byte
+	subtests []subtest
+}
+
+type subtest struct {
+	name        string
+	opts        []config.Option
+	displayOnly bool // output can't be applied as a patch
+	pragmas     []byte
+	want        []byte
+
+	// If not nil, render is used instead of Unified to create the output.
+	render func(x, y []byte, opts ...Option) []byte
+}
+
+func parseTests(t testing.TB) []test {
+	t.Helper()
+	testFiles, err := filepath.Glob("testdata/*.test")
+	if err != nil {
+		t.Fatalf("Failed to read testdata: %v", err)
+	}
+	var tests []test
+	for _, filename := range testFiles {
+		ar, err := txtar.ParseFile(filename)
+		if err != nil {
+			t.Fatalf("failed to parse test case: %v", err)
+		}
+		name := strings.TrimPrefix(filename, "testdata/")
+		test := test{
+			name:     name,
+			filename: filename,
+			comment:  ar.Comment,
+		}
+
+		for _, f := range ar.Files {
+			switch f.Name {
+			case "x":
+				test.x = f.Data
+			case "y":
+				test.y = f.Data
+			case "diff":
+				data := f.Data
+				var st subtest
+				var name []string
+				i := 0
+				for ; i < len(data); i++ {
+					if data[i] != '#' {
+						break
+					}
+					i++
+					eol := i + bytes.IndexByte(data[i:], '\n')
+					if eol < i {
+						t.Fatal("failed to parse test case: missing newline after pragma line")
+					}
+					k, v, found := bytes.Cut(data[i:eol], []byte{':'})
+					if !found {
+						t.Fatal("failed to parse test case: missing ':' in pragma line")
+					}
+					switch k, v := strings.TrimSpace(string(k)), strings.TrimSpace(string(v)); k {
+					case "indent-heuristic":
+						switch v {
+						case "true":
+							st.opts = append(st.opts, IndentHeuristic())
+						case "false":
+							// do nothing
+						default:
+							t.Fatalf("invalid value for indent-heuristic: %q", v)
+						}
+						name = append(name, k)
+					case "force-anchoring-heuristic":
+						switch v {
+						case "true":
+							// The inline function definition is necessary, because the anchoring
+							// heuristic is not exported as an option.
+							st.opts = append(st.opts, func(cfg *config.Config) config.Flag {
+								cfg.ForceAnchoringHeuristic = true
+								return 0
+							})
+						case "false":
+							// do nothing
+						default:
+							t.Fatalf("invalid value for force-anchoring-heuristic: %q", v)
+						}
+						name = append(name, k)
+					case "fast":
+						switch v {
+						case "true":
+							st.opts = append(st.opts, diff.Fast())
+						case "false":
+							// do nothing
+						default:
+							t.Fatalf("invalid value for fast: %q", v)
+						}
+					case "context":
+						n, err := strconv.ParseInt(v, 10, 64)
+						if err != nil {
+							t.Fatalf("invalid value for context: %v", err.Error())
+						}
+						st.opts = append(st.opts, diff.Context(int(n)))
+						name = append(name, k+"="+v)
+					case "paired-ordering":
+						switch v {
+						case "true":
+							st.opts = append(st.opts, diff.PairedOrdering())
+						case "false":
+							// do nothing
+						default:
+							t.Fatalf("invalid value for paired-ordering: %q", v)
+						}
+						name = append(name, k)
+					case "prefer-long-matches":
+						switch v {
+						case "true":
+							st.opts = append(st.opts, diff.PreferLongMatches())
+						case "false":
+							// do nothing
+						default:
+							t.Fatalf("invalid value for prefer-long-matches: %q", v)
+						}
+						name = append(name, k)
+					case "compact":
+						switch v {
+						case "true":
+							st.render = UnifiedCompact[[]byte]
+							st.displayOnly = true
+						case "false":
+							// do nothing
+						default:
+							t.Fatalf("invalid value for compact: %q", v)
+						}
+						name = append(name, k)
+					case "numbered":
+						switch v {
+						case "true":
+							st.render = UnifiedNumbered[[]byte]
+							st.displayOnly = true
+						case "false":
+							// do nothing
+						default:
+							t.Fatalf("invalid value for numbered: %q", v)
+						}
+						name = append(name, k)
+					case "unified-minimal":
+						switch v {
+						case "true":
+							st.render = UnifiedMinimal[[]byte]
+							st.displayOnly = true
+						case "false":
+							// do nothing
+						default:
+							t.Fatalf("invalid value for unified-minimal: %q", v)
+						}
+						name = append(name, k)
+					case "side-by-side":
+						n, err := strconv.ParseInt(v, 10, 64)
+						if err != nil {
+							t.Fatalf("invalid value for side-by-side: %v", err.Error())
+						}
+						st.render = func(x, y []byte, opts ...Option) []byte {
+							return SideBySide(x, y, int(n), opts...)
+						}
+						st.displayOnly = true
+						name = append(name, k+"="+v)
+					case "hunk-stats":
+						switch v {
+						case "true":
+							st.opts = append(st.opts, HunkStatsInHeader())
+							st.displayOnly = true
+						case "false":
+							// do nothing
+						default:
+							t.Fatalf("invalid value for hunk-stats: %q", v)
+						}
+						name = append(name, k)
+					case "show-whitespace":
+						switch v {
+						case "true":
+							st.opts = append(st.opts, ShowWhitespace())
+							st.displayOnly = true
+						case "false":
+							// do nothing
+						default:
+							t.Fatalf("invalid value for show-whitespace: %q", v)
+						}
+						name = append(name, k)
+					case "hunk-separator":
+						st.opts = append(st.opts, HunkSeparator(v))
+						name = append(name, k+"="+v)
+					case "anchor-on":
+						prefix := v
+						st.opts = append(st.opts, AnchorOn(func(line string) bool {
+							return strings.HasPrefix(line, prefix)
+						}))
+						name = append(name, k+"="+v)
+					case "indent-heuristic-except":
+						re, err := regexp.Compile(v)
+						if err != nil {
+							t.Fatalf("invalid value for indent-heuristic-except: %v", err)
+						}
+						st.opts = append(st.opts, IndentHeuristicExcept(re.MatchString))
+						name = append(name, k+"="+v)
+					case "function-context":
+						prefix := v
+						st.opts = append(st.opts, FunctionContext(func(line string) bool {
+							return strings.HasPrefix(line, prefix)
+						}))
+						name = append(name, k+"="+v)
+					case "max-line-width":
+						n, err := strconv.ParseInt(v, 10, 64)
+						if err != nil {
+							t.Fatalf("invalid value for max-line-width: %v", err.Error())
+						}
+						st.opts = append(st.opts, MaxLineWidth(int(n)))
+						st.displayOnly = true
+						name = append(name, k+"="+v)
+					case "labels":
+						labels := strings.Fields(v)
+						if len(labels) != 2 {
+							t.Fatalf("invalid value for labels: %q", v)
+						}
+						st.opts = append(st.opts, Labels(labels[0], labels[1]))
+						name = append(name, k)
+					case "index-header":
+						fields := strings.Fields(v)
+						if len(fields) < 2 || len(fields) > 3 {
+							t.Fatalf("invalid value for index-header: %q", v)
+						}
+						fields = append(fields, "")
+						st.opts = append(st.opts, IndexHeader(fields[0], fields[1], fields[2]))
+						name = append(name, k)
+					case "output-newline":
+						switch v {
+						case "lf":
+							st.opts = append(st.opts, OutputNewline("\n"))
+						case "crlf":
+							st.opts = append(st.opts, OutputNewline("\r\n"))
+						default:
+							t.Fatalf("invalid value for output-newline: %q", v)
+						}
+						name = append(name, k+"="+v)
+					default:
+						t.Fatalf("unknown option: %q", k)
+					}
+					i = eol
+				}
+				if len(name) == 0 {
+					name = append(name, "default")
+				}
+				st.name = strings.Join(name, ":")
+				st.pragmas = data[:i]
+				st.want = data[i:]
+				test.subtests = append(test.subtests, st)
+			default:
+				t.Fatalf("unknown file in archive: %v", f)
+			}
+		}
+		tests = append(tests, test)
+	}
+	return tests
+}
+
+func TestApplyIndentHeuristic(t *testing.T) {
+	for _, tt := range parseTests(t) {
+		t.Run(tt.name, func(t *testing.T) {
+			// The heuristic can only shift changes within a hunk. Use a context that covers the
+			// whole input to make sure the result is the same as applying the heuristic during the
+			// diff.
+			context := diff.Context(bytes.Count(tt.x, []byte{'\n'}) + bytes.Count(tt.y, []byte{'\n'}) + 1)
+			want := Hunks(tt.x, tt.y, context, IndentHeuristic())
+			got := ApplyIndentHeuristic(Hunks(tt.x, tt.y, context))
+			if reflect.DeepEqual(want, got) {
+				return // cmp.Diff is slow on large inputs
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("ApplyIndentHeuristic(Hunks(...)) result is different from Hunks(..., IndentHeuristic()) (-want, +got):\n%s", diff)
+			}
+		})
+	}
 }
@@ -0,0 +1,242 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"znkr.io/diff/internal/byteview"
+	"znkr.io/diff/internal/config"
+	"znkr.io/diff/internal/rvecs"
+)
+
+func unified[T string | []byte](d lineDiff, cfg config.Config) T {
+	r := newUnifiedRenderer(&d, &cfg)
+
+	// Precompute output buffer size.
+	n, i := 0, 0
+	for h := range d.hunkSeq(cfg) {
+		n += r.size(i, h)
+		i++
+	}
+
+	// Format output.
+	var b byteview.Builder[T]
+	b.Grow(n)
+	i = 0
+	for h := range d.hunkSeq(cfg) {
+		r.writeHunk(&b, i, h)
+		i++
+	}
+	return b.Build()
+}
+
+// output is where a unifiedRenderer writes to.
+type output interface {
+	io.Writer
+	io.StringWriter
+}
+
+// unifiedRenderer renders the hunks of a line diff in the unified format, see [Unified]. It works
+// on the result vectors directly, without building any edits.
+type unifiedRenderer struct {
+	d      *lineDiff
+	cfg    *config.Config
+	colors config.ColorConfig
+	nl     string // Line separator for hunk headers and markers.
+	header string // File header in front of the first hunk.
+	buf    []byte // Scratch buffer for hunk headers.
+
+	// If set, every line is colored individually and the color is reset before the end of the
+	// line, see [WriteUnified]. Otherwise, every run of edits is colored as a whole. size doesn't
+	// support colorLines.
+	colorLines bool
+}
+
+func newUnifiedRenderer(d *lineDiff, cfg *config.Config) *unifiedRenderer {
+	r := &unifiedRenderer{d: d, cfg: cfg, nl: "\n"}
+	if cfg.Colors != nil {
+		r.colors = *cfg.Colors
+	}
+	if cfg.OutputNewline != "" {
+		r.nl = cfg.OutputNewline
+	}
+	r.header = fileHeader(cfg, r.nl)
+	return r
+}
+
+// fileHeader returns the "index" line and the "---" and "+++" file header, if configured.
+func fileHeader(cfg *config.Config, nl string) string {
+	var b strings.Builder
+	b.WriteString(indexLine(cfg, nl))
+	if cfg.LabelX != "" || cfg.LabelY != "" {
+		b.WriteString("--- " + cfg.LabelX + nl)
+		b.WriteString("+++ " + cfg.LabelY + nl)
+	}
+	return b.String()
+}
+
+// indexLine returns the git style "index" line for [IndexHeader] or an empty string if it's not
+// set.
+func indexLine(cfg *config.Config, nl string) string {
+	if cfg.IndexOld == "" && cfg.IndexNew == "" {
+		return ""
+	}
+	line := "index " + cfg.IndexOld + ".." + cfg.IndexNew
+	if cfg.IndexMode != "" {
+		line += " " + cfg.IndexMode
+	}
+	return line + nl
+}
+
+// writeHunk writes hunk h to w. The index i is the position of h in the diff, starting at 0 for
+// the first hunk.
+func (r *unifiedRenderer) writeHunk(w output, i int, h rvecs.Hunk) {
+	if i == 0 {
+		w.WriteString(r.header)
+	}
+	w.WriteString(r.colors.HunkHeader)
+	if i == 0 || r.cfg.HunkSeparator == "" {
+		b := append(r.buf[:0], "@@ -"...)
+		b = strconv.AppendInt(b, int64(h.S0+1), 10)
+		b = append(b, ',')
+		b = strconv.AppendInt(b, int64(h.S1-h.S0), 10)
+		b = append(b, " +"...)
+		b = strconv.AppendInt(b, int64(h.T0+1), 10)
+		b = append(b, ',')
+		b = strconv.AppendInt(b, int64(h.T1-h.T0), 10)
+		b = append(b, " @@"...)
+		w.Write(b)
+		w.WriteString(r.stats(h))
+		r.buf = b
+	} else {
+		w.WriteString(r.cfg.HunkSeparator)
+	}
+	w.WriteString(r.colors.Reset)
+	w.WriteString(r.nl)
+
+	for run := range rvecs.Runs(r.d.rx, r.d.ry, h, r.cfg.PairedOrdering) {
+		color, prefix := r.style(run.Op)
+		if r.colorLines {
+			for k := range run.N {
+				line, missingNewline := r.line(run, k)
+				r.writeColoredLine(w, color, prefix, line, missingNewline)
+			}
+			continue
+		}
+		w.WriteString(color)
+		for k := range run.N {
+			line, missingNewline := r.line(run, k)
+			w.WriteString(prefix)
+			writeLine(w, line, r.cfg)
+			if missingNewline {
+				writeMissingNewline(w, r.nl)
+			}
+		}
+		w.WriteString(r.colors.Reset)
+	}
+}
+
+// writeColoredLine writes a single line and resets the color before the newline.
+func (r *unifiedRenderer) writeColoredLine(w output, color, prefix string, line byteview.ByteView, missingNewline bool) {
+	content := strings.TrimSuffix(displayLine(line, r.cfg), "\n")
+	truncated := false
+	if r.cfg.MaxLineWidth > 0 {
+		var rest string
+		content, rest = truncate(content, r.cfg.MaxLineWidth)
+		truncated = len(rest) > 0
+	}
+	w.WriteString(color)
+	w.WriteString(prefix)
+	w.WriteString(content)
+	if truncated {
+		w.WriteString(ellipsis)
+	}
+	if color != "" {
+		w.WriteString(r.colors.Reset)
+	}
+	if !missingNewline {
+		w.WriteString("\n")
+		return
+	}
+	writeMissingNewline(w, r.nl)
+}
+
+// size returns the number of bytes written by writeHunk.
+func (r *unifiedRenderer) size(i int, h rvecs.Hunk) int {
+	var n int
+	if i == 0 {
+		n += len(r.header)
+	}
+	n += len(r.colors.HunkHeader) + len(r.colors.Reset) + len(r.nl)
+	if i == 0 || r.cfg.HunkSeparator == "" {
+		n += len("@@ -, +, @@")
+		n += numDigits(h.S0+1) + numDigits(h.S1-h.S0) + numDigits(h.T0+1) + numDigits(h.T1-h.T0)
+		n += len(r.stats(h))
+	} else {
+		n += len(r.cfg.HunkSeparator)
+	}
+	for run := range rvecs.Runs(r.d.rx, r.d.ry, h, r.cfg.PairedOrdering) {
+		color, _ := r.style(run.Op)
+		n += len(color) + len(r.colors.Reset)
+		for k := range run.N {
+			line, missingNewline := r.line(run, k)
+			n += 1 + lineLen(line, r.cfg)
+			if missingNewline {
+				n += missingNewlineLen(r.nl)
+			}
+		}
+	}
+	return n
+}
+
+// stats returns the text that [HunkStatsInHeader] appends to the header of h or an empty string
+// if it's not set.
+func (r *unifiedRenderer) stats(h rvecs.Hunk) string {
+	if !r.cfg.HunkStatsInHeader {
+		return ""
+	}
+	return hunkStats(countChanges(r.d.ry[h.T0:h.T1]), countChanges(r.d.rx[h.S0:h.S1]))
+}
+
+// hunkStats formats the number of inserted and deleted lines for [HunkStatsInHeader].
+func hunkStats(ins, del int) string {
+	return " (+" + strconv.Itoa(ins) + " -" + strconv.Itoa(del) + ")"
+}
+
+// style returns the color and the line prefix for edits of kind op.
+func (r *unifiedRenderer) style(op rvecs.Op) (color, prefix string) {
+	switch op {
+	case rvecs.Delete:
+		return r.colors.Delete, prefixDelete
+	case rvecs.Insert:
+		return r.colors.Insert, prefixInsert
+	default:
+		return r.colors.Match, prefixMatch
+	}
+}
+
+// line returns the k-th line of run and whether it's missing its newline. Deletions and matches
+// refer to lines in x, insertions to lines in y.
+func (r *unifiedRenderer) line(run rvecs.Run, k int) (byteview.ByteView, bool) {
+	if run.Op == rvecs.Insert {
+		t := run.T + k
+		return r.d.y[t], t == r.d.yMissingNewline
+	}
+	s := run.S + k
+	return r.d.x[s], s == r.d.xMissingNewline
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"znkr.io/diff"
+)
+
+// Tokenizer splits a line into tokens for [WordDiff].
+//
+// The tokens must partition the line: Concatenating all tokens has to result in the line again.
+// Tokenize is called once for each input of [WordDiff].
+type Tokenizer[T string | []byte] interface {
+	Tokenize(line T) []T
+}
+
+// WordDiff compares x and y token by token and returns one edit for every token in the input.
+// This is useful to highlight changes within a line. PosX and PosY of the returned edits are token
+// indices, not byte offsets.
+//
+// The inputs are split into tokens using tok. If tok is nil, the inputs are split into words:
+// Every run of letters, digits, and underscores and every run of whitespace is a token, every
+// other rune is a token by itself. For code, this splits operators like "->" into separate tokens;
+// use a custom [Tokenizer] to keep them together.
+//
+// The following options are supported: [diff.Minimal], [diff.PreferLongMatches], [diff.Fast],
+// [diff.AutoFast], [diff.Parallel]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func WordDiff[T string | []byte](x, y T, tok Tokenizer[T], opts ...Option) []diff.Edit[T] {
+	if tok == nil {
+		tok = wordTokenizer[T]{}
+	}
+	xtokens, ytokens := tok.Tokenize(x), tok.Tokenize(y)
+	edits := diff.Edits(tokenKeys(xtokens), tokenKeys(ytokens), opts...)
+	if len(edits) == 0 {
+		return nil
+	}
+	out := make([]diff.Edit[T], len(edits))
+	for i, e := range edits {
+		out[i] = diff.Edit[T]{Op: e.Op, PosX: e.PosX, PosY: e.PosY}
+		if e.PosX >= 0 {
+			out[i].X = xtokens[e.PosX]
+		}
+		if e.PosY >= 0 {
+			out[i].Y = ytokens[e.PosY]
+		}
+	}
+	return out
+}
+
+// tokenKeys returns the tokens as strings to compare them.
+func tokenKeys[T string | []byte](tokens []T) []string {
+	keys := make([]string, len(tokens))
+	for i, t := range tokens {
+		keys[i] = string(t)
+	}
+	return keys
+}
+
+// wordTokenizer is the default [Tokenizer] of [WordDiff].
+type wordTokenizer[T string | []byte] struct{}
+
+func (wordTokenizer[T]) Tokenize(line T) []T {
+	var tokens []T
+	for len(line) > 0 {
+		r, size := decodeRune(line)
+		if class := runeClass(r); class != classOther {
+			for size < len(line) {
+				r, n := decodeRune(line[size:])
+				if runeClass(r) != class {
+					break
+				}
+				size += n
+			}
+		}
+		tokens = append(tokens, line[:size])
+		line = line[size:]
+	}
+	return tokens
+}
+
+// Rune classes of the default tokenizer.
+const (
+	classOther = iota
+	classWord
+	classSpace
+)
+
+func runeClass(r rune) int {
+	switch {
+	case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+		return classWord
+	case unicode.IsSpace(r):
+		return classSpace
+	default:
+		return classOther
+	}
+}
+
+func decodeRune[T string | []byte](s T) (rune, int) {
+	switch s := any(s).(type) {
+	case string:
+		return utf8.DecodeRuneInString(s)
+	case []byte:
+		return utf8.DecodeRune(s)
+	}
+	panic("unreachable")
+}
@@ -0,0 +1,144 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"znkr.io/diff"
+)
+
+func TestWordDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y string
+		want []diff.Edit[string]
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "words",
+			x:    "the quick  fox",
+			y:    "the slow  fox",
+			want: []diff.Edit[string]{
+				{Op: diff.Match, X: "the", Y: "the", PosX: 0, PosY: 0},
+				{Op: diff.Match, X: " ", Y: " ", PosX: 1, PosY: 1},
+				{Op: diff.Delete, X: "quick", PosX: 2, PosY: -1},
+				{Op: diff.Insert, Y: "slow", PosX: -1, PosY: 2},
+				{Op: diff.Match, X: "  ", Y: "  ", PosX: 3, PosY: 3},
+				{Op: diff.Match, X: "fox", Y: "fox", PosX: 4, PosY: 4},
+			},
+		},
+		{
+			name: "punctuation",
+			x:    "p->next_node",
+			y:    "p->prev_node",
+			want: []diff.Edit[string]{
+				{Op: diff.Match, X: "p", Y: "p", PosX: 0, PosY: 0},
+				{Op: diff.Match, X: "-", Y: "-", PosX: 1, PosY: 1},
+				{Op: diff.Match, X: ">", Y: ">", PosX: 2, PosY: 2},
+				{Op: diff.Delete, X: "next_node", PosX: 3, PosY: -1},
+				{Op: diff.Insert, Y: "prev_node", PosX: -1, PosY: 3},
+			},
+		},
+		{
+			name: "unicode",
+			x:    "Grüße, Welt",
+			y:    "Grüße, 世界",
+			want: []diff.Edit[string]{
+				{Op: diff.Match, X: "Grüße", Y: "Grüße", PosX: 0, PosY: 0},
+				{Op: diff.Match, X: ",", Y: ",", PosX: 1, PosY: 1},
+				{Op: diff.Match, X: " ", Y: " ", PosX: 2, PosY: 2},
+				{Op: diff.Delete, X: "Welt", PosX: 3, PosY: -1},
+				{Op: diff.Insert, Y: "世界", PosX: -1, PosY: 3},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WordDiff(tt.x, tt.y, nil)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("WordDiff(...) result is different (-want, +got):\n%s", diff)
+			}
+
+			gotBytes := WordDiff([]byte(tt.x), []byte(tt.y), nil)
+			if diff := cmp.Diff(tt.want, toStringEdits(gotBytes)); diff != "" {
+				t.Errorf("WordDiff[[]byte](...) result is different (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// arrowTokenizer is like the default tokenizer, but keeps "->" together.
+type arrowTokenizer struct{}
+
+func (arrowTokenizer) Tokenize(line string) []string {
+	var tokens []string
+	for i, part := range strings.Split(line, "->") {
+		if i > 0 {
+			tokens = append(tokens, "->")
+		}
+		tokens = append(tokens, wordTokenizer[string]{}.Tokenize(part)...)
+	}
+	return tokens
+}
+
+func TestWordDiffTokenizer(t *testing.T) {
+	x := "a->b - c"
+	y := "a-b -> c"
+	want := []diff.Edit[string]{
+		{Op: diff.Match, X: "a", Y: "a", PosX: 0, PosY: 0},
+		{Op: diff.Delete, X: "->", PosX: 1, PosY: -1},
+		{Op: diff.Insert, Y: "-", PosX: -1, PosY: 1},
+		{Op: diff.Match, X: "b", Y: "b", PosX: 2, PosY: 2},
+		{Op: diff.Match, X: " ", Y: " ", PosX: 3, PosY: 3},
+		{Op: diff.Delete, X: "-", PosX: 4, PosY: -1},
+		{Op: diff.Insert, Y: "->", PosX: -1, PosY: 4},
+		{Op: diff.Match, X: " ", Y: " ", PosX: 5, PosY: 5},
+		{Op: diff.Match, X: "c", Y: "c", PosX: 6, PosY: 6},
+	}
+	got := WordDiff(x, y, arrowTokenizer{})
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("WordDiff(...) result is different (-want, +got):\n%s", diff)
+	}
+}
+
+func TestWordTokenizer(t *testing.T) {
+	for _, line := range []string{"", "a", "  ", "foo(bar, baz) -> qux\n", "x\t\ty", "ünïcödé 世界!", "\xff\xfeab"} {
+		tokens := wordTokenizer[string]{}.Tokenize(line)
+		if got := strings.Join(tokens, ""); got != line {
+			t.Errorf("Tokenize(%q) = %q, concatenation is %q, want %q", line, tokens, got, line)
+		}
+		for _, tok := range tokens {
+			if tok == "" {
+				t.Errorf("Tokenize(%q) = %q, contains an empty token", line, tokens)
+			}
+		}
+	}
+}
+
+func toStringEdits(edits []diff.Edit[[]byte]) []diff.Edit[string] {
+	if edits == nil {
+		return nil
+	}
+	out := make([]diff.Edit[string], len(edits))
+	for i, e := range edits {
+		out[i] = diff.Edit[string]{Op: e.Op, PosX: e.PosX, PosY: e.PosY, X: string(e.X), Y: string(e.Y)}
+	}
+	return out
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"bufio"
+	"io"
+	"iter"
+
+	"znkr.io/diff/internal/byteview"
+	"znkr.io/diff/internal/config"
+)
+
+// WriteUnified is like [Unified], but writes the output incrementally to w instead of building it
+// in memory. It returns the first error encountered while writing to w.
+//
+// With [TerminalColors], every line is colored individually and the color is reset before the end
+// of the line. A partially written output therefore never leaves a color sequence open.
+//
+// The following options are supported: [diff.Context], [diff.Minimal], [diff.PreferLongMatches],
+// [diff.Fast], [diff.AutoFast], [diff.Parallel], [diff.PairedOrdering], [IndentHeuristic],
+// [NormalizeUnicode], [IgnoreMatching], [IgnoreComments], [IgnoreReorderedBlocks], [AnchorOn],
+// [FuzzyLines], [BlockContext], [FunctionContext], [MaxHunkLines], [MinHunkChanges],
+// [MaxLineWidth], [SanitizeInvalidUTF8], [ShowWhitespace], [TerminalColors], [OutputNewline],
+// [Labels], [IndexHeader], [HunkStatsInHeader], [BinaryDetection]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func WriteUnified[T string | []byte](w io.Writer, x, y T, opts ...Option) error {
+	cfg := config.FromOptions(opts, config.Algorithm|lineFlags|hunkFlags|renderFlags|config.BinaryDetection)
+	if binaryInputs(x, y, &cfg) {
+		if len(binaryHunks(x, y)) == 0 {
+			return nil
+		}
+		_, err := io.WriteString(w, binaryMessage(&cfg))
+		return err
+	}
+
+	return writeUnified(w, diffLines(x, y, cfg), cfg)
+}
+
+// WriteUnifiedStreaming is like [WriteUnified], but takes the lines of x and y as sequences, e.g.
+// records of a JSON lines stream. Like [UnifiedLines], every element is treated as one line,
+// including its newline character if it has one, and the lines are neither split nor joined. If
+// the last line of x or y doesn't end in a newline, it's marked with "\ No newline at end of file".
+// All other lines should end in a newline, like the lines yielded by [strings.Lines] or
+// [bytes.Lines]. Lines that don't are still compared as they are, but are written as if they ended
+// in a newline.
+//
+// The output is written hunk by hunk as soon as it's computed. However, the diff algorithm needs
+// random access to all lines, so both sequences are collected into memory before they are
+// compared. Lines of type []byte are copied, the sequences may therefore reuse their buffers.
+//
+// The following options are supported: [diff.Context], [diff.Minimal], [diff.PreferLongMatches],
+// [diff.Fast], [diff.AutoFast], [diff.Parallel], [diff.PairedOrdering], [IndentHeuristic],
+// [NormalizeUnicode], [IgnoreMatching], [IgnoreComments], [IgnoreReorderedBlocks], [AnchorOn],
+// [FuzzyLines], [BlockContext], [FunctionContext], [MaxHunkLines], [MinHunkChanges],
+// [MaxLineWidth], [SanitizeInvalidUTF8], [ShowWhitespace], [TerminalColors], [OutputNewline],
+// [Labels], [IndexHeader], [HunkStatsInHeader]
+//
+// Important: The output is not guaranteed to be stable and may change with minor version upgrades.
+// DO NOT rely on the output being stable.
+func WriteUnifiedStreaming[T string | []byte](w io.Writer, x, y iter.Seq[T], opts ...Option) error {
+	cfg := config.FromOptions(opts, config.Algorithm|lineFlags|hunkFlags|renderFlags)
+	var d lineDiff
+	d.x, d.xMissingNewline = collectLines(x)
+	d.y, d.yMissingNewline = collectLines(y)
+	d.compare(cfg)
+	return writeUnified(w, d, cfg)
+}
+
+// collectLines collects the lines in seq and returns them together with the index of the last line
+// if it's missing a newline, or -1 otherwise.
+func collectLines[T string | []byte](seq iter.Seq[T]) ([]byteview.ByteView, int) {
+	var lines []byteview.ByteView
+	for line := range seq {
+		// Converting to string copies []byte lines, seq may reuse the buffer.
+		lines = append(lines, byteview.From(string(line)))
+	}
+	if n := len(lines); n > 0 && !hasNewline(lines[n-1]) {
+		return lines, n - 1
+	}
+	return lines, -1
+}
+
+// writeUnified writes d to w in the unified format, flushing the output after every hunk.
+func writeUnified(w io.Writer, d lineDiff, cfg config.Config) error {
+	r := newUnifiedRenderer(&d, &cfg)
+	r.colorLines = true
+	bw := bufio.NewWriter(w)
+	i := 0
+	for h := range d.hunkSeq(cfg) {
+		r.writeHunk(bw, i, h)
+		i++
+		// Flush after every hunk to stream the output and to stop early if writing fails.
+		if err := bw.Flush(); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
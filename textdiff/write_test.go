@@ -0,0 +1,224 @@
+// Copyright 2025 Florian Zenker (flo@znkr.io)
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package textdiff
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteUnified(t *testing.T) {
+	for _, tt := range parseTests(t) {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, st := range tt.subtests {
+				if st.render != nil {
+					continue // only Unified output is supported
+				}
+				t.Run(st.name, func(t *testing.T) {
+					var got bytes.Buffer
+					if err := WriteUnified(&got, tt.x, tt.y, st.opts...); err != nil {
+						t.Fatalf("WriteUnified(...) failed: %v", err)
+					}
+					if !bytes.Equal(st.want, got.Bytes()) {
+						t.Errorf("WriteUnified(...) result is different from Unified(...) [-want,+got]:\n%s", cmp.Diff(string(st.want), got.String()))
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestWriteUnifiedColors(t *testing.T) {
+	x := "a\nb\nc\nd"
+	y := "a\nB\nc\nD"
+	want := "\033[36m@@ -1,4 +1,4 @@\033[m\n" +
+		" a\n" +
+		"\033[31m-b\033[m\n" +
+		"\033[32m+B\033[m\n" +
+		" c\n" +
+		"\033[31m-d\033[m\n" +
+		"\\ No newline at end of file\n" +
+		"\033[32m+D\033[m\n" +
+		"\\ No newline at end of file\n"
+
+	var got strings.Builder
+	if err := WriteUnified(&got, x, y, TerminalColors()); err != nil {
+		t.Fatalf("WriteUnified(...) failed: %v", err)
+	}
+	if diff := cmp.Diff(want, got.String()); diff != "" {
+		t.Errorf("WriteUnified(...) result is different [-want,+got]:\n%s", diff)
+	}
+
+	// Every line brackets its own color.
+	for line := range strings.Lines(got.String()) {
+		if strings.Contains(line, "\033[") && !strings.HasSuffix(line, "\033[m\n") {
+			t.Errorf("line %q leaves a color sequence open", line)
+		}
+	}
+}
+
+func TestWriteUnifiedSanitizeInvalidUTF8(t *testing.T) {
+	x := "a\n\xffb\nc\xe2\n"
+	y := "a\n\xfeb\nc\xe2\n"
+	want := Unified(x, y, SanitizeInvalidUTF8())
+
+	var got strings.Builder
+	if err := WriteUnified(&got, x, y, SanitizeInvalidUTF8()); err != nil {
+		t.Fatalf("WriteUnified(...) failed: %v", err)
+	}
+	if diff := cmp.Diff(want, got.String()); diff != "" {
+		t.Errorf("WriteUnified(...) result is different from Unified(...) [-want,+got]:\n%s", diff)
+	}
+}
+
+type failingWriter struct {
+	n int // Number of bytes to accept before failing.
+}
+
+var errWrite = errors.New("write failed")
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		n := w.n
+		w.n = 0
+		return n, errWrite
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func TestWriteUnifiedError(t *testing.T) {
+	x := strings.Repeat("x\n", 10_000)
+	y := strings.Repeat("y\n", 10_000)
+	if err := WriteUnified(&failingWriter{n: 100}, x, y); !errors.Is(err, errWrite) {
+		t.Errorf("WriteUnified(...) = %v, want %v", err, errWrite)
+	}
+}
+
+func TestWriteUnifiedStreaming(t *testing.T) {
+	// Synthetic JSON lines logs with a few changed, deleted, and inserted records.
+	const n = 100_000
+	record := func(i int, status string) string {
+		return fmt.Sprintf(`{"id":%d,"service":"api","status":%q,"latency_ms":%d}`+"\n", i, status, i%97)
+	}
+	var xs iter.Seq[string] = func(yield func(string) bool) {
+		for i := range n {
+			if !yield(record(i, "ok")) {
+				return
+			}
+		}
+	}
+	var ys iter.Seq[string] = func(yield func(string) bool) {
+		for i := range n {
+			switch {
+			case i%10_000 == 1234:
+				continue // deleted
+			case i%25_000 == 42:
+				if !yield(record(i, "error")) {
+					return
+				}
+			default:
+				if !yield(record(i, "ok")) {
+					return
+				}
+			}
+			if i == n/2 && !yield(record(-1, "inserted")) {
+				return
+			}
+		}
+	}
+
+	var got bytes.Buffer
+	if err := WriteUnifiedStreaming(&got, xs, ys); err != nil {
+		t.Fatalf("WriteUnifiedStreaming(...) failed: %v", err)
+	}
+	x, y := slices.Collect(xs), slices.Collect(ys)
+	want := Unified(strings.Join(x, ""), strings.Join(y, ""))
+	if want == "" {
+		t.Fatal("Unified(...) is empty, test inputs are broken")
+	}
+	if diff := cmp.Diff(want, got.String()); diff != "" {
+		t.Errorf("WriteUnifiedStreaming(...) result is different from Unified(...) [-want,+got]:\n%s", diff)
+	}
+}
+
+func TestWriteUnifiedStreamingLines(t *testing.T) {
+	tests := []struct {
+		name string
+		x, y [][]byte
+		want string
+	}{
+		{
+			name: "missing-newline-at-end",
+			x:    [][]byte{[]byte("a\n"), []byte("b\n"), []byte("c")},
+			y:    [][]byte{[]byte("a\n"), []byte("B\n"), []byte("c\n")},
+			want: "@@ -1,3 +1,3 @@\n a\n-b\n-c\n\\ No newline at end of file\n+B\n+c\n",
+		},
+		{
+			// Lines are compared as they are, they are never joined.
+			name: "missing-newline-in-between",
+			x:    [][]byte{[]byte("a\n"), []byte("b"), []byte("c\n")},
+			y:    [][]byte{[]byte("a\n"), []byte("b\n"), []byte("c\n")},
+			want: "@@ -1,3 +1,3 @@\n a\n-b\n+b\n c\n",
+		},
+		{
+			name: "empty-lines",
+			x:    [][]byte{[]byte("a\n"), []byte(""), []byte("b\n")},
+			y:    [][]byte{[]byte("a\n"), []byte("b\n")},
+			want: "@@ -1,3 +1,2 @@\n a\n-\n b\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got strings.Builder
+			if err := WriteUnifiedStreaming(&got, slices.Values(tt.x), slices.Values(tt.y)); err != nil {
+				t.Fatalf("WriteUnifiedStreaming(...) failed: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got.String()); diff != "" {
+				t.Errorf("WriteUnifiedStreaming(...) result is different [-want,+got]:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWriteUnifiedStreamingReusedBuffer(t *testing.T) {
+	// The sequences reuse their buffer for every line, like bufio.Scanner does.
+	lines := func(s string) iter.Seq[[]byte] {
+		return func(yield func([]byte) bool) {
+			var buf []byte
+			for line := range strings.Lines(s) {
+				buf = append(buf[:0], line...)
+				if !yield(buf) {
+					return
+				}
+			}
+		}
+	}
+	x, y := "a\nb\nc\n", "a\nB\nc\n"
+	var got strings.Builder
+	if err := WriteUnifiedStreaming(&got, lines(x), lines(y)); err != nil {
+		t.Fatalf("WriteUnifiedStreaming(...) failed: %v", err)
+	}
+	if want := Unified(x, y); got.String() != want {
+		t.Errorf("WriteUnifiedStreaming(...) = %q, want %q", got.String(), want)
+	}
+}